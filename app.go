@@ -13,11 +13,15 @@ import (
 	"runtime"
 	"strings"
 
+	"Kairo/internal/ai"
+	"Kairo/internal/api"
 	"Kairo/internal/category"
 	"Kairo/internal/config"
 	"Kairo/internal/db"
 	"Kairo/internal/db/schema"
 	"Kairo/internal/deps"
+	"Kairo/internal/events"
+	"Kairo/internal/jobs"
 	"Kairo/internal/publish"
 	"Kairo/internal/rss"
 	"Kairo/internal/task"
@@ -40,6 +44,9 @@ type App struct {
 	videoManager    *video.Manager
 	categoryManager *category.Manager
 	publishManager  *publish.PublishManager
+	apiServer       *api.Server
+	jobQueue        *jobs.Queue
+	bus             *events.Bus
 	db              *gorm.DB
 }
 
@@ -76,16 +83,21 @@ func (a *App) startup(ctx context.Context) {
 
 	a.db = db.NewDatabase()
 
+	a.bus = events.NewBus(0)
+	events.ForwardToWails(ctx, a.bus)
+
 	dep := deps.NewManager(ctx, readEmbedded)
 	dep.EnsureYtDlp()
 	dep.EnsureFFmpeg()
 
-	a.taskManager = task.NewManager(ctx, a.db, dep)
+	a.taskManager = task.NewManager(ctx, a.db, dep, a.bus)
+	a.taskManager.StartPlaylistSync()
 	a.depsManager = dep
 
-	a.videoManager = video.NewManager(ctx, a.db, dep)
+	a.jobQueue = jobs.NewQueue(ctx, a.db, a.bus)
+	a.videoManager = video.NewManager(ctx, a.db, dep, a.bus, a.jobQueue)
 
-	a.rssManager = rss.NewManager(ctx, a.db)
+	a.rssManager = rss.NewManager(ctx, a.db, dep)
 	a.rssManager.Start()
 
 	a.categoryManager = category.NewManager(ctx, a.db)
@@ -93,6 +105,9 @@ func (a *App) startup(ctx context.Context) {
 	a.publishManager = publish.NewPublishManager(ctx, a.db)
 	a.publishManager.StartAutoPublish()
 
+	// Publish step of category recipes
+	a.videoManager.PublishHighlights = a.publishManager.QueueHighlights
+
 	// Wire up Task Manager callback to RSS Manager and Video Manager
 	a.taskManager.OnTaskComplete = func(task *schema.Task) {
 		// Update RSS item status when task completes
@@ -100,8 +115,10 @@ func (a *App) startup(ctx context.Context) {
 		// This assumes 1-to-1 mapping or at least that the URL is unique enough
 		_ = a.rssManager.SetItemDownloadedByLink(task.URL, true)
 
-		// Create video entry from task, but not for audio-only downloads
-		if task.Format != "audio" {
+		// Create video entry from task, but not for audio-only downloads.
+		// Podcast enclosures are the exception so they can be transcribed
+		// and summarized.
+		if task.Format != "audio" || task.EnclosureURL != "" {
 			_ = a.videoManager.CreateFromTask(task)
 		}
 	}
@@ -113,19 +130,54 @@ func (a *App) startup(ctx context.Context) {
 	// Wire up RSS Auto Download
 	a.rssManager.OnAutoDownload = func(item schema.FeedItem, feed schema.Feed) {
 		input := schema.AddRSSTaskInput{
-			FeedURL:       feed.URL,
-			FeedTitle:     feed.Title,
-			FeedThumbnail: feed.Thumbnail,
-			ItemURL:       item.Link,
-			ItemTitle:     item.Title,
-			Dir:           feed.CustomDir,
-			CategoryID:    feed.CategoryID,
+			FeedURL:          feed.URL,
+			FeedTitle:        feed.Title,
+			FeedThumbnail:    feed.Thumbnail,
+			ItemURL:          item.Link,
+			ItemTitle:        item.Title,
+			ItemThumbnail:    item.Thumbnail,
+			Dir:              feed.CustomDir,
+			CategoryID:       feed.CategoryID,
+			FilenameTemplate: feed.FilenameTemplate,
+			FeedID:           feed.ID,
+			FeedItemID:       item.ID,
+			ItemDescription:  item.Description,
+			FeedTags:         feed.Tags,
+			ItemAuthor:       item.Author,
+			ItemPubDate:      item.PubDate,
+		}
+		if feed.Podcast && item.EnclosureURL != "" {
+			input.EnclosureURL = item.EnclosureURL
+			input.EnclosureType = item.EnclosureType
+			input.EnclosureLength = item.EnclosureLength
 		}
 		_, err := a.taskManager.AddRSSTask(input)
 		if err != nil {
 			fmt.Printf("Failed to auto-add RSS task: %v\n", err)
 		}
 	}
+
+	// Local HTTP API (opt-in via settings)
+	a.apiServer = api.NewServer(ctx, api.Options{
+		Deps:            a.depsManager,
+		TaskManager:     a.taskManager,
+		VideoManager:    a.videoManager,
+		RSSManager:      a.rssManager,
+		CategoryManager: a.categoryManager,
+		PublishManager:  a.publishManager,
+		Jobs:            a.jobQueue,
+		Bus:             a.bus,
+	})
+	if err := a.apiServer.Reload(); err != nil {
+		fmt.Printf("Failed to start local API: %v\n", err)
+	}
+}
+
+// shutdown is called when the app is closing
+func (a *App) shutdown(ctx context.Context) {
+	if a.apiServer != nil {
+		a.apiServer.Stop()
+	}
 }
 
 func (a *App) ChooseDirectory() (string, error) {
@@ -157,6 +209,11 @@ func (a *App) GetVideoSubtitles(videoID string) ([]schema.VideoSubtitle, error)
 	return a.videoManager.GetVideoSubtitles(videoID)
 }
 
+// GetVideoPipeline returns the recipe steps recorded for a video
+func (a *App) GetVideoPipeline(videoID string) ([]schema.VideoPipelineStep, error) {
+	return a.videoManager.GetVideoPipeline(videoID)
+}
+
 // ImportSubtitle imports a subtitle file for a video
 func (a *App) ImportSubtitle(videoID string, filePath string, language string) (*schema.VideoSubtitle, error) {
 	return a.videoManager.ImportSubtitle(videoID, filePath, language)
@@ -199,8 +256,9 @@ func (a *App) AddVideoToLibrary(taskID string) (bool, error) {
 	if !task.FileExists {
 		return false, fmt.Errorf("file not found")
 	}
-	// Audio-only downloads cannot be added to video library
-	if task.Format == "audio" {
+	// Audio-only downloads cannot be added to video library, except podcast
+	// enclosures
+	if task.Format == "audio" && task.EnclosureURL == "" {
 		return false, fmt.Errorf("audio-only tasks cannot be added to video library")
 	}
 
@@ -227,6 +285,32 @@ func (a *App) AnalyzeVideo(id string) error {
 	return a.videoManager.AnalyzeVideo(id)
 }
 
+// GetAIUsage returns the tokens spent on AI calls over the last days,
+// summed by day and by feature
+func (a *App) GetAIUsage(days int) (*schema.AIUsageReport, error) {
+	return a.videoManager.GetAIUsage(days)
+}
+
+// ListJobs returns background jobs matching the filter
+func (a *App) ListJobs(filter schema.JobFilter) (*schema.JobListResponse, error) {
+	return a.jobQueue.ListJobs(filter)
+}
+
+// GetJob returns a background job by ID
+func (a *App) GetJob(id string) (*schema.Job, error) {
+	return a.jobQueue.GetJob(id)
+}
+
+// CancelJob stops a pending or running background job
+func (a *App) CancelJob(id string) error {
+	return a.jobQueue.Cancel(id)
+}
+
+// RerunJob runs a finished background job again
+func (a *App) RerunJob(id string) error {
+	return a.jobQueue.Rerun(id)
+}
+
 // GetVideoHighlights returns highlights for a video
 func (a *App) GetVideoHighlights(videoID string) ([]schema.VideoHighlight, error) {
 	return a.videoManager.GetHighlights(videoID)
@@ -273,7 +357,7 @@ func (a *App) ClipVideo(videoID string, highlightID string, start, end string) e
 	if err == nil {
 		highlights, _ := a.videoManager.GetHighlights(videoID)
 		updatedVideo.Highlights = highlights
-		wailsRuntime.EventsEmit(a.ctx, "video:ai_status", map[string]interface{}{
+		a.bus.Publish(events.VideoAIStatus, map[string]interface{}{
 			"id":         updatedVideo.ID,
 			"status":     updatedVideo.Status,
 			"summary":    updatedVideo.Summary,
@@ -414,6 +498,46 @@ func (a *App) RetryTask(id string) {
 	a.taskManager.RetryTask(id)
 }
 
+func (a *App) GetTaskQueue() ([]schema.Task, error) {
+	return a.taskManager.GetQueue()
+}
+
+func (a *App) GetQueueStatus() schema.QueueStatus {
+	return a.taskManager.GetQueueStatus()
+}
+
+func (a *App) SyncPlaylist(id string) (int, error) {
+	return a.taskManager.SyncPlaylist(id)
+}
+
+func (a *App) SetPlaylistSyncInterval(id string, minutes int) error {
+	return a.taskManager.SetPlaylistSyncInterval(id, minutes)
+}
+
+func (a *App) PauseGroup(id string) error {
+	return a.taskManager.PauseGroup(id)
+}
+
+func (a *App) ResumeGroup(id string) error {
+	return a.taskManager.ResumeGroup(id)
+}
+
+func (a *App) RetryFailedInGroup(id string) error {
+	return a.taskManager.RetryFailedInGroup(id)
+}
+
+func (a *App) CancelGroup(id string) error {
+	return a.taskManager.CancelGroup(id)
+}
+
+func (a *App) MoveTask(id string, move schema.TaskMove) error {
+	return a.taskManager.MoveTask(id, move)
+}
+
+func (a *App) SetTaskPriority(id string, priority int) error {
+	return a.taskManager.SetTaskPriority(id, priority)
+}
+
 func (a *App) OpenTaskDir(id string) {
 	a.taskManager.OpenTaskDir(id)
 }
@@ -425,6 +549,26 @@ func (a *App) GetTaskLogs(id string) ([]string, error) {
 // UpdateSettings updates the application settings
 func (a *App) UpdateSettings(settings config.AppSettings) {
 	config.UpdateSettings(settings)
+	if a.apiServer != nil {
+		if err := a.apiServer.Reload(); err != nil {
+			fmt.Printf("Failed to reload local API: %v\n", err)
+		}
+	}
+}
+
+// ListAIModels returns the models served by the provider of cfg, used to
+// check AI settings before they are saved.
+func (a *App) ListAIModels(cfg config.AIConfig) ([]string, error) {
+	return ai.NewManager(a.ctx, nil, nil).ListModels(cfg)
+}
+
+// CheckLocalWhisper returns the executable of a local ASR engine, installing
+// it where possible, so the settings can tell whether transcription works.
+func (a *App) CheckLocalWhisper(engine string) (string, error) {
+	if !deps.IsWhisperEngine(engine) {
+		return "", fmt.Errorf("unknown whisper engine %q", engine)
+	}
+	return a.depsManager.GetWhisperPath(engine)
 }
 
 // GetSettings returns the current application settings
@@ -432,6 +576,14 @@ func (a *App) GetSettings() config.AppSettings {
 	return config.GetSettings()
 }
 
+func (a *App) GetSiteProfiles() []config.SiteProfile {
+	return config.GetSiteProfiles()
+}
+
+func (a *App) SaveSiteProfiles(profiles []config.SiteProfile) error {
+	return config.SaveSiteProfiles(profiles)
+}
+
 func (a *App) GetCategories() ([]schema.Category, error) {
 	return a.categoryManager.GetCategories()
 }
@@ -448,6 +600,23 @@ func (a *App) DeleteCategory(id string) error {
 	return a.categoryManager.DeleteCategory(id)
 }
 
+// GetCategoryRecipe returns the processing recipe of a category, nil when it uses the default
+func (a *App) GetCategoryRecipe(categoryID string) (*schema.Recipe, error) {
+	return a.categoryManager.GetRecipe(categoryID)
+}
+
+func (a *App) ListCategoryRecipes() ([]schema.Recipe, error) {
+	return a.categoryManager.ListRecipes()
+}
+
+func (a *App) SaveCategoryRecipe(req schema.SaveRecipeRequest) (*schema.Recipe, error) {
+	return a.categoryManager.SaveRecipe(req)
+}
+
+func (a *App) DeleteCategoryRecipe(categoryID string) error {
+	return a.categoryManager.DeleteRecipe(categoryID)
+}
+
 // RSS Methods
 func (a *App) AddFeed(input schema.AddRSSFeedInput) (*schema.Feed, error) {
 	return a.rssManager.AddFeed(input)
@@ -457,6 +626,16 @@ func (a *App) GetRSSFeeds() ([]schema.Feed, error) {
 	return a.rssManager.GetFeeds()
 }
 
+func (a *App) GetUnhealthyRSSFeeds() ([]schema.Feed, error) {
+	return a.rssManager.GetUnhealthyFeeds()
+}
+
+// PruneRSSItems applies the item retention limits now instead of waiting
+// for the scheduled cleanup.
+func (a *App) PruneRSSItems() (*schema.RSSPruneResult, error) {
+	return a.rssManager.PruneItems()
+}
+
 func (a *App) DeleteRSSFeed(id string) error {
 	return a.rssManager.DeleteFeed(id)
 }
@@ -473,6 +652,36 @@ func (a *App) MarkRSSItemRead(itemID string) error {
 	return a.rssManager.MarkItemRead(itemID)
 }
 
+func (a *App) ImportOPML(filePath string, mode schema.OPMLFolderMode) (*schema.OPMLImportResult, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return a.rssManager.ImportOPML(data, mode)
+}
+
+// ExportOPML asks for a destination and writes all subscriptions there, it
+// returns an empty path when the dialog was cancelled.
+func (a *App) ExportOPML() (string, error) {
+	file, err := wailsRuntime.SaveFileDialog(a.ctx, wailsRuntime.SaveDialogOptions{
+		DefaultFilename: "kairo.opml",
+		Filters: []wailsRuntime.FileFilter{
+			{DisplayName: "OPML (*.opml)", Pattern: "*.opml"},
+		},
+	})
+	if err != nil || file == "" {
+		return "", err
+	}
+	data, err := a.rssManager.ExportOPML()
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
 func (a *App) SetRSSFeedEnabled(feedID string, enabled bool) error {
 	return a.rssManager.SetFeedEnabled(feedID, enabled)
 }
@@ -481,6 +690,12 @@ func (a *App) UpdateRSSFeed(feed schema.Feed) error {
 	return a.rssManager.UpdateFeed(feed)
 }
 
+// PreviewRSSFilters shows which stored items of a feed the given filter
+// expression would auto-download
+func (a *App) PreviewRSSFilters(feedID string, filters string) ([]schema.RSSFilterPreview, error) {
+	return a.rssManager.PreviewFilters(feedID, filters)
+}
+
 func (a *App) SetRSSItemQueued(itemID string, queued bool) error {
 	return a.rssManager.SetItemQueued(itemID, queued)
 }
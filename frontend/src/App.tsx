@@ -130,8 +130,8 @@ function App() {
         id: string;
         progress: number;
         total_bytes?: number;
-        speed?: string;
-        eta?: string;
+        speed_bps?: number;
+        eta_seconds?: number;
       }) => {
         updateTaskProgress(data);
       }
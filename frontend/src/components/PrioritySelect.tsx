@@ -0,0 +1,42 @@
+import { Select } from 'antd';
+import { useTranslation } from 'react-i18next';
+import { TaskPriority } from '@/data/variables';
+
+export interface PrioritySelectProps {
+  value?: TaskPriority;
+  onChange?: (priority: TaskPriority) => void;
+  className?: string;
+}
+
+export const priorityKeys: Record<TaskPriority, string> = {
+  [TaskPriority.Low]: 'low',
+  [TaskPriority.Normal]: 'normal',
+  [TaskPriority.High]: 'high',
+  [TaskPriority.Urgent]: 'urgent',
+};
+
+export const priorityOrder = [
+  TaskPriority.Urgent,
+  TaskPriority.High,
+  TaskPriority.Normal,
+  TaskPriority.Low,
+];
+
+const PrioritySelect = ({ value = TaskPriority.Normal, onChange, className }: PrioritySelectProps) => {
+  const { t } = useTranslation();
+
+  return (
+    <Select
+      value={value}
+      onChange={onChange}
+      className={className}
+      style={{ width: '100%' }}
+      options={priorityOrder.map((priority) => ({
+        value: priority,
+        label: t(`tasks.priority.${priorityKeys[priority]}`),
+      }))}
+    />
+  );
+};
+
+export default PrioritySelect;
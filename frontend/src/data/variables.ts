@@ -8,6 +8,23 @@ export enum TaskStatus {
   Completed = 'completed',
   TrimFailed = 'trim_failed',
   Error = 'error',
+  Cancelled = 'cancelled',
+  // Only set on playlist and RSS parents, some children completed and some failed
+  PartialFailed = 'partial_failed',
+}
+
+export enum TaskPriority {
+  Low = -1,
+  Normal = 0,
+  High = 1,
+  Urgent = 2,
+}
+
+export enum TaskMove {
+  Up = 'up',
+  Down = 'down',
+  Top = 'top',
+  Bottom = 'bottom',
 }
 
 export enum TrimMode {
@@ -22,6 +39,11 @@ export enum SourceType {
   RSS = 2,
 }
 
+export enum FeedSourceType {
+  RSS = 0,
+  Channel = 1,
+}
+
 export enum RSSItemStatus {
   New = 0,
   Read = 1,
@@ -1,11 +1,14 @@
 import { create } from 'zustand';
-import { Category, CategorySource } from '@/types';
+import { Category, CategorySource, Recipe, RecipeStep } from '@/types';
 import { schema } from '@root/wailsjs/go/models';
 import {
   GetCategories,
   CreateCategory,
   UpdateCategory,
   DeleteCategory,
+  GetCategoryRecipe,
+  SaveCategoryRecipe,
+  DeleteCategoryRecipe,
 } from '@root/wailsjs/go/main/App';
 
 interface CategoryState {
@@ -15,6 +18,9 @@ interface CategoryState {
   createCategory: (name: string, prompt: string) => Promise<Category | null>;
   updateCategory: (id: string, name: string, prompt: string) => Promise<Category | null>;
   deleteCategory: (id: string) => Promise<void>;
+  fetchRecipe: (categoryId: string) => Promise<Recipe | null>;
+  saveRecipe: (categoryId: string, steps: RecipeStep[], isEnabled: boolean) => Promise<Recipe>;
+  deleteRecipe: (categoryId: string) => Promise<void>;
 }
 
 const normalizeCategory = (category: schema.Category): Category => ({
@@ -83,4 +89,29 @@ export const useCategoryStore = create<CategoryState>((set) => ({
       console.error('Failed to delete category:', error);
     }
   },
+
+  fetchRecipe: async (categoryId: string) => {
+    try {
+      const recipe = await GetCategoryRecipe(categoryId);
+      return recipe ? (recipe as unknown as Recipe) : null;
+    } catch (error) {
+      console.error('Failed to fetch recipe:', error);
+      return null;
+    }
+  },
+
+  saveRecipe: async (categoryId: string, steps: RecipeStep[], isEnabled: boolean) => {
+    const saved = await SaveCategoryRecipe(
+      new schema.SaveRecipeRequest({
+        category_id: categoryId,
+        steps,
+        is_enabled: isEnabled,
+      })
+    );
+    return saved as unknown as Recipe;
+  },
+
+  deleteRecipe: async (categoryId: string) => {
+    await DeleteCategoryRecipe(categoryId);
+  },
 }));
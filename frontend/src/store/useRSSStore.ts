@@ -1,6 +1,6 @@
 import { create } from 'zustand';
 import { RSSFeed, RSSItem } from '@/types';
-import { RSSItemStatus } from '@/data/variables';
+import { FeedSourceType, RSSItemStatus } from '@/data/variables';
 
 import {
   AddFeed,
@@ -16,22 +16,33 @@ import {
 
 interface AddFeedInput {
   url: string;
+  source_type: FeedSourceType;
+  podcast: boolean;
   custom_dir: string;
   download_latest: boolean;
   filters: string;
   tags: string;
   filename_template: string;
   category_id?: string;
+  refresh_interval: number;
+  refresh_cron: string;
+  retention_items: number;
+  retention_days: number;
 }
 
 interface UpdateFeedInput {
   id: string;
+  podcast: boolean;
   custom_dir: string;
   download_latest: boolean;
   filters: string;
   tags: string;
   filename_template: string;
   category_id?: string;
+  refresh_interval: number;
+  refresh_cron: string;
+  retention_items: number;
+  retention_days: number;
 }
 
 interface RSSState {
@@ -116,11 +127,16 @@ export const useRSSStore = create<RSSState>((set, get) => ({
       const updatedFeed: RSSFeed = {
         ...existingFeed,
         custom_dir: input.custom_dir,
+        podcast: input.podcast,
         download_latest: input.download_latest,
         filters: input.filters,
         tags: input.tags,
         filename_template: input.filename_template,
         category_id: input.category_id || '',
+        refresh_interval: input.refresh_interval,
+        refresh_cron: input.refresh_cron,
+        retention_items: input.retention_items,
+        retention_days: input.retention_days,
       };
 
       await UpdateRSSFeed(updatedFeed);
@@ -156,6 +172,8 @@ export const useRSSStore = create<RSSState>((set, get) => ({
       }
     } catch (error) {
       console.error('Failed to refresh feed:', error);
+      // Pick up the recorded failure
+      await get().fetchFeeds();
     }
   },
 
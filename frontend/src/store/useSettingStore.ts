@@ -20,6 +20,7 @@ export interface AIConfig {
   apiKey: string;
   modelName: string;
   prompt: string;
+  chunkSize: number;
 }
 
 export interface AppSettings {
@@ -56,6 +57,7 @@ const DEFAULT_AI_CONFIG: AIConfig = {
   apiKey: '',
   modelName: 'gpt-3.5-turbo',
   prompt: '',
+  chunkSize: 0,
 };
 
 const DEFAULT_WHISPER_AI_CONFIG: AIConfig = {
@@ -65,6 +67,7 @@ const DEFAULT_WHISPER_AI_CONFIG: AIConfig = {
   apiKey: '',
   modelName: 'whisper-1',
   prompt: '',
+  chunkSize: 0,
 };
 
 const DEFAULT_TRANSLATE_AI_CONFIG: AIConfig = {
@@ -74,6 +77,7 @@ const DEFAULT_TRANSLATE_AI_CONFIG: AIConfig = {
   apiKey: '',
   modelName: 'gpt-3.5-turbo',
   prompt: '',
+  chunkSize: 0,
 };
 
 const DEFAULT_SETTINGS: AppSettings = {
@@ -143,6 +147,7 @@ const normalizeSettings = (value: Partial<AppSettings>): AppSettings => {
       apiKey: typeof value.apiKey === 'string' ? value.apiKey : '',
       modelName: typeof value.modelName === 'string' ? value.modelName : 'gpt-3.5-turbo',
       prompt: typeof value.prompt === 'string' ? value.prompt : '',
+      chunkSize: typeof value.chunkSize === 'number' ? value.chunkSize : 0,
     };
   };
 
@@ -155,6 +160,7 @@ const normalizeSettings = (value: Partial<AppSettings>): AppSettings => {
       apiKey: typeof value.apiKey === 'string' ? value.apiKey : '',
       modelName: typeof value.modelName === 'string' ? value.modelName : 'whisper-1',
       prompt: typeof value.prompt === 'string' ? value.prompt : '',
+      chunkSize: typeof value.chunkSize === 'number' ? value.chunkSize : 0,
     };
   };
 
@@ -167,6 +173,7 @@ const normalizeSettings = (value: Partial<AppSettings>): AppSettings => {
       apiKey: typeof value.apiKey === 'string' ? value.apiKey : '',
       modelName: typeof value.modelName === 'string' ? value.modelName : 'gpt-3.5-turbo',
       prompt: typeof value.prompt === 'string' ? value.prompt : '',
+      chunkSize: typeof value.chunkSize === 'number' ? value.chunkSize : 0,
     };
   };
 
@@ -14,8 +14,8 @@ interface TaskState {
     id: string;
     progress: number;
     total_bytes?: number;
-    speed?: string;
-    eta?: string;
+    speed_bps?: number;
+    eta_seconds?: number;
   }) => void;
   addTaskLog: (taskId: string, message: string, replace?: boolean) => void;
   setTaskLogs: (taskId: string, logs: string[]) => void;
@@ -60,8 +60,8 @@ export const useTaskStore = create<TaskState>((set, get) => ({
             ...task,
             progress: data.progress,
             total_bytes: data.total_bytes,
-            speed: data.speed,
-            eta: data.eta,
+            speed_bps: data.speed_bps,
+            eta_seconds: data.eta_seconds,
           },
         },
       };
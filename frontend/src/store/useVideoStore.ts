@@ -7,7 +7,7 @@ interface VideoState {
   videos: Video[];
   loading: boolean;
   setVideos: (videos: Video[]) => void;
-  fetchVideos: (status?: string, query?: string) => Promise<void>;
+  fetchVideos: (status?: string, query?: string, feedId?: string) => Promise<void>;
   updateVideoStatus: (
     id: string,
     status: string,
@@ -25,10 +25,10 @@ export const useVideoStore = create<VideoState>((set) => ({
 
   setVideos: (videos) => set({ videos }),
 
-  fetchVideos: async (status = 'all', query = '') => {
+  fetchVideos: async (status = 'all', query = '', feedId = '') => {
     set({ loading: true });
     try {
-      const result = await ListVideos(new schema.VideoFilter({ status, query }));
+      const result = await ListVideos(new schema.VideoFilter({ status, query, feed_id: feedId }));
       set({ videos: result || [] });
     } catch (error) {
       console.error('Failed to fetch videos:', error);
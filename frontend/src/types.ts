@@ -1,4 +1,4 @@
-import { TaskStatus, RSSItemStatus } from '@/data/variables';
+import { TaskStatus, RSSItemStatus, FeedSourceType } from '@/data/variables';
 
 export interface TaskFile {
   path: string;
@@ -21,20 +21,80 @@ export interface Task {
   title: string;
   thumbnail: string;
   total_bytes?: number;
-  speed?: string;
-  eta?: string;
+  downloaded_bytes?: number;
+  speed_bps?: number;
+  eta_seconds?: number;
+  fragment_index?: number;
+  fragment_count?: number;
   log_path?: string;
   file_exists?: boolean;
   files?: TaskFile[];
   category_id?: string;
+  priority?: number;
+  queue_order?: number;
+  error_kind?: string;
+  error_message?: string;
+  attempts?: number;
+  next_retry_at?: number;
+  sync_interval?: number;
+  last_synced_at?: number;
+  enclosure_url?: string;
   created_at?: number;
 }
 
+export interface QueueStatus {
+  paused: boolean;
+  reason: string;
+  dir: string;
+  free_bytes: number;
+  required_bytes: number;
+}
+
 export enum CategorySource {
   Builtin = 'builtin',
   Custom = 'custom',
 }
 
+export enum RecipeStepType {
+  Subtitles = 'subtitles',
+  Translate = 'translate',
+  Analyze = 'analyze',
+  Clip = 'clip',
+  Publish = 'publish',
+}
+
+export interface RecipeStep {
+  type: RecipeStepType;
+  skip_asr?: boolean;
+  language?: string;
+  prompt?: string;
+  min_score?: number;
+  account_id?: string;
+}
+
+export interface Recipe {
+  id: string;
+  category_id: string;
+  steps: RecipeStep[];
+  is_enabled: boolean;
+  created_at: number;
+  updated_at: number;
+}
+
+export type PipelineStepStatus = 'pending' | 'running' | 'completed' | 'failed' | 'skipped';
+
+export interface VideoPipelineStep {
+  id: string;
+  video_id: string;
+  position: number;
+  type: RecipeStepType;
+  options: string;
+  status: PipelineStepStatus;
+  error: string;
+  started_at: number;
+  finished_at: number;
+}
+
 export interface Category {
   id: string;
   name: string;
@@ -47,6 +107,8 @@ export interface Category {
 export interface RSSFeed {
   id: string;
   url: string;
+  source_type: FeedSourceType;
+  podcast: boolean;
   title: string;
   description: string;
   thumbnail: string;
@@ -59,6 +121,15 @@ export interface RSSFeed {
   tags: string;
   filename_template: string;
   category_id: string;
+  refresh_interval: number;
+  refresh_cron: string;
+  next_refresh_at: number;
+  retention_items: number;
+  retention_days: number;
+  last_success_at: number;
+  last_error: string;
+  last_error_at: number;
+  failure_count: number;
   created_at: number;
   updated_at: number;
 }
@@ -72,6 +143,10 @@ export interface RSSItem {
   pub_date: number;
   status: RSSItemStatus;
   thumbnail: string;
+  author: string;
+  enclosure_url: string;
+  enclosure_type: string;
+  enclosure_length: number;
 }
 
 export interface Video {
@@ -91,12 +166,16 @@ export interface Video {
   summary: string;
   tags: string[];
   evaluation: string;
+  feed_id?: string;
+  feed_item_id?: string;
+  source_tags?: string;
   highlights?: {
     id: string;
     video_id: string;
     start: string;
     end: string;
     description: string;
+    score?: number;
     file_path?: string;
   }[];
   status: string;
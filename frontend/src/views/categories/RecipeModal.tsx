@@ -0,0 +1,229 @@
+import { useEffect, useMemo, useState } from 'react';
+import { useTranslation } from 'react-i18next';
+import { Button, Form, Input, InputNumber, Modal, Select, Space, Switch, message } from 'antd';
+import { ArrowDownOutlined, ArrowUpOutlined, DeleteOutlined, PlusOutlined } from '@ant-design/icons';
+import { useShallow } from 'zustand/react/shallow';
+import { useCategoryStore } from '@/store/useCategoryStore';
+import { usePublishStore } from '@/store/usePublishStore';
+import { Category, RecipeStep, RecipeStepType } from '@/types';
+
+interface RecipeModalProps {
+  category: Category | null;
+  onClose: () => void;
+}
+
+const defaultSteps: RecipeStep[] = [
+  { type: RecipeStepType.Subtitles },
+  { type: RecipeStepType.Analyze },
+  { type: RecipeStepType.Clip },
+];
+
+export default function RecipeModal({ category, onClose }: RecipeModalProps) {
+  const { t } = useTranslation();
+  const [form] = Form.useForm();
+  const [loading, setLoading] = useState(false);
+  const [submitting, setSubmitting] = useState(false);
+  const [hasRecipe, setHasRecipe] = useState(false);
+  const { fetchRecipe, saveRecipe, deleteRecipe } = useCategoryStore(
+    useShallow((state) => ({
+      fetchRecipe: state.fetchRecipe,
+      saveRecipe: state.saveRecipe,
+      deleteRecipe: state.deleteRecipe,
+    }))
+  );
+  const { accounts, fetchAccounts } = usePublishStore(
+    useShallow((state) => ({
+      accounts: state.accounts,
+      fetchAccounts: state.fetchAccounts,
+    }))
+  );
+
+  useEffect(() => {
+    if (!category) return;
+    fetchAccounts();
+    setLoading(true);
+    fetchRecipe(category.id)
+      .then((recipe) => {
+        setHasRecipe(!!recipe);
+        form.setFieldsValue({
+          is_enabled: recipe ? recipe.is_enabled : true,
+          steps: recipe && recipe.steps.length > 0 ? recipe.steps : defaultSteps,
+        });
+      })
+      .finally(() => setLoading(false));
+  }, [category]);
+
+  const stepOptions = useMemo(
+    () =>
+      Object.values(RecipeStepType).map((value) => ({
+        value,
+        label: t(`categories.recipe.steps.${value}`),
+      })),
+    [t]
+  );
+
+  const accountOptions = useMemo(
+    () => accounts.map((account) => ({ value: account.id, label: account.name })),
+    [accounts]
+  );
+
+  const handleSubmit = async () => {
+    if (!category) return;
+    try {
+      const values = await form.validateFields();
+      setSubmitting(true);
+      await saveRecipe(category.id, values.steps || [], values.is_enabled);
+      message.success(t('categories.saveSuccess'));
+      onClose();
+    } catch (error) {
+      if (error instanceof Error || typeof error === 'string') {
+        message.error(`${t('categories.saveFailed')}: ${error}`);
+      }
+    } finally {
+      setSubmitting(false);
+    }
+  };
+
+  const handleReset = async () => {
+    if (!category) return;
+    await deleteRecipe(category.id);
+    message.success(t('categories.recipe.resetSuccess'));
+    onClose();
+  };
+
+  const renderOptions = (name: number) => {
+    const type: RecipeStepType = form.getFieldValue(['steps', name, 'type']);
+    switch (type) {
+      case RecipeStepType.Subtitles:
+        return (
+          <Form.Item name={[name, 'skip_asr']} valuePropName="checked" className="mb-0">
+            <Switch
+              size="small"
+              checkedChildren={t('categories.recipe.skipAsr')}
+              unCheckedChildren={t('categories.recipe.allowAsr')}
+            />
+          </Form.Item>
+        );
+      case RecipeStepType.Translate:
+        return (
+          <Form.Item
+            name={[name, 'language']}
+            rules={[{ required: true, message: t('categories.recipe.languageRequired') }]}
+            className="mb-0 flex-1"
+          >
+            <Input placeholder={t('categories.recipe.languagePlaceholder')} />
+          </Form.Item>
+        );
+      case RecipeStepType.Analyze:
+        return (
+          <Form.Item name={[name, 'prompt']} className="mb-0 flex-1">
+            <Input.TextArea rows={1} placeholder={t('categories.recipe.promptPlaceholder')} />
+          </Form.Item>
+        );
+      case RecipeStepType.Clip:
+        return (
+          <Form.Item name={[name, 'min_score']} className="mb-0">
+            <InputNumber
+              min={0}
+              max={10}
+              step={0.5}
+              placeholder={t('categories.recipe.minScore')}
+              style={{ width: 140 }}
+            />
+          </Form.Item>
+        );
+      case RecipeStepType.Publish:
+        return (
+          <Space.Compact className="flex-1">
+            <Form.Item
+              name={[name, 'account_id']}
+              rules={[{ required: true, message: t('categories.recipe.accountRequired') }]}
+              className="mb-0 flex-1"
+            >
+              <Select options={accountOptions} placeholder={t('categories.recipe.account')} />
+            </Form.Item>
+            <Form.Item name={[name, 'min_score']} className="mb-0">
+              <InputNumber
+                min={0}
+                max={10}
+                step={0.5}
+                placeholder={t('categories.recipe.minScore')}
+                style={{ width: 140 }}
+              />
+            </Form.Item>
+          </Space.Compact>
+        );
+      default:
+        return null;
+    }
+  };
+
+  return (
+    <Modal
+      centered
+      open={!!category}
+      title={t('categories.recipe.title', { name: category?.name || '' })}
+      onCancel={onClose}
+      destroyOnHidden
+      width={760}
+      footer={[
+        <Button key="reset" danger disabled={!hasRecipe} onClick={handleReset}>
+          {t('categories.recipe.reset')}
+        </Button>,
+        <Button key="cancel" onClick={onClose}>
+          {t('common.cancel')}
+        </Button>,
+        <Button key="save" type="primary" loading={submitting} onClick={handleSubmit}>
+          {t('common.save')}
+        </Button>,
+      ]}
+    >
+      <div className="text-xs text-slate-500 mb-3">{t('categories.recipe.description')}</div>
+      <Form form={form} layout="vertical" disabled={loading}>
+        <Form.Item
+          name="is_enabled"
+          label={t('categories.recipe.enabled')}
+          valuePropName="checked"
+        >
+          <Switch />
+        </Form.Item>
+        <Form.List name="steps">
+          {(fields, { add, remove, move }) => (
+            <div className="space-y-2">
+              {fields.map((field, index) => (
+                <div key={field.key} className="flex items-start gap-2">
+                  <span className="w-6 leading-8 text-slate-400 tabular-nums">{index + 1}.</span>
+                  <Form.Item name={[field.name, 'type']} className="mb-0" style={{ width: 160 }}>
+                    <Select options={stepOptions} />
+                  </Form.Item>
+                  <Form.Item noStyle shouldUpdate>
+                    {() => <div className="flex flex-1 gap-2">{renderOptions(field.name)}</div>}
+                  </Form.Item>
+                  <Button
+                    icon={<ArrowUpOutlined />}
+                    disabled={index === 0}
+                    onClick={() => move(index, index - 1)}
+                  />
+                  <Button
+                    icon={<ArrowDownOutlined />}
+                    disabled={index === fields.length - 1}
+                    onClick={() => move(index, index + 1)}
+                  />
+                  <Button danger icon={<DeleteOutlined />} onClick={() => remove(field.name)} />
+                </div>
+              ))}
+              <Button
+                type="dashed"
+                block
+                icon={<PlusOutlined />}
+                onClick={() => add({ type: RecipeStepType.Analyze })}
+              >
+                {t('categories.recipe.addStep')}
+              </Button>
+            </div>
+          )}
+        </Form.List>
+      </Form>
+    </Modal>
+  );
+}
@@ -7,6 +7,7 @@ import { useCategoryStore } from '@/store/useCategoryStore';
 import { useShallow } from 'zustand/react/shallow';
 import dayjs from 'dayjs';
 import { Category, CategorySource } from '@/types';
+import RecipeModal from './RecipeModal';
 
 const promptVariables = [
   '{{title}}',
@@ -40,6 +41,7 @@ export default function CategoriesView() {
   const [modalOpen, setModalOpen] = useState(false);
   const [editingCategory, setEditingCategory] = useState<Category | null>(null);
   const [submitting, setSubmitting] = useState(false);
+  const [recipeCategory, setRecipeCategory] = useState<Category | null>(null);
 
   useEffect(() => {
     fetchCategories();
@@ -130,12 +132,15 @@ export default function CategoriesView() {
       {
         title: t('categories.columns.actions'),
         key: 'actions',
-        width: 180,
+        width: 240,
         render: (_: unknown, record: Category) => (
           <Space size="small">
             <Button size="small" onClick={() => handleOpenEdit(record)}>
               {t('common.edit')}
             </Button>
+            <Button size="small" onClick={() => setRecipeCategory(record)}>
+              {t('categories.recipe.action')}
+            </Button>
             <Button
               size="small"
               danger
@@ -207,6 +212,8 @@ export default function CategoriesView() {
           </div>
         </Form>
       </Modal>
+
+      <RecipeModal category={recipeCategory} onClose={() => setRecipeCategory(null)} />
     </PageContainer>
   );
 }
@@ -3,10 +3,11 @@ import { Grid, CellComponentProps } from 'react-window';
 import { useTranslation } from 'react-i18next';
 import { Button, Checkbox, Divider, Image, Select, Tag } from 'antd';
 import { schema } from '@root/wailsjs/go/models';
-import { ImageFallback } from '@/data/variables';
+import { ImageFallback, TaskPriority } from '@/data/variables';
 import dayjs from 'dayjs';
 import { DownloadOutlined, PlayCircleOutlined } from '@ant-design/icons';
 import DownloadDir from '@/components/DownloadDir';
+import PrioritySelect from '@/components/PrioritySelect';
 import { useShallow } from 'zustand/react/shallow';
 import { useStore } from 'zustand';
 import { useCategoryStore } from '@/store/useCategoryStore';
@@ -18,10 +19,12 @@ interface VideoResultProps {
     newDir,
     playList,
     categoryId,
+    priority,
   }: {
     newDir: string;
     playList?: number[];
     categoryId: string;
+    priority: TaskPriority;
   }) => void;
 }
 
@@ -40,6 +43,7 @@ const PlaylistResult = ({ videoInfo, onStartDownload }: VideoResultProps) => {
   const [newDir, setNewDir] = useState(defaultDir);
   const [selectedPlaylistItems, setSelectedPlaylistItems] = useState<number[]>([]);
   const [categoryId, setCategoryId] = useState('');
+  const [priority, setPriority] = useState(TaskPriority.Normal);
 
   const columns = useMemo(() => {
     if (!Array.isArray(videoInfo.playlist_items)) {
@@ -220,12 +224,18 @@ const PlaylistResult = ({ videoInfo, onStartDownload }: VideoResultProps) => {
             style={{ width: '100%' }}
           />
         </div>
+        <div className="space-y-2">
+          <label className="text-sm font-medium text-foreground">{t('downloads.priority')}</label>
+          <PrioritySelect value={priority} onChange={setPriority} />
+        </div>
       </div>
       <Divider size="large" />
       <div className="flex items-end justify-end">
         <Button
           type="primary"
-          onClick={() => onStartDownload({ newDir, playList: selectedPlaylistItems, categoryId })}
+          onClick={() =>
+            onStartDownload({ newDir, playList: selectedPlaylistItems, categoryId, priority })
+          }
           disabled={selectedPlaylistItems.length === 0}
           icon={<DownloadOutlined />}
         >
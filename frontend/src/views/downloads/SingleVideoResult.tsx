@@ -15,8 +15,9 @@ import { useStore } from 'zustand';
 import { useSettingStore } from '@/store/useSettingStore';
 import { useCategoryStore } from '@/store/useCategoryStore';
 import DownloadDir from '@/components/DownloadDir';
+import PrioritySelect from '@/components/PrioritySelect';
 
-import { TrimMode } from '@/data/variables';
+import { TaskPriority, TrimMode } from '@/data/variables';
 
 interface SingleVideoResultProps {
   videoInfo: schema.VideoInfo;
@@ -28,6 +29,7 @@ interface SingleVideoResultProps {
     trimEnd,
     trimMode,
     categoryId,
+    priority,
   }: {
     newDir: string;
     newQuality: string;
@@ -36,6 +38,7 @@ interface SingleVideoResultProps {
     trimEnd: string;
     trimMode: TrimMode;
     categoryId: string;
+    priority: TaskPriority;
   }) => void;
 }
 
@@ -50,6 +53,7 @@ const SingleVideoResult = ({ videoInfo, onStartDownload }: SingleVideoResultProp
   const [trimRange, setTrimRange] = useState<[number, number]>([0, 0]);
   const [trimMode, setTrimMode] = useState<TrimMode>(TrimMode.None);
   const [categoryId, setCategoryId] = useState('');
+  const [priority, setPriority] = useState(TaskPriority.Normal);
 
   useEffect(() => {
     if (videoInfo.qualities && videoInfo.qualities.length > 0) {
@@ -183,6 +187,10 @@ const SingleVideoResult = ({ videoInfo, onStartDownload }: SingleVideoResultProp
               style={{ width: '100%' }}
             />
           </div>
+          <div className="space-y-2">
+            <label className="text-sm font-medium text-foreground">{t('downloads.priority')}</label>
+            <PrioritySelect value={priority} onChange={setPriority} />
+          </div>
           <div className="space-y-2">
             <label className="text-sm font-medium text-foreground">
               {t('downloads.trimVideo')}
@@ -230,6 +238,7 @@ const SingleVideoResult = ({ videoInfo, onStartDownload }: SingleVideoResultProp
               trimEnd: formatSeconds(trimRange[1]),
               trimMode,
               categoryId,
+              priority,
             })
           }
           disabled={!newDir || !videoInfo}
@@ -10,7 +10,7 @@ import PageContainer from '@/components/PageContainer';
 import PageHeader from '@/components/PageHeader';
 import bilibiliIcon from '@/assets/images/bilibili.png';
 import youtubeIcon from '@/assets/images/Youtube.png';
-import { MenuItemKey, TrimMode, SourceType } from '@/data/variables';
+import { MenuItemKey, TrimMode, SourceType, TaskPriority } from '@/data/variables';
 import SingleVideoResult from './SingleVideoResult';
 import PlaylistResult from './PlaylistResult';
 
@@ -64,6 +64,7 @@ export default function Downloads() {
     trimEnd,
     trimMode,
     categoryId,
+    priority,
   }: {
     newDir: string;
     newQuality: string;
@@ -72,6 +73,7 @@ export default function Downloads() {
     trimEnd: string;
     trimMode: TrimMode;
     categoryId: string;
+    priority: TaskPriority;
   }) => {
     if (!newUrl || !newDir) return;
     try {
@@ -95,6 +97,7 @@ export default function Downloads() {
           trim_mode: trimMode,
           source_type: SourceType.Single,
           category_id: categoryId,
+          priority,
         })
       );
       setNewUrl('');
@@ -112,10 +115,12 @@ export default function Downloads() {
     newDir,
     playList = [],
     categoryId,
+    priority,
   }: {
     newDir: string;
     playList?: number[];
     categoryId: string;
+    priority: TaskPriority;
   }) => {
     if (!newUrl || !newDir) return;
     try {
@@ -141,6 +146,7 @@ export default function Downloads() {
           thumbnail: videoInfo?.thumbnail || '',
           playlist_items: selectedItems,
           category_id: categoryId,
+          priority,
         })
       );
 
@@ -1,6 +1,17 @@
 import React, { useState, useEffect } from 'react';
 import { useTranslation } from 'react-i18next';
-import { Modal, Input, Form, Button, Switch, Select, notification } from 'antd';
+import {
+  Modal,
+  Input,
+  InputNumber,
+  Form,
+  Button,
+  Switch,
+  Select,
+  Radio,
+  Space,
+  notification,
+} from 'antd';
 import { useRSSStore } from '@/store/useRSSStore';
 import { BrowserOpenURL } from '@root/wailsjs/runtime/runtime';
 import DownloadDir from '@/components/DownloadDir';
@@ -8,6 +19,7 @@ import { useSettingStore } from '@/store/useSettingStore';
 import { useShallow } from 'zustand/react/shallow';
 import { RSSFeed } from '@/types';
 import { useCategoryStore } from '@/store/useCategoryStore';
+import { FeedSourceType } from '@/data/variables';
 
 interface AddFeedModalProps {
   open: boolean;
@@ -25,6 +37,8 @@ const AddFeedModal: React.FC<AddFeedModalProps> = ({
   const { t } = useTranslation();
   const [isSubmitting, setIsSubmitting] = useState(false);
   const [form] = Form.useForm();
+  const sourceType = Form.useWatch('source_type', form);
+  const isChannel = sourceType === FeedSourceType.Channel;
 
   const { defaultDir } = useSettingStore(
     useShallow((state) => ({
@@ -54,12 +68,18 @@ const AddFeedModal: React.FC<AddFeedModalProps> = ({
     if (mode === 'edit' && initialValues) {
       form.setFieldsValue({
         url: initialValues.url,
+        source_type: initialValues.source_type ?? FeedSourceType.RSS,
         custom_dir: initialValues.custom_dir,
+        podcast: initialValues.podcast,
         download_latest: initialValues.download_latest,
         filters: initialValues.filters,
         tags: initialValues.tags,
         filename_template: initialValues.filename_template,
         category_id: initialValues.category_id || undefined,
+        refresh_interval: initialValues.refresh_interval || undefined,
+        refresh_cron: initialValues.refresh_cron,
+        retention_items: initialValues.retention_items || undefined,
+        retention_days: initialValues.retention_days || undefined,
       });
     } else {
       form.resetFields();
@@ -75,11 +95,16 @@ const AddFeedModal: React.FC<AddFeedModalProps> = ({
         await updateFeed({
           id: initialValues.id,
           custom_dir: values.custom_dir || '',
+          podcast: !isChannel && !!values.podcast,
           download_latest: values.download_latest || false,
           filters: values.filters || '',
           tags: values.tags || '',
           filename_template: values.filename_template || '',
           category_id: values.category_id || '',
+          refresh_interval: values.refresh_interval || 0,
+          refresh_cron: values.refresh_cron || '',
+          retention_items: values.retention_items || 0,
+          retention_days: values.retention_days || 0,
         });
         notification.success({
           title: t('rss.modal.updateSuccess'),
@@ -87,12 +112,18 @@ const AddFeedModal: React.FC<AddFeedModalProps> = ({
       } else {
         await addFeed({
           url: values.url,
+          source_type: values.source_type ?? FeedSourceType.RSS,
           custom_dir: values.custom_dir || '',
+          podcast: !isChannel && !!values.podcast,
           download_latest: values.download_latest || false,
           filters: values.filters || '',
           tags: values.tags || '',
           filename_template: values.filename_template || '',
           category_id: values.category_id || '',
+          refresh_interval: values.refresh_interval || 0,
+          refresh_cron: values.refresh_cron || '',
+          retention_items: values.retention_items || 0,
+          retention_days: values.retention_days || 0,
         });
         notification.success({
           title: t('rss.modal.success'),
@@ -125,20 +156,30 @@ const AddFeedModal: React.FC<AddFeedModalProps> = ({
       okText={mode === 'edit' ? t('rss.modal.save') : t('rss.modal.add')}
       cancelText={t('rss.modal.cancel')}
     >
-      <div className="mb-4 text-slate-500 text-sm">{t('rss.modal.desc')}</div>
+      <div className="mb-4 text-slate-500 text-sm">
+        {isChannel ? t('rss.modal.channelDesc') : t('rss.modal.desc')}
+      </div>
 
       <Form
         form={form}
         layout="vertical"
         initialValues={{
+          source_type: FeedSourceType.RSS,
           download_latest: true,
           custom_dir: defaultDir,
           url: 'http://192.168.31.65:12000/bilibili/popular/all',
         }}
       >
+        <Form.Item name="source_type" label={t('rss.modal.sourceType')} className="mb-4">
+          <Radio.Group disabled={mode === 'edit'}>
+            <Radio value={FeedSourceType.RSS}>{t('rss.modal.sourceRSS')}</Radio>
+            <Radio value={FeedSourceType.Channel}>{t('rss.modal.sourceChannel')}</Radio>
+          </Radio.Group>
+        </Form.Item>
+
         <Form.Item
           name="url"
-          label={t('rss.modal.url')}
+          label={isChannel ? t('rss.modal.channelUrl') : t('rss.modal.url')}
           rules={[
             { required: true, message: t('rss.modal.urlRequired') },
             { type: 'url', message: t('rss.modal.urlInvalid') },
@@ -146,30 +187,48 @@ const AddFeedModal: React.FC<AddFeedModalProps> = ({
           className="mb-2"
         >
           <Input
-            placeholder="https://docs.rsshub.app/routes/youtube/user/@FKJ"
+            placeholder={
+              isChannel
+                ? 'https://www.youtube.com/@FKJ'
+                : 'https://docs.rsshub.app/routes/youtube/user/@FKJ'
+            }
             disabled={mode === 'edit'}
           />
         </Form.Item>
 
-        <div className="mb-3 p-2 flex items-center justify-between gap-2 bg-yellow-50 dark:bg-yellow-900/10 rounded border border-yellow-100 dark:border-yellow-900/20 text-xs">
-          <span className="text-slate-600 dark:text-slate-400">{t('rss.modal.rsshubTip')}</span>
-          <Button
-            type="link"
-            size="small"
-            className="p-0 ml-1 h-auto"
-            onClick={() => BrowserOpenURL('https://docs.rsshub.app/')}
-          >
-            {'>'}
-          </Button>
-        </div>
+        {!isChannel && (
+          <div className="mb-3 p-2 flex items-center justify-between gap-2 bg-yellow-50 dark:bg-yellow-900/10 rounded border border-yellow-100 dark:border-yellow-900/20 text-xs">
+            <span className="text-slate-600 dark:text-slate-400">{t('rss.modal.rsshubTip')}</span>
+            <Button
+              type="link"
+              size="small"
+              className="p-0 ml-1 h-auto"
+              onClick={() => BrowserOpenURL('https://docs.rsshub.app/')}
+            >
+              {'>'}
+            </Button>
+          </div>
+        )}
 
         <Form.Item label={t('rss.modal.customDir')} name="custom_dir" className="mb-4">
           <DownloadDir />
         </Form.Item>
 
-        <Form.Item label={t('rss.modal.downloadLatest')} name="download_latest" className="mb-4">
-          <Switch />
-        </Form.Item>
+        <div className="grid grid-cols-2 gap-x-3">
+          <Form.Item label={t('rss.modal.downloadLatest')} name="download_latest" className="mb-4">
+            <Switch />
+          </Form.Item>
+          {!isChannel && (
+            <Form.Item
+              label={t('rss.modal.podcast')}
+              name="podcast"
+              tooltip={t('rss.modal.podcastTip')}
+              className="mb-4"
+            >
+              <Switch />
+            </Form.Item>
+          )}
+        </div>
         <Form.Item name="filters" label={t('rss.modal.filters')} className="mb-4">
           <Input placeholder={t('rss.modal.filtersPlaceholder')} />
         </Form.Item>
@@ -177,8 +236,49 @@ const AddFeedModal: React.FC<AddFeedModalProps> = ({
           <Input placeholder={t('rss.modal.tagsPlaceholder')} />
         </Form.Item>
         <Form.Item name="filename_template" label={t('rss.modal.template')} className="mb-4">
-          <Input placeholder="%(uploader)s/%(title)s.%(ext)s" />
+          <Input placeholder="{uploader}/{upload_date} - {title}" />
         </Form.Item>
+        <div className="grid grid-cols-2 gap-x-3">
+          <Form.Item label={t('rss.modal.refreshInterval')} className="mb-4">
+            <Space.Compact className="w-full">
+              <Form.Item name="refresh_interval" noStyle>
+                <InputNumber
+                  min={0}
+                  className="w-full"
+                  placeholder={t('rss.modal.refreshIntervalPlaceholder')}
+                />
+              </Form.Item>
+              <Space.Addon>min</Space.Addon>
+            </Space.Compact>
+          </Form.Item>
+          <Form.Item name="refresh_cron" label={t('rss.modal.refreshCron')} className="mb-4">
+            <Input placeholder="0 */6 * * *" allowClear />
+          </Form.Item>
+          <Form.Item
+            name="retention_items"
+            label={t('rss.modal.retentionItems')}
+            tooltip={t('rss.modal.retentionTip')}
+            className="mb-4"
+          >
+            <InputNumber
+              min={-1}
+              className="w-full"
+              placeholder={t('rss.modal.retentionPlaceholder')}
+            />
+          </Form.Item>
+          <Form.Item label={t('rss.modal.retentionDays')} className="mb-4">
+            <Space.Compact className="w-full">
+              <Form.Item name="retention_days" noStyle>
+                <InputNumber
+                  min={-1}
+                  className="w-full"
+                  placeholder={t('rss.modal.retentionPlaceholder')}
+                />
+              </Form.Item>
+              <Space.Addon>{t('rss.modal.days')}</Space.Addon>
+            </Space.Compact>
+          </Form.Item>
+        </div>
         <Form.Item name="category_id" label={t('rss.modal.category')} className="mb-2">
           <Select
             allowClear
@@ -186,6 +186,20 @@ const FeedItems: React.FC = () => {
             item_thumbnail: item.thumbnail,
             dir: currentFeed?.custom_dir || '',
             category_id: currentFeed?.category_id || '',
+            filename_template: currentFeed?.filename_template || '',
+            feed_id: currentFeed?.id || '',
+            feed_item_id: item.id,
+            item_description: item.description || '',
+            feed_tags: currentFeed?.tags || '',
+            item_author: item.author || '',
+            item_pub_date: item.pub_date,
+            ...(currentFeed?.podcast && item.enclosure_url
+              ? {
+                  enclosure_url: item.enclosure_url,
+                  enclosure_type: item.enclosure_type,
+                  enclosure_length: item.enclosure_length,
+                }
+              : {}),
           })
         );
         await markItemRead(item.id);
@@ -8,9 +8,13 @@ import {
   StopOutlined,
   CheckCircleOutlined,
   EditOutlined,
+  PlaySquareOutlined,
+  CustomerServiceOutlined,
+  WarningOutlined,
 } from '@ant-design/icons';
 import { cn } from '@/lib/utils';
 import { RSSFeed } from '@/types';
+import { FeedSourceType } from '@/data/variables';
 
 interface FeedListProps {
   onEdit: (feed: RSSFeed) => void;
@@ -117,6 +121,33 @@ const FeedList: React.FC<FeedListProps> = ({ onEdit }) => {
                 </div>
               )}
 
+              {feed.source_type === FeedSourceType.Channel && (
+                <div
+                  title={t('rss.channel')}
+                  className="absolute -bottom-0.5 -right-0.5 z-20 w-[18px] h-[18px] rounded-full border-[1.5px] border-white dark:border-neutral-900 bg-primary flex items-center justify-center"
+                >
+                  <PlaySquareOutlined className="text-[10px] text-white" />
+                </div>
+              )}
+
+              {feed.podcast && (
+                <div
+                  title={t('rss.podcast')}
+                  className="absolute -bottom-0.5 -right-0.5 z-20 w-[18px] h-[18px] rounded-full border-[1.5px] border-white dark:border-neutral-900 bg-primary flex items-center justify-center"
+                >
+                  <CustomerServiceOutlined className="text-[10px] text-white" />
+                </div>
+              )}
+
+              {feed.failure_count > 0 && (
+                <div
+                  title={t('rss.health', { count: feed.failure_count, error: feed.last_error })}
+                  className="absolute -bottom-0.5 -left-0.5 z-20 w-[18px] h-[18px] rounded-full border-[1.5px] border-white dark:border-neutral-900 bg-amber-500 flex items-center justify-center"
+                >
+                  <WarningOutlined className="text-[10px] text-white" />
+                </div>
+              )}
+
               {feed.unread_count > 0 && (
                 <div
                   className={cn(
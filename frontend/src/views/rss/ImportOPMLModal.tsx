@@ -0,0 +1,122 @@
+import React, { useEffect, useState } from 'react';
+import { useTranslation } from 'react-i18next';
+import { Alert, Button, Input, List, Modal, Radio, Space } from 'antd';
+import { FileTextOutlined } from '@ant-design/icons';
+import { ChooseFile, ImportOPML } from '@root/wailsjs/go/main/App';
+import { schema } from '@root/wailsjs/go/models';
+import { useRSSStore } from '@/store/useRSSStore';
+
+interface ImportOPMLModalProps {
+  open: boolean;
+  onClose: () => void;
+}
+
+const ImportOPMLModal: React.FC<ImportOPMLModalProps> = ({ open, onClose }) => {
+  const { t } = useTranslation();
+  const fetchFeeds = useRSSStore((state) => state.fetchFeeds);
+  const [file, setFile] = useState('');
+  const [mode, setMode] = useState('category');
+  const [importing, setImporting] = useState(false);
+  const [result, setResult] = useState<schema.OPMLImportResult | null>(null);
+  const [error, setError] = useState('');
+
+  useEffect(() => {
+    if (!open) return;
+    setFile('');
+    setResult(null);
+    setError('');
+  }, [open]);
+
+  const handleChooseFile = async () => {
+    try {
+      const path = await ChooseFile([
+        { displayName: 'OPML (*.opml, *.xml)', pattern: '*.opml;*.xml' },
+        { displayName: 'All Files (*)', pattern: '*' },
+      ]);
+      if (path) {
+        setFile(path);
+      }
+    } catch (e) {
+      console.error(e);
+    }
+  };
+
+  const handleImport = async () => {
+    setImporting(true);
+    setError('');
+    try {
+      setResult(await ImportOPML(file, mode));
+      await fetchFeeds();
+    } catch (e) {
+      setError(String(e));
+    } finally {
+      setImporting(false);
+    }
+  };
+
+  return (
+    <Modal
+      centered
+      open={open}
+      title={t('rss.opml.importTitle')}
+      onCancel={onClose}
+      onOk={result ? onClose : handleImport}
+      okText={result ? t('rss.opml.done') : t('rss.opml.import')}
+      okButtonProps={{ disabled: !file }}
+      cancelButtonProps={{ hidden: !!result }}
+      cancelText={t('rss.modal.cancel')}
+      confirmLoading={importing}
+      destroyOnHidden
+      width={560}
+    >
+      {result ? (
+        <div className="space-y-3">
+          <Alert
+            type={result.failed?.length ? 'warning' : 'success'}
+            showIcon
+            title={t('rss.opml.result', {
+              added: result.added,
+              skipped: result.skipped,
+              failed: result.failed?.length || 0,
+            })}
+          />
+          {!!result.failed?.length && (
+            <List
+              size="small"
+              bordered
+              className="max-h-64 overflow-y-auto"
+              dataSource={result.failed}
+              renderItem={(item) => (
+                <List.Item>
+                  <div className="min-w-0">
+                    <div className="truncate font-medium">{item.title || item.url}</div>
+                    <div className="truncate text-xs text-slate-500">{item.url}</div>
+                    <div className="text-xs text-red-500">{item.error}</div>
+                  </div>
+                </List.Item>
+              )}
+            />
+          )}
+        </div>
+      ) : (
+        <div className="space-y-4">
+          <div className="text-slate-500 text-sm">{t('rss.opml.importDesc')}</div>
+          <Space.Compact className="w-full">
+            <Input readOnly value={file} placeholder={t('rss.opml.filePlaceholder')} />
+            <Button icon={<FileTextOutlined />} onClick={handleChooseFile} />
+          </Space.Compact>
+          <div>
+            <div className="mb-1 text-sm">{t('rss.opml.folders')}</div>
+            <Radio.Group value={mode} onChange={(e) => setMode(e.target.value)}>
+              <Radio value="category">{t('rss.opml.foldersCategory')}</Radio>
+              <Radio value="tag">{t('rss.opml.foldersTag')}</Radio>
+            </Radio.Group>
+          </div>
+          {error && <Alert type="error" showIcon title={error} />}
+        </div>
+      )}
+    </Modal>
+  );
+};
+
+export default ImportOPMLModal;
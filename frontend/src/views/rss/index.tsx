@@ -1,11 +1,20 @@
 import React, { useEffect, useState } from 'react';
 import { useTranslation } from 'react-i18next';
 import { useRSSStore } from '@/store/useRSSStore';
-import { PlusOutlined } from '@ant-design/icons';
+import { Dropdown, message } from 'antd';
+import {
+  ClearOutlined,
+  ExportOutlined,
+  ImportOutlined,
+  PlusOutlined,
+  SwapOutlined,
+} from '@ant-design/icons';
+import { ExportOPML, PruneRSSItems } from '@root/wailsjs/go/main/App';
 import FeedList from './FeedList';
 import FeedItems from './FeedItems';
 import RSSWelcome from './RSSWelcome';
 import AddFeedModal from './AddFeedModal';
+import ImportOPMLModal from './ImportOPMLModal';
 import PageContainer from '@/components/PageContainer';
 import PageHeader from '@/components/PageHeader';
 import { RSSFeed } from '@/types';
@@ -21,6 +30,7 @@ const RSSView: React.FC = () => {
   );
   const [isModalOpen, setIsModalOpen] = useState(false);
   const [editingFeed, setEditingFeed] = useState<RSSFeed | undefined>(undefined);
+  const [isImportOpen, setIsImportOpen] = useState(false);
 
   const handleEdit = (feed: RSSFeed) => {
     setEditingFeed(feed);
@@ -32,6 +42,27 @@ const RSSView: React.FC = () => {
     setEditingFeed(undefined);
   };
 
+  const handleExport = async () => {
+    try {
+      const path = await ExportOPML();
+      if (path) {
+        message.success(t('rss.opml.exportSuccess', { path }));
+      }
+    } catch (error) {
+      message.error(`${t('rss.opml.exportFailed')}: ${error}`);
+    }
+  };
+
+  const handlePrune = async () => {
+    try {
+      const result = await PruneRSSItems();
+      message.success(t('rss.prune.success', { items: result.items, feeds: result.feeds }));
+      await fetchFeeds();
+    } catch (error) {
+      message.error(`${t('rss.prune.failed')}: ${error}`);
+    }
+  };
+
   useEffect(() => {
     fetchFeeds();
   }, []);
@@ -58,6 +89,41 @@ const RSSView: React.FC = () => {
                 {t('rss.add')}
               </span>
             </div>
+            <Dropdown
+              trigger={['click']}
+              menu={{
+                items: [
+                  {
+                    key: 'import',
+                    label: t('rss.opml.import'),
+                    icon: <ImportOutlined />,
+                    onClick: () => setIsImportOpen(true),
+                  },
+                  {
+                    key: 'export',
+                    label: t('rss.opml.export'),
+                    icon: <ExportOutlined />,
+                    onClick: handleExport,
+                  },
+                  { type: 'divider' },
+                  {
+                    key: 'prune',
+                    label: t('rss.prune.run'),
+                    icon: <ClearOutlined />,
+                    onClick: handlePrune,
+                  },
+                ],
+              }}
+            >
+              <div className="flex flex-col items-center gap-1.5 cursor-pointer group shrink-0 px-1">
+                <div className="w-14 h-14 rounded-full border-2 border-dashed border-slate-300 dark:border-neutral-700 flex items-center justify-center text-slate-400 group-hover:border-primary group-hover:text-primary transition-colors bg-slate-50 dark:bg-neutral-900">
+                  <SwapOutlined className="text-xl" />
+                </div>
+                <span className="text-[11px] text-slate-500 group-hover:text-primary transition-colors">
+                  OPML
+                </span>
+              </div>
+            </Dropdown>
           </div>
         </div>
       }
@@ -69,6 +135,7 @@ const RSSView: React.FC = () => {
         initialValues={editingFeed}
         mode={editingFeed ? 'edit' : 'add'}
       />
+      <ImportOPMLModal open={isImportOpen} onClose={() => setIsImportOpen(false)} />
     </PageContainer>
   );
 };
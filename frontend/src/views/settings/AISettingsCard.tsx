@@ -1,14 +1,84 @@
 import { useState } from 'react';
 import { useTranslation } from 'react-i18next';
-import { Card, Input, Segmented, Select, Switch, Typography } from 'antd';
-import { useSettingStore } from '@/store/useSettingStore';
+import {
+  AutoComplete,
+  Button,
+  Card,
+  Input,
+  InputNumber,
+  Segmented,
+  Select,
+  Space,
+  Switch,
+  Typography,
+  message,
+} from 'antd';
+import { SyncOutlined } from '@ant-design/icons';
+import { AIConfig, useSettingStore } from '@/store/useSettingStore';
 import { useShallow } from 'zustand/react/shallow';
+import { CheckLocalWhisper, ListAIModels } from '@root/wailsjs/go/main/App';
+import { config } from '@root/wailsjs/go/models';
+import AIUsagePanel from './AIUsagePanel';
+
+// Whisper providers that run a local executable instead of calling an API.
+const LOCAL_WHISPER_ENGINES = ['whispercpp', 'faster-whisper'];
 
 const { Text } = Typography;
 
+// ModelInput suggests the models the provider serves and warns when the
+// configured one is not among them.
+const ModelInput = ({ value, onChange }: { value: AIConfig; onChange: (v: string) => void }) => {
+  const { t } = useTranslation();
+  const [models, setModels] = useState<string[]>([]);
+  const [loading, setLoading] = useState(false);
+
+  const fetchModels = async () => {
+    setLoading(true);
+    try {
+      const list = (await ListAIModels(new config.AIConfig(value))) || [];
+      setModels(list);
+      if (list.length === 0) {
+        message.warning(t('settings.ai.noModels'));
+      } else if (
+        value.modelName &&
+        // Ollama lists untagged models with the implicit latest tag
+        !list.includes(value.modelName) &&
+        !list.includes(`${value.modelName}:latest`)
+      ) {
+        message.warning(t('settings.ai.modelMissing', { model: value.modelName }));
+      } else {
+        message.success(t('settings.ai.modelsLoaded', { count: list.length }));
+      }
+    } catch (error) {
+      message.error(`${t('settings.ai.modelsFailed')}: ${error}`);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <Space.Compact block>
+      <AutoComplete
+        value={value.modelName}
+        onChange={onChange}
+        options={models.map((m) => ({ value: m }))}
+        filterOption={(input, option) =>
+          (option?.value ?? '').toLowerCase().includes(input.toLowerCase())
+        }
+        placeholder="gpt-3.5-turbo"
+        style={{ width: '100%' }}
+      />
+      <Button icon={<SyncOutlined spin={loading} />} onClick={fetchModels} disabled={loading}>
+        {t('settings.ai.fetchModels')}
+      </Button>
+    </Space.Compact>
+  );
+};
+
 const AISettingsCard = () => {
   const { t } = useTranslation();
-  const [aiSegment, setAiSegment] = useState<'analysis' | 'whisper' | 'translation'>('analysis');
+  const [checkingWhisper, setCheckingWhisper] = useState(false);
+  const [aiSegment, setAiSegment] = useState<'analysis' | 'whisper' | 'translation' | 'usage'>('analysis');
   const { ai, setAI, whisperAi, setWhisperAI, translateAi, setTranslateAI } = useSettingStore(
     useShallow((state) => ({
       ai: state.ai,
@@ -19,6 +89,19 @@ const AISettingsCard = () => {
       setTranslateAI: state.setTranslateAI,
     }))
   );
+  const localWhisper = LOCAL_WHISPER_ENGINES.includes(whisperAi.provider);
+
+  const checkLocalWhisper = async () => {
+    setCheckingWhisper(true);
+    try {
+      const path = await CheckLocalWhisper(whisperAi.provider);
+      message.success(t('settings.ai.localWhisperFound', { path }));
+    } catch (e) {
+      message.error(t('settings.ai.localWhisperMissing', { error: String(e) }));
+    } finally {
+      setCheckingWhisper(false);
+    }
+  };
 
   return (
     <Card
@@ -34,11 +117,12 @@ const AISettingsCard = () => {
         <Segmented
           value={aiSegment}
           block
-          onChange={(val) => setAiSegment(val as 'analysis' | 'whisper' | 'translation')}
+          onChange={(val) => setAiSegment(val as 'analysis' | 'whisper' | 'translation' | 'usage')}
           options={[
             { label: t('settings.ai.analysisTitle'), value: 'analysis' },
             { label: t('settings.ai.whisperTitle'), value: 'whisper' },
             { label: t('settings.ai.translationTitle'), value: 'translation' },
+            { label: t('settings.ai.usage.title'), value: 'usage' },
           ]}
         />
         {aiSegment === 'analysis' && (
@@ -79,7 +163,13 @@ const AISettingsCard = () => {
                           modelName = 'gpt-3.5-turbo';
                         } else if (val === 'anthropic') {
                           baseUrl = 'https://api.anthropic.com/v1';
-                          modelName = 'claude-3-opus-20240229';
+                          modelName = 'claude-sonnet-4-5';
+                        } else if (val === 'gemini') {
+                          baseUrl = 'https://generativelanguage.googleapis.com/v1beta';
+                          modelName = 'gemini-2.5-flash';
+                        } else if (val === 'ollama') {
+                          baseUrl = 'http://localhost:11434';
+                          modelName = 'qwen2.5';
                         } else if (val === 'local') {
                           baseUrl = 'http://localhost:11434/v1';
                           modelName = 'llama3';
@@ -99,6 +189,7 @@ const AISettingsCard = () => {
                         { value: 'gemini', label: 'Google Gemini' },
                         { value: 'deepseek', label: 'DeepSeek' },
                         { value: 'siliconflow', label: 'SiliconFlow (硅基流动)' },
+                        { value: 'ollama', label: 'Ollama' },
                         { value: 'local', label: 'Local (Ollama/Compatible)' },
                         { value: 'custom', label: 'Custom' },
                       ]}
@@ -154,15 +245,40 @@ const AISettingsCard = () => {
                     </Text>
                   </div>
                   <div className="md:col-span-8">
-                    <Input
-                      value={ai.modelName}
-                      onChange={(e) => setAI({ ...ai, modelName: e.target.value })}
-                      placeholder="gpt-3.5-turbo"
-                      className="dark:bg-gray-800 dark:text-gray-300 dark:border-gray-700"
+                    <ModelInput
+                      value={ai}
+                      onChange={(modelName) => setAI({ ...ai, modelName })}
                     />
                   </div>
                 </div>
 
+                <div className="grid grid-cols-1 md:grid-cols-12 gap-2 items-center">
+                  <div className="md:col-span-4">
+                    <Text
+                      strong
+                      className="block text-[13px] text-gray-600 dark:text-gray-400 mb-0"
+                    >
+                      {t('settings.ai.chunkSize')}
+                    </Text>
+                  </div>
+                  <div className="md:col-span-8">
+                    <Space.Compact block>
+                      <InputNumber
+                        min={0}
+                        step={1000}
+                        value={ai.chunkSize || null}
+                        onChange={(val) => setAI({ ...ai, chunkSize: val || 0 })}
+                        placeholder={t('settings.ai.chunkSizePlaceholder')}
+                        className="w-full"
+                      />
+                      <Space.Addon>{t('settings.ai.chars')}</Space.Addon>
+                    </Space.Compact>
+                    <Text type="secondary" className="text-xs">
+                      {t('settings.ai.chunkSizeTip')}
+                    </Text>
+                  </div>
+                </div>
+
                 <div className="grid grid-cols-1 md:grid-cols-12 gap-2 items-start">
                   <div className="md:col-span-4 pt-1">
                     <Text
@@ -235,6 +351,9 @@ const AISettingsCard = () => {
                         } else if (val === 'siliconflow') {
                           baseUrl = 'https://api.siliconflow.cn/v1';
                           modelName = 'whisper-1';
+                        } else if (LOCAL_WHISPER_ENGINES.includes(val)) {
+                          baseUrl = '';
+                          modelName = 'base';
                         }
                         setWhisperAI({ ...whisperAi, provider: val, baseUrl, modelName });
                       }}
@@ -246,49 +365,65 @@ const AISettingsCard = () => {
                         { value: 'deepseek', label: 'DeepSeek' },
                         { value: 'siliconflow', label: 'SiliconFlow (硅基流动)' },
                         { value: 'local', label: 'Local (Ollama/Compatible)' },
+                        { value: 'whispercpp', label: 'whisper.cpp (Offline)' },
+                        { value: 'faster-whisper', label: 'faster-whisper (Offline)' },
                         { value: 'custom', label: 'Custom' },
                       ]}
                     />
+                    {localWhisper && (
+                      <div className="mt-1 flex items-center gap-2">
+                        <Text type="secondary" className="text-xs">
+                          {t('settings.ai.localWhisperTip')}
+                        </Text>
+                        <Button size="small" loading={checkingWhisper} onClick={checkLocalWhisper}>
+                          {t('settings.ai.localWhisperCheck')}
+                        </Button>
+                      </div>
+                    )}
                   </div>
                 </div>
 
-                <div className="grid grid-cols-1 md:grid-cols-12 gap-2 items-center">
-                  <div className="md:col-span-4">
-                    <Text
-                      strong
-                      className="block text-[13px] text-gray-600 dark:text-gray-400 mb-0"
-                    >
-                      {t('settings.ai.baseUrl')}
-                    </Text>
-                  </div>
-                  <div className="md:col-span-8">
-                    <Input
-                      value={whisperAi.baseUrl}
-                      onChange={(e) => setWhisperAI({ ...whisperAi, baseUrl: e.target.value })}
-                      placeholder="https://api.openai.com/v1"
-                      className="dark:bg-gray-800 dark:text-gray-300 dark:border-gray-700"
-                    />
-                  </div>
-                </div>
+                {!localWhisper && (
+                  <>
+                    <div className="grid grid-cols-1 md:grid-cols-12 gap-2 items-center">
+                      <div className="md:col-span-4">
+                        <Text
+                          strong
+                          className="block text-[13px] text-gray-600 dark:text-gray-400 mb-0"
+                        >
+                          {t('settings.ai.baseUrl')}
+                        </Text>
+                      </div>
+                      <div className="md:col-span-8">
+                        <Input
+                          value={whisperAi.baseUrl}
+                          onChange={(e) => setWhisperAI({ ...whisperAi, baseUrl: e.target.value })}
+                          placeholder="https://api.openai.com/v1"
+                          className="dark:bg-gray-800 dark:text-gray-300 dark:border-gray-700"
+                        />
+                      </div>
+                    </div>
 
-                <div className="grid grid-cols-1 md:grid-cols-12 gap-2 items-center">
-                  <div className="md:col-span-4">
-                    <Text
-                      strong
-                      className="block text-[13px] text-gray-600 dark:text-gray-400 mb-0"
-                    >
-                      {t('settings.ai.apiKey')}
-                    </Text>
-                  </div>
-                  <div className="md:col-span-8">
-                    <Input.Password
-                      value={whisperAi.apiKey}
-                      onChange={(e) => setWhisperAI({ ...whisperAi, apiKey: e.target.value })}
-                      placeholder="sk-..."
-                      className="dark:bg-gray-800 dark:text-gray-300 dark:border-gray-700"
-                    />
-                  </div>
-                </div>
+                    <div className="grid grid-cols-1 md:grid-cols-12 gap-2 items-center">
+                      <div className="md:col-span-4">
+                        <Text
+                          strong
+                          className="block text-[13px] text-gray-600 dark:text-gray-400 mb-0"
+                        >
+                          {t('settings.ai.apiKey')}
+                        </Text>
+                      </div>
+                      <div className="md:col-span-8">
+                        <Input.Password
+                          value={whisperAi.apiKey}
+                          onChange={(e) => setWhisperAI({ ...whisperAi, apiKey: e.target.value })}
+                          placeholder="sk-..."
+                          className="dark:bg-gray-800 dark:text-gray-300 dark:border-gray-700"
+                        />
+                      </div>
+                    </div>
+                  </>
+                )}
 
                 <div className="grid grid-cols-1 md:grid-cols-12 gap-2 items-center">
                   <div className="md:col-span-4">
@@ -303,7 +438,7 @@ const AISettingsCard = () => {
                     <Input
                       value={whisperAi.modelName}
                       onChange={(e) => setWhisperAI({ ...whisperAi, modelName: e.target.value })}
-                      placeholder="whisper-1"
+                      placeholder={localWhisper ? 'base' : 'whisper-1'}
                       className="dark:bg-gray-800 dark:text-gray-300 dark:border-gray-700"
                     />
                   </div>
@@ -388,7 +523,13 @@ const AISettingsCard = () => {
                           modelName = 'gpt-3.5-turbo';
                         } else if (val === 'anthropic') {
                           baseUrl = 'https://api.anthropic.com/v1';
-                          modelName = 'claude-3-opus-20240229';
+                          modelName = 'claude-sonnet-4-5';
+                        } else if (val === 'gemini') {
+                          baseUrl = 'https://generativelanguage.googleapis.com/v1beta';
+                          modelName = 'gemini-2.5-flash';
+                        } else if (val === 'ollama') {
+                          baseUrl = 'http://localhost:11434';
+                          modelName = 'qwen2.5';
                         } else if (val === 'local') {
                           baseUrl = 'http://localhost:11434/v1';
                           modelName = 'llama3';
@@ -408,6 +549,7 @@ const AISettingsCard = () => {
                         { value: 'gemini', label: 'Google Gemini' },
                         { value: 'deepseek', label: 'DeepSeek' },
                         { value: 'siliconflow', label: 'SiliconFlow (硅基流动)' },
+                        { value: 'ollama', label: 'Ollama' },
                         { value: 'local', label: 'Local (Ollama/Compatible)' },
                         { value: 'custom', label: 'Custom' },
                       ]}
@@ -463,13 +605,9 @@ const AISettingsCard = () => {
                     </Text>
                   </div>
                   <div className="md:col-span-8">
-                    <Input
-                      value={translateAi.modelName}
-                      onChange={(e) =>
-                        setTranslateAI({ ...translateAi, modelName: e.target.value })
-                      }
-                      placeholder="gpt-3.5-turbo"
-                      className="dark:bg-gray-800 dark:text-gray-300 dark:border-gray-700"
+                    <ModelInput
+                      value={translateAi}
+                      onChange={(modelName) => setTranslateAI({ ...translateAi, modelName })}
                     />
                   </div>
                 </div>
@@ -497,6 +635,7 @@ const AISettingsCard = () => {
             )}
           </>
         )}
+        {aiSegment === 'usage' && <AIUsagePanel />}
       </div>
     </Card>
   );
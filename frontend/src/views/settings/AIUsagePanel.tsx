@@ -0,0 +1,86 @@
+import { useEffect, useState } from 'react';
+import { useTranslation } from 'react-i18next';
+import { Segmented, Table, message } from 'antd';
+import type { ColumnsType } from 'antd/es/table';
+import { GetAIUsage } from '@root/wailsjs/go/main/App';
+import { schema } from '@root/wailsjs/go/models';
+
+const AIUsagePanel = () => {
+  const { t } = useTranslation();
+  const [days, setDays] = useState(7);
+  const [report, setReport] = useState<schema.AIUsageReport | null>(null);
+  const [loading, setLoading] = useState(false);
+
+  useEffect(() => {
+    setLoading(true);
+    GetAIUsage(days)
+      .then(setReport)
+      .catch((error) => message.error(`${t('settings.ai.usage.loadFailed')}: ${error}`))
+      .finally(() => setLoading(false));
+  }, [days, t]);
+
+  const columns = (keyTitle: string, renderKey?: (key: string) => string) =>
+    [
+      {
+        title: keyTitle,
+        dataIndex: 'key',
+        key: 'key',
+        render: (value: string) => (renderKey ? renderKey(value) : value),
+      },
+      { title: t('settings.ai.usage.calls'), dataIndex: 'calls', key: 'calls' },
+      { title: t('settings.ai.usage.failures'), dataIndex: 'failures', key: 'failures' },
+      {
+        title: t('settings.ai.usage.promptTokens'),
+        dataIndex: 'prompt_tokens',
+        key: 'prompt_tokens',
+        render: (value: number) => value.toLocaleString(),
+      },
+      {
+        title: t('settings.ai.usage.completionTokens'),
+        dataIndex: 'completion_tokens',
+        key: 'completion_tokens',
+        render: (value: number) => value.toLocaleString(),
+      },
+      {
+        title: t('settings.ai.usage.latency'),
+        dataIndex: 'avg_latency_ms',
+        key: 'avg_latency_ms',
+        render: (value: number) => `${(value / 1000).toFixed(1)}s`,
+      },
+    ] as ColumnsType<schema.AIUsageTotal>;
+
+  return (
+    <div className="flex flex-col gap-3">
+      <Segmented
+        value={days}
+        onChange={(val) => setDays(val as number)}
+        options={[
+          { label: t('settings.ai.usage.days', { count: 7 }), value: 7 },
+          { label: t('settings.ai.usage.days', { count: 30 }), value: 30 },
+          { label: t('settings.ai.usage.days', { count: 90 }), value: 90 },
+        ]}
+      />
+      <Table
+        rowKey="key"
+        size="small"
+        loading={loading}
+        dataSource={report?.by_purpose || []}
+        columns={columns(t('settings.ai.usage.feature'), (key) =>
+          t(`settings.ai.usage.purpose.${key}`, { defaultValue: key })
+        )}
+        pagination={false}
+      />
+      <Table
+        rowKey="key"
+        size="small"
+        loading={loading}
+        dataSource={[...(report?.by_day || [])].reverse()}
+        columns={columns(t('settings.ai.usage.day'))}
+        scroll={{ y: 40 * 7 }}
+        pagination={false}
+      />
+    </div>
+  );
+};
+
+export default AIUsagePanel;
@@ -0,0 +1,279 @@
+import { useEffect, useState } from 'react';
+import { useTranslation } from 'react-i18next';
+import { Button, Card, Form, Input, Modal, Radio, Select, Space, Switch, message } from 'antd';
+import { DeleteOutlined, FileTextOutlined, PlusOutlined } from '@ant-design/icons';
+import { ChooseFile, GetSiteProfiles, SaveSiteProfiles } from '@root/wailsjs/go/main/App';
+import { config as WailsConfig } from '@root/wailsjs/go/models';
+
+interface SiteProfilesModalProps {
+  open: boolean;
+  platform: string;
+  onClose: () => void;
+}
+
+interface ProfileFormValue {
+  name: string;
+  enabled: boolean;
+  hosts: string[];
+  proxyUrl: string;
+  userAgent: string;
+  referer: string;
+  geoBypass: 'inherit' | 'on' | 'off';
+  cookie: { enabled: boolean; source: string; browser: string; file: string };
+  extraArgs: string;
+}
+
+const browserOptions = [
+  { value: 'chrome', label: 'Google Chrome' },
+  { value: 'firefox', label: 'Mozilla Firefox' },
+  { value: 'edge', label: 'Microsoft Edge' },
+  { value: 'safari', label: 'Safari' },
+  { value: 'opera', label: 'Opera' },
+  { value: 'brave', label: 'Brave' },
+  { value: 'vivaldi', label: 'Vivaldi' },
+  { value: 'chromium', label: 'Chromium' },
+];
+
+const toFormValue = (profile: WailsConfig.SiteProfile): ProfileFormValue => ({
+  name: profile.name,
+  enabled: profile.enabled,
+  hosts: profile.hosts || [],
+  proxyUrl: profile.proxyUrl || '',
+  userAgent: profile.userAgent || '',
+  referer: profile.referer || '',
+  geoBypass:
+    profile.geoBypass === undefined || profile.geoBypass === null
+      ? 'inherit'
+      : profile.geoBypass
+        ? 'on'
+        : 'off',
+  cookie: {
+    enabled: !!profile.cookie?.enabled,
+    source: profile.cookie?.source || 'browser',
+    browser: profile.cookie?.browser || '',
+    file: profile.cookie?.file || '',
+  },
+  extraArgs: (profile.extraArgs || []).join(' '),
+});
+
+const toProfile = (value: ProfileFormValue) =>
+  new WailsConfig.SiteProfile({
+    name: value.name,
+    enabled: value.enabled,
+    hosts: value.hosts || [],
+    proxyUrl: value.proxyUrl || '',
+    userAgent: value.userAgent || '',
+    referer: value.referer || '',
+    geoBypass: value.geoBypass === 'inherit' ? undefined : value.geoBypass === 'on',
+    cookie: value.cookie,
+    extraArgs: (value.extraArgs || '').split(/\s+/).filter(Boolean),
+  });
+
+export default function SiteProfilesModal({ open, platform, onClose }: SiteProfilesModalProps) {
+  const { t } = useTranslation();
+  const [form] = Form.useForm();
+  const [saving, setSaving] = useState(false);
+
+  useEffect(() => {
+    if (!open) return;
+    GetSiteProfiles()
+      .then((profiles) => form.setFieldsValue({ profiles: (profiles || []).map(toFormValue) }))
+      .catch((e) => console.error('Failed to load site profiles', e));
+  }, [open]);
+
+  const handleChooseFile = async (name: number) => {
+    try {
+      const file = await ChooseFile([
+        { displayName: 'Text Files (*.txt)', pattern: '*.txt' },
+        { displayName: 'All Files (*)', pattern: '*' },
+      ]);
+      if (file) {
+        form.setFieldValue(['profiles', name, 'cookie', 'file'], file);
+      }
+    } catch (e) {
+      console.error(e);
+    }
+  };
+
+  const handleSave = async () => {
+    try {
+      const values = await form.validateFields();
+      setSaving(true);
+      await SaveSiteProfiles((values.profiles || []).map(toProfile));
+      message.success(t('settings.profiles.saveSuccess'));
+      onClose();
+    } catch (error) {
+      if (error instanceof Error || typeof error === 'string') {
+        message.error(`${t('settings.profiles.saveFailed')}: ${error}`);
+      }
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <Modal
+      centered
+      open={open}
+      title={t('settings.profiles.title')}
+      onCancel={onClose}
+      onOk={handleSave}
+      okText={t('common.save')}
+      cancelText={t('common.cancel')}
+      confirmLoading={saving}
+      destroyOnHidden
+      width={760}
+    >
+      <div className="text-xs text-slate-500 mb-3">{t('settings.profiles.description')}</div>
+      <Form form={form} layout="vertical" className="max-h-[60vh] overflow-y-auto pr-1">
+        <Form.List name="profiles">
+          {(fields, { add, remove }) => (
+            <div className="space-y-3">
+              {fields.map((field) => (
+                <Card
+                  key={field.key}
+                  size="small"
+                  title={
+                    <Space>
+                      <Form.Item
+                        name={[field.name, 'name']}
+                        rules={[{ required: true, message: t('settings.profiles.nameRequired') }]}
+                        className="mb-0"
+                      >
+                        <Input placeholder={t('settings.profiles.name')} variant="borderless" />
+                      </Form.Item>
+                    </Space>
+                  }
+                  extra={
+                    <Space>
+                      <Form.Item name={[field.name, 'enabled']} valuePropName="checked" noStyle>
+                        <Switch size="small" />
+                      </Form.Item>
+                      <Button
+                        type="text"
+                        danger
+                        icon={<DeleteOutlined />}
+                        onClick={() => remove(field.name)}
+                      />
+                    </Space>
+                  }
+                >
+                  <Form.Item
+                    name={[field.name, 'hosts']}
+                    label={t('settings.profiles.hosts')}
+                    rules={[{ required: true, message: t('settings.profiles.hostsRequired') }]}
+                  >
+                    <Select
+                      mode="tags"
+                      open={false}
+                      tokenSeparators={[',', ' ']}
+                      placeholder="bilibili.com, b23.tv"
+                    />
+                  </Form.Item>
+                  <div className="grid grid-cols-2 gap-x-3">
+                    <Form.Item name={[field.name, 'proxyUrl']} label={t('settings.network.proxy')}>
+                      <Input placeholder={t('settings.profiles.inherit')} allowClear />
+                    </Form.Item>
+                    <Form.Item
+                      name={[field.name, 'geoBypass']}
+                      label={t('settings.network.geoBypass')}
+                    >
+                      <Select
+                        options={[
+                          { value: 'inherit', label: t('settings.profiles.inherit') },
+                          { value: 'on', label: t('settings.profiles.on') },
+                          { value: 'off', label: t('settings.profiles.off') },
+                        ]}
+                      />
+                    </Form.Item>
+                    <Form.Item
+                      name={[field.name, 'userAgent']}
+                      label={t('settings.network.userAgent')}
+                    >
+                      <Input placeholder={t('settings.profiles.inherit')} allowClear />
+                    </Form.Item>
+                    <Form.Item name={[field.name, 'referer']} label={t('settings.network.referer')}>
+                      <Input placeholder={t('settings.profiles.inherit')} allowClear />
+                    </Form.Item>
+                  </div>
+                  <Form.Item label={t('settings.network.auth')} className="mb-3">
+                    <Space wrap>
+                      <Form.Item
+                        name={[field.name, 'cookie', 'enabled']}
+                        valuePropName="checked"
+                        noStyle
+                      >
+                        <Switch size="small" />
+                      </Form.Item>
+                      <Form.Item name={[field.name, 'cookie', 'source']} noStyle>
+                        <Radio.Group size="small">
+                          <Radio value="browser">{t('settings.site.browser')}</Radio>
+                          <Radio value="file">{t('settings.site.file')}</Radio>
+                        </Radio.Group>
+                      </Form.Item>
+                      <Form.Item noStyle shouldUpdate>
+                        {() =>
+                          form.getFieldValue(['profiles', field.name, 'cookie', 'source']) ===
+                          'file' ? (
+                            <Space.Compact>
+                              <Form.Item name={[field.name, 'cookie', 'file']} noStyle>
+                                <Input
+                                  readOnly
+                                  placeholder={t('settings.network.cookiesFilePlaceholder')}
+                                  style={{ width: 260 }}
+                                />
+                              </Form.Item>
+                              <Button
+                                icon={<FileTextOutlined />}
+                                onClick={() => handleChooseFile(field.name)}
+                              />
+                            </Space.Compact>
+                          ) : (
+                            <Form.Item name={[field.name, 'cookie', 'browser']} noStyle>
+                              <Select
+                                allowClear
+                                placeholder={t('settings.network.cookiesPlaceholder')}
+                                style={{ width: 220 }}
+                                options={browserOptions.filter(
+                                  (option) => platform !== 'windows' || option.value === 'firefox'
+                                )}
+                              />
+                            </Form.Item>
+                          )
+                        }
+                      </Form.Item>
+                    </Space>
+                  </Form.Item>
+                  <Form.Item
+                    name={[field.name, 'extraArgs']}
+                    label={t('settings.profiles.extraArgs')}
+                    className="mb-0"
+                  >
+                    <Input placeholder="--extractor-args youtube:player_client=web" allowClear />
+                  </Form.Item>
+                </Card>
+              ))}
+              <Button
+                type="dashed"
+                block
+                icon={<PlusOutlined />}
+                onClick={() =>
+                  add({
+                    name: '',
+                    enabled: true,
+                    hosts: [],
+                    geoBypass: 'inherit',
+                    cookie: { enabled: false, source: 'browser', browser: '', file: '' },
+                    extraArgs: '',
+                  })
+                }
+              >
+                {t('settings.profiles.add')}
+              </Button>
+            </div>
+          )}
+        </Form.List>
+      </Form>
+    </Modal>
+  );
+}
@@ -22,6 +22,7 @@ import { AppLanguage, AppTheme, useSettingStore, CookieConfig } from '@/store/us
 import DownloadDir from '@/components/DownloadDir';
 import { THEME_COLORS } from '@/data/themeColors';
 import AISettingsCard from './AISettingsCard';
+import SiteProfilesModal from './SiteProfilesModal';
 
 const { Text } = Typography;
 
@@ -37,6 +38,7 @@ const SectionDivider = ({ children }: { children: React.ReactNode }) => (
 const Settings = () => {
   const { t, i18n } = useTranslation();
   const [platform, setPlatform] = useState('');
+  const [profilesOpen, setProfilesOpen] = useState(false);
 
   useEffect(() => {
     GetPlatform().then(setPlatform);
@@ -478,9 +480,30 @@ const Settings = () => {
             <SectionDivider>{t('settings.network.auth')}</SectionDivider>
 
             {renderCookieSettings(cookie, setCookie)}
+
+            <SectionDivider>{t('settings.profiles.title')}</SectionDivider>
+
+            <div className="grid grid-cols-1 md:grid-cols-12 gap-2 items-center">
+              <div className="md:col-span-4">
+                <Text strong className="block text-[13px] text-gray-600 dark:text-gray-400 mb-0">
+                  {t('settings.profiles.siteProfiles')}
+                </Text>
+              </div>
+              <div className="md:col-span-8">
+                <Button onClick={() => setProfilesOpen(true)}>
+                  {t('settings.profiles.manage')}
+                </Button>
+              </div>
+            </div>
           </div>
         </Card>
 
+        <SiteProfilesModal
+          open={profilesOpen}
+          platform={platform}
+          onClose={() => setProfilesOpen(false)}
+        />
+
         <AISettingsCard />
 
         {/* Language Settings */}
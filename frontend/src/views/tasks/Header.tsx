@@ -39,28 +39,16 @@ export function Header({ filter, onFilterChange }: HeaderProps) {
       let isFailed = false;
 
       if (task.source_type === SourceType.Playlist || task.source_type === SourceType.RSS) {
-        const hasDownloading = childs.some(
-          (c) =>
-            c.status === TaskStatus.Pending ||
-            c.status === TaskStatus.Starting ||
-            c.status === TaskStatus.Downloading ||
-            c.status === TaskStatus.Merging ||
-            c.status === TaskStatus.Paused
-        );
-        const hasFailed = childs.some((c) => c.status === TaskStatus.Error);
-
-        if (hasDownloading || childs.length === 0) {
-          isDownloading = true;
-        }
-
-        if (!hasDownloading && hasFailed) {
-          isFailed = true;
-        }
-
-        if (!hasDownloading && !hasFailed && childs.length > 0) {
-          const allCompleted = childs.every((c) => c.status === TaskStatus.Completed);
-          if (allCompleted) isCompleted = true;
-        }
+        // The parent status is aggregated from its children by the backend
+        isDownloading =
+          childs.length === 0 ||
+          task.status === TaskStatus.Pending ||
+          task.status === TaskStatus.Downloading ||
+          task.status === TaskStatus.Paused;
+        isFailed =
+          !isDownloading &&
+          (task.status === TaskStatus.Error || task.status === TaskStatus.PartialFailed);
+        isCompleted = !isDownloading && task.status === TaskStatus.Completed;
       } else {
         isDownloading =
           task.status === TaskStatus.Pending ||
@@ -1,6 +1,23 @@
 import { useState, useMemo } from 'react';
-import { Card, Badge, Tag } from 'antd';
-import { DownOutlined, RightOutlined } from '@ant-design/icons';
+import { Card, Badge, Tag, Dropdown, MenuProps, message } from 'antd';
+import {
+  ClockCircleOutlined,
+  DownOutlined,
+  PauseCircleOutlined,
+  PlayCircleOutlined,
+  RedoOutlined,
+  RightOutlined,
+  StopOutlined,
+  SyncOutlined,
+} from '@ant-design/icons';
+import {
+  CancelGroup,
+  PauseGroup,
+  ResumeGroup,
+  RetryFailedInGroup,
+  SetPlaylistSyncInterval,
+  SyncPlaylist,
+} from '@root/wailsjs/go/main/App';
 import dayjs from 'dayjs';
 import { useTranslation } from 'react-i18next';
 import { Task } from '@/types';
@@ -10,6 +27,9 @@ import { useCategoryStore } from '@/store/useCategoryStore';
 import { useSettingStore } from '@/store/useSettingStore';
 import { useStore } from 'zustand';
 
+// Automatic sync intervals in minutes, 0 only syncs on demand
+const syncIntervals = [0, 60, 360, 1440];
+
 interface PlaylistTaskItemProps {
   task: Task;
   childrenTasks: Task[];
@@ -23,7 +43,17 @@ export function PlaylistTaskItem({ task, childrenTasks, onViewLog }: PlaylistTas
   const themeColor = useStore(useSettingStore, (state) => state.themeColor);
 
   const completedCount = childrenTasks.filter((t) => t.status === TaskStatus.Completed).length;
-  const failedCount = childrenTasks.filter((t) => t.status === TaskStatus.Error).length;
+  const failedCount = childrenTasks.filter(
+    (t) => t.status === TaskStatus.Error || t.status === TaskStatus.TrimFailed
+  ).length;
+  const pausedCount = childrenTasks.filter((t) => t.status === TaskStatus.Paused).length;
+  const cancelledCount = childrenTasks.filter((t) => t.status === TaskStatus.Cancelled).length;
+  const activeCount = childrenTasks.filter(
+    (t) =>
+      t.status === TaskStatus.Pending ||
+      t.status === TaskStatus.Starting ||
+      t.status === TaskStatus.Downloading
+  ).length;
   const totalCount = childrenTasks.length;
 
   const siteLabel = useMemo(() => {
@@ -38,92 +68,201 @@ export function PlaylistTaskItem({ task, childrenTasks, onViewLog }: PlaylistTas
     return categories?.find((c) => c.id === task.category_id);
   }, [task.category_id, categories]);
 
-  return (
-    <Badge.Ribbon text={siteLabel}>
-      <Card
-        hoverable
-        variant="borderless"
-        styles={{ body: { padding: '0' } }}
-        className="overflow-hidden"
-      >
-        <div
-          className="flex items-center gap-3 p-4 cursor-pointer hover:bg-gray-50 dark:hover:bg-white/5 transition-colors"
-          onClick={() => setExpanded(!expanded)}
-        >
-          <div className="text-muted-foreground text-sm">
-            {expanded ? <DownOutlined /> : <RightOutlined />}
-          </div>
+  const isPlaylist = task.source_type === SourceType.Playlist;
+  const syncInterval = task.sync_interval ?? 0;
+
+  const handleSync = async () => {
+    const hide = message.loading(t('tasks.playlist.syncing'), 0);
+    try {
+      const added = await SyncPlaylist(task.id);
+      message.success(t('tasks.playlist.syncDone', { count: added }));
+    } catch (error) {
+      message.error(`${t('tasks.playlist.syncFailed')}: ${error}`);
+    } finally {
+      hide();
+    }
+  };
+
+  const handleSetSyncInterval = async (minutes: number) => {
+    try {
+      await SetPlaylistSyncInterval(task.id, minutes);
+    } catch (error) {
+      message.error(`${t('tasks.playlist.syncFailed')}: ${error}`);
+    }
+  };
+
+  const runGroupAction = async (action: (id: string) => Promise<void>) => {
+    try {
+      await action(task.id);
+    } catch (error) {
+      message.error(`${t('tasks.playlist.groupFailed')}: ${error}`);
+    }
+  };
 
-          <div className="flex-1 min-w-0">
-            <div className="flex items-center gap-1.5 mb-1" title={task.title || task.url}>
-              {category && (
-                <Tag color={themeColor} className="py-0">
-                  {category.name}
-                </Tag>
-              )}
-              <div className="font-semibold text-[15px] truncate text-foreground">
-                {task.title || task.url}
+  const menuItems: MenuProps['items'] = [
+    {
+      key: 'pauseAll',
+      label: t('tasks.playlist.pauseAll'),
+      icon: <PauseCircleOutlined className="w-4 h-4 mt-[-2px]" />,
+      disabled: activeCount === 0,
+      onClick: () => runGroupAction(PauseGroup),
+    },
+    {
+      key: 'resumeAll',
+      label: t('tasks.playlist.resumeAll'),
+      icon: <PlayCircleOutlined className="w-4 h-4 mt-[-2px]" />,
+      disabled: pausedCount === 0,
+      onClick: () => runGroupAction(ResumeGroup),
+    },
+    {
+      key: 'retryFailed',
+      label: t('tasks.playlist.retryFailed'),
+      icon: <RedoOutlined className="w-4 h-4 mt-[-2px]" />,
+      disabled: failedCount === 0,
+      onClick: () => runGroupAction(RetryFailedInGroup),
+    },
+    {
+      key: 'cancelRemaining',
+      label: t('tasks.playlist.cancelRemaining'),
+      icon: <StopOutlined className="w-4 h-4 mt-[-2px]" />,
+      danger: true,
+      disabled: activeCount + pausedCount === 0,
+      onClick: () => runGroupAction(CancelGroup),
+    },
+    ...(isPlaylist
+      ? [
+          { type: 'divider' as const },
+          {
+            key: 'sync',
+            label: t('tasks.playlist.syncNow'),
+            icon: <SyncOutlined className="w-4 h-4 mt-[-2px]" />,
+            onClick: handleSync,
+          },
+          {
+            key: 'autoSync',
+            label: t('tasks.playlist.autoSync'),
+            icon: <ClockCircleOutlined className="w-4 h-4 mt-[-2px]" />,
+            children: syncIntervals.map((minutes) => ({
+              key: `autoSync-${minutes}`,
+              label: t(`tasks.playlist.syncInterval.${minutes}`),
+              disabled: minutes === syncInterval,
+              onClick: () => handleSetSyncInterval(minutes),
+            })),
+          },
+        ]
+      : []),
+  ];
+
+  return (
+    <Dropdown menu={{ items: menuItems }} trigger={['contextMenu']}>
+      <div>
+        <Badge.Ribbon text={siteLabel}>
+          <Card
+            hoverable
+            variant="borderless"
+            styles={{ body: { padding: '0' } }}
+            className="overflow-hidden"
+          >
+            <div
+              className="flex items-center gap-3 p-4 cursor-pointer hover:bg-gray-50 dark:hover:bg-white/5 transition-colors"
+              onClick={() => setExpanded(!expanded)}
+            >
+              <div className="text-muted-foreground text-sm">
+                {expanded ? <DownOutlined /> : <RightOutlined />}
               </div>
-            </div>
-            <div className="text-xs text-muted-foreground flex items-center gap-1">
-              <span>
-                {task.source_type === SourceType.RSS
-                  ? t('tasks.playlist.rssPrefix')
-                  : t('tasks.playlist.statusPrefix')}
-              </span>
-              {totalCount > 0 && completedCount === totalCount ? (
-                <span className="text-green-600 dark:text-green-400">
-                  {t('tasks.playlist.allCompleted')}
-                </span>
-              ) : totalCount > 0 && failedCount === totalCount ? (
-                <span className="text-red-500 dark:text-red-400">
-                  {t('tasks.playlist.allFailed')}
-                </span>
-              ) : (
-                <>
-                  <span className="text-green-600 dark:text-green-400">
-                    {t('tasks.playlist.progress', {
-                      completed: completedCount,
-                      total: totalCount,
-                    })}
+
+              <div className="flex-1 min-w-0">
+                <div className="flex items-center gap-1.5 mb-1" title={task.title || task.url}>
+                  {category && (
+                    <Tag color={themeColor} className="py-0">
+                      {category.name}
+                    </Tag>
+                  )}
+                  <div className="font-semibold text-[15px] truncate text-foreground">
+                    {task.title || task.url}
+                  </div>
+                </div>
+                <div className="text-xs text-muted-foreground flex items-center gap-1">
+                  <span>
+                    {task.source_type === SourceType.RSS
+                      ? t('tasks.playlist.rssPrefix')
+                      : t('tasks.playlist.statusPrefix')}
                   </span>
-                  {failedCount > 0 && (
+                  {totalCount > 0 && completedCount === totalCount ? (
+                    <span className="text-green-600 dark:text-green-400">
+                      {t('tasks.playlist.allCompleted')}
+                    </span>
+                  ) : totalCount > 0 && failedCount === totalCount ? (
+                    <span className="text-red-500 dark:text-red-400">
+                      {t('tasks.playlist.allFailed')}
+                    </span>
+                  ) : (
+                    <>
+                      <span className="text-green-600 dark:text-green-400">
+                        {t('tasks.playlist.progress', {
+                          completed: completedCount,
+                          total: totalCount,
+                        })}
+                      </span>
+                      {failedCount > 0 && (
+                        <>
+                          <span>·</span>
+                          <span className="text-red-500 dark:text-red-400">
+                            {failedCount}
+                            &ensp;
+                            {t('tasks.playlist.partiallyFailed')}
+                          </span>
+                        </>
+                      )}
+                      {cancelledCount > 0 && (
+                        <>
+                          <span>·</span>
+                          <span>
+                            {cancelledCount}
+                            &ensp;
+                            {t('tasks.playlist.cancelled')}
+                          </span>
+                        </>
+                      )}
+                    </>
+                  )}
+                  {task.created_at && (
+                    <>
+                      <span>·</span>
+                      <span>{dayjs.unix(task.created_at).format('YYYY-MM-DD HH:mm')}</span>
+                    </>
+                  )}
+                  {syncInterval > 0 && !!task.last_synced_at && (
                     <>
                       <span>·</span>
-                      <span className="text-red-500 dark:text-red-400">
-                        {failedCount}
-                        &ensp;
-                        {t('tasks.playlist.partiallyFailed')}
+                      <span>
+                        {t('tasks.playlist.lastSynced', {
+                          time: dayjs.unix(task.last_synced_at).format('MM-DD HH:mm'),
+                        })}
                       </span>
                     </>
                   )}
-                </>
-              )}
-              {task.created_at && (
-                <>
-                  <span>·</span>
-                  <span>{dayjs.unix(task.created_at).format('YYYY-MM-DD HH:mm')}</span>
-                </>
-              )}
+                </div>
+              </div>
+              <div className="w-8 h-8"></div>
             </div>
-          </div>
-          <div className="w-8 h-8"></div>
-        </div>
 
-        {expanded && (
-          <div className="bg-gray-50/50 dark:bg-black/20 border-t border-gray-100 dark:border-white/5 p-2 space-y-2 pl-4">
-            {childrenTasks.map((child) => (
-              <TaskItem
-                key={child.id}
-                task={child}
-                showSiteLabel={false}
-                showCategoryTag={false}
-                onViewLog={() => onViewLog(child.id)}
-              />
-            ))}
-          </div>
-        )}
-      </Card>
-    </Badge.Ribbon>
+            {expanded && (
+              <div className="bg-gray-50/50 dark:bg-black/20 border-t border-gray-100 dark:border-white/5 p-2 space-y-2 pl-4">
+                {childrenTasks.map((child) => (
+                  <TaskItem
+                    key={child.id}
+                    task={child}
+                    showSiteLabel={false}
+                    showCategoryTag={false}
+                    onViewLog={() => onViewLog(child.id)}
+                  />
+                ))}
+              </div>
+            )}
+          </Card>
+        </Badge.Ribbon>
+      </div>
+    </Dropdown>
   );
 }
@@ -0,0 +1,38 @@
+import { useEffect, useState } from 'react';
+import { useTranslation } from 'react-i18next';
+import { Alert } from 'antd';
+import { GetQueueStatus } from '@root/wailsjs/go/main/App';
+import { EventsOn } from '@root/wailsjs/runtime/runtime';
+import { QueueStatus } from '@/types';
+import { formatBytes } from '@/lib/utils';
+
+export function QueueStatusAlert() {
+  const { t } = useTranslation();
+  const [status, setStatus] = useState<QueueStatus | null>(null);
+
+  useEffect(() => {
+    GetQueueStatus()
+      .then(setStatus)
+      .catch((error) => console.error('Failed to fetch queue status:', error));
+
+    return EventsOn('queue:status', (data: QueueStatus) => setStatus(data));
+  }, []);
+
+  if (!status?.paused) {
+    return null;
+  }
+
+  return (
+    <Alert
+      type="warning"
+      showIcon
+      className="mb-4"
+      title={t('tasks.queuePaused.diskSpace')}
+      description={t('tasks.queuePaused.diskSpaceDesc', {
+        dir: status.dir,
+        free: formatBytes(status.free_bytes),
+        required: formatBytes(status.required_bytes),
+      })}
+    />
+  );
+}
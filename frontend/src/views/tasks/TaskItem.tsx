@@ -10,22 +10,30 @@ import {
   LinkOutlined,
   ReloadOutlined,
   DeleteOutlined,
+  ArrowUpOutlined,
+  ArrowDownOutlined,
+  VerticalAlignTopOutlined,
+  VerticalAlignBottomOutlined,
+  FlagOutlined,
 } from '@ant-design/icons';
 import dayjs from 'dayjs';
 import { useTranslation } from 'react-i18next';
 import { Task } from '@/types';
-import { TaskStatus } from '@/data/variables';
+import { TaskMove, TaskPriority, TaskStatus } from '@/data/variables';
 import {
   PauseTask,
   ResumeTask,
   OpenTaskDir,
   RetryTask,
   AddVideoToLibrary,
+  MoveTask,
+  SetTaskPriority,
 } from '@root/wailsjs/go/main/App';
 import { useTaskStore } from '@/store/useTaskStore';
-import { formatBytes } from '@/lib/utils';
+import { formatBytes, formatDuration } from '@/lib/utils';
 import { useTheme } from '@/hooks/useTheme';
 import { ThumbnailImage } from '@/components/ThumbnailImage';
+import { priorityKeys, priorityOrder } from '@/components/PrioritySelect';
 import { useCategoryStore } from '@/store/useCategoryStore';
 import { useSettingStore } from '@/store/useSettingStore';
 import { useStore } from 'zustand';
@@ -102,11 +110,21 @@ export function TaskItem({
         return t('tasks.status.pending');
       case TaskStatus.Paused:
         return t('tasks.status.paused');
+      case TaskStatus.Cancelled:
+        return t('tasks.status.cancelled');
       default:
         return status;
     }
   };
 
+  const errorReason = useMemo(() => {
+    if (!task.error_kind) return '';
+    return t(`tasks.errorKind.${task.error_kind}`, { defaultValue: task.error_kind });
+  }, [task.error_kind, t]);
+
+  const isWaitingRetry =
+    task.status === TaskStatus.Pending && !!task.next_retry_at && !!task.attempts;
+
   const displayProgress = task.progress;
   const displaySize = task.total_bytes ? formatBytes(task.total_bytes) : undefined;
 
@@ -156,6 +174,24 @@ export function TaskItem({
     }
   };
 
+  const handleMove = async (move: TaskMove) => {
+    try {
+      await MoveTask(task.id, move);
+    } catch (error) {
+      message.error(`${t('tasks.moveFailed')}: ${error}`);
+    }
+  };
+
+  const handleSetPriority = async (priority: TaskPriority) => {
+    try {
+      await SetTaskPriority(task.id, priority);
+    } catch (error) {
+      message.error(`${t('tasks.moveFailed')}: ${error}`);
+    }
+  };
+
+  const priority: TaskPriority = task.priority ?? TaskPriority.Normal;
+
   const menuItems: MenuProps['items'] = [
     {
       key: 'details',
@@ -175,7 +211,9 @@ export function TaskItem({
       icon: <LinkOutlined className="w-4 h-4 mt-[-2px]" />,
       onClick: () => navigator.clipboard.writeText(task.url),
     },
-    ...(task.status === TaskStatus.Completed && task.file_exists && task.quality !== 'audio'
+    ...(task.status === TaskStatus.Completed &&
+    task.file_exists &&
+    (task.quality !== 'audio' || task.enclosure_url)
       ? [
           {
             key: 'addToLibrary',
@@ -185,6 +223,45 @@ export function TaskItem({
           },
         ]
       : []),
+    ...(task.status === TaskStatus.Pending
+      ? [
+          {
+            key: 'moveTop',
+            label: t('tasks.contextMenu.moveTop'),
+            icon: <VerticalAlignTopOutlined className="w-4 h-4 mt-[-2px]" />,
+            onClick: () => handleMove(TaskMove.Top),
+          },
+          {
+            key: 'moveUp',
+            label: t('tasks.contextMenu.moveUp'),
+            icon: <ArrowUpOutlined className="w-4 h-4 mt-[-2px]" />,
+            onClick: () => handleMove(TaskMove.Up),
+          },
+          {
+            key: 'moveDown',
+            label: t('tasks.contextMenu.moveDown'),
+            icon: <ArrowDownOutlined className="w-4 h-4 mt-[-2px]" />,
+            onClick: () => handleMove(TaskMove.Down),
+          },
+          {
+            key: 'moveBottom',
+            label: t('tasks.contextMenu.moveBottom'),
+            icon: <VerticalAlignBottomOutlined className="w-4 h-4 mt-[-2px]" />,
+            onClick: () => handleMove(TaskMove.Bottom),
+          },
+          {
+            key: 'priority',
+            label: t('tasks.contextMenu.priority'),
+            icon: <FlagOutlined className="w-4 h-4 mt-[-2px]" />,
+            children: priorityOrder.map((value) => ({
+              key: `priority-${value}`,
+              label: t(`tasks.priority.${priorityKeys[value]}`),
+              disabled: value === priority,
+              onClick: () => handleSetPriority(value),
+            })),
+          },
+        ]
+      : []),
     ...(task.status === 'error' || (task.status === 'completed' && !task.file_exists)
       ? [
           {
@@ -281,6 +358,14 @@ export function TaskItem({
                       {category.name}
                     </Tag>
                   )}
+                  {priority !== TaskPriority.Normal && (
+                    <Tag
+                      color={priority > TaskPriority.Normal ? 'volcano' : 'default'}
+                      className="py-0"
+                    >
+                      {t(`tasks.priority.${priorityKeys[priority]}`)}
+                    </Tag>
+                  )}
                   <div
                     className="flex-1 min-w-0 font-semibold text-[15px] truncate text-foreground"
                     title={task.title || task.url}
@@ -291,6 +376,7 @@ export function TaskItem({
                     className={`shrink-0 text-[11px] font-medium px-2.5 py-0.5 rounded-full uppercase tracking-wide whitespace-nowrap ${getStatusTagClass(
                       task.status
                     )}`}
+                    title={task.error_message || undefined}
                   >
                     {getStatusText(task.status)}
                   </div>
@@ -315,14 +401,47 @@ export function TaskItem({
                     )}
                     {isActive && (
                       <>
-                        {task.speed && task.speed !== '~' && (
+                        {!!task.speed_bps && (
+                          <>
+                            <span className="text-gray-300 dark:text-muted-foreground/50">•</span>
+                            <span>{formatBytes(Math.round(task.speed_bps))}/s</span>
+                          </>
+                        )}
+                        {!!task.eta_seconds && (
+                          <>
+                            <span className="text-gray-300 dark:text-muted-foreground/50">•</span>
+                            <span>{formatDuration(task.eta_seconds)}</span>
+                          </>
+                        )}
+                        {!!task.fragment_count && (
                           <>
                             <span className="text-gray-300 dark:text-muted-foreground/50">•</span>
-                            <span>{task.speed}</span>
+                            <span>
+                              {task.fragment_index}/{task.fragment_count}
+                            </span>
                           </>
                         )}
                       </>
                     )}
+                    {(task.status === TaskStatus.Error || isWaitingRetry) && errorReason && (
+                      <>
+                        <span className="text-gray-300 dark:text-muted-foreground/50">•</span>
+                        <span className="text-red-500" title={task.error_message}>
+                          {errorReason}
+                        </span>
+                      </>
+                    )}
+                    {isWaitingRetry && (
+                      <>
+                        <span className="text-gray-300 dark:text-muted-foreground/50">•</span>
+                        <span>
+                          {t('tasks.retrying', {
+                            attempt: task.attempts,
+                            time: dayjs.unix(task.next_retry_at!).format('HH:mm:ss'),
+                          })}
+                        </span>
+                      </>
+                    )}
                     {task.created_at && (
                       <>
                         <span className="text-gray-300 dark:text-muted-foreground/50">•</span>
@@ -343,7 +462,8 @@ export function TaskItem({
                     className="flex items-center justify-center rounded-full w-8 h-8 text-muted-foreground hover:text-primary hover:bg-blue-50 dark:hover:bg-blue-500/20"
                   />
                 )}
-                {task.status === TaskStatus.Paused && (
+                {(task.status === TaskStatus.Paused ||
+                  task.status === TaskStatus.Cancelled) && (
                   <PlayCircleOutlined
                     title={t('tasks.resume')}
                     onClick={() => ResumeTask(task.id)}
@@ -2,6 +2,7 @@ import { useState } from 'react';
 import { Header } from './Header';
 import { TaskList } from './TaskList';
 import { LogModal } from './LogModal';
+import { QueueStatusAlert } from './QueueStatusAlert';
 import PageContainer from '@/components/PageContainer';
 
 export default function Tasks() {
@@ -10,6 +11,7 @@ export default function Tasks() {
 
   return (
     <PageContainer viewClass="px-10" header={<Header filter={filter} onFilterChange={setFilter} />}>
+      <QueueStatusAlert />
       <TaskList onViewLog={setViewLogId} filter={filter} />
       <LogModal viewLogId={viewLogId} onClose={() => setViewLogId(null)} />
     </PageContainer>
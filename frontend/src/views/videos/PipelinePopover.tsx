@@ -0,0 +1,77 @@
+import { ReactNode, useEffect, useState } from 'react';
+import { useTranslation } from 'react-i18next';
+import { Empty, Popover, Spin, Steps } from 'antd';
+import { GetVideoPipeline } from '@root/wailsjs/go/main/App';
+import { EventsOn } from '@root/wailsjs/runtime/runtime';
+import { PipelineStepStatus, VideoPipelineStep } from '@/types';
+
+interface PipelinePopoverProps {
+  videoId: string;
+  children: ReactNode;
+}
+
+const stepStatus: Record<PipelineStepStatus, 'wait' | 'process' | 'finish' | 'error'> = {
+  pending: 'wait',
+  running: 'process',
+  completed: 'finish',
+  failed: 'error',
+  skipped: 'finish',
+};
+
+export default function PipelinePopover({ videoId, children }: PipelinePopoverProps) {
+  const { t } = useTranslation();
+  const [open, setOpen] = useState(false);
+  const [loading, setLoading] = useState(false);
+  const [steps, setSteps] = useState<VideoPipelineStep[]>([]);
+
+  useEffect(() => {
+    if (!open) return;
+    setLoading(true);
+    GetVideoPipeline(videoId)
+      .then((res) => setSteps((res as unknown as VideoPipelineStep[]) || []))
+      .catch((error) => console.error('Failed to fetch pipeline:', error))
+      .finally(() => setLoading(false));
+
+    return EventsOn('video:pipeline', (data: { id: string; steps: VideoPipelineStep[] }) => {
+      if (data.id === videoId) {
+        setSteps(data.steps || []);
+      }
+    });
+  }, [open, videoId]);
+
+  const content = (
+    <div className="w-72" onClick={(e) => e.stopPropagation()}>
+      {loading ? (
+        <div className="flex justify-center py-4">
+          <Spin size="small" />
+        </div>
+      ) : steps.length === 0 ? (
+        <Empty image={Empty.PRESENTED_IMAGE_SIMPLE} description={t('videos.pipeline.empty')} />
+      ) : (
+        <Steps
+          direction="vertical"
+          size="small"
+          items={steps.map((step) => ({
+            title: t(`categories.recipe.steps.${step.type}`),
+            status: stepStatus[step.status] || 'wait',
+            description: step.error
+              ? `${t(`videos.pipeline.status.${step.status}`)}: ${step.error}`
+              : t(`videos.pipeline.status.${step.status}`),
+          }))}
+        />
+      )}
+    </div>
+  );
+
+  return (
+    <Popover
+      title={t('videos.pipeline.title')}
+      content={content}
+      trigger="click"
+      open={open}
+      onOpenChange={setOpen}
+    >
+      {children}
+    </Popover>
+  );
+}
@@ -7,6 +7,7 @@ import {
   FileTextOutlined,
   ScissorOutlined,
   FolderOpenOutlined,
+  NodeIndexOutlined,
 } from '@ant-design/icons';
 import { Video, Category } from '@/types';
 import { formatDuration } from '@/lib/utils';
@@ -19,6 +20,7 @@ import { useCategoryStore } from '@/store/useCategoryStore';
 import { useStore } from 'zustand';
 import icon from '@/assets/images/icon.png';
 import { useSettingStore } from '@/store/useSettingStore';
+import PipelinePopover from './PipelinePopover';
 
 interface VideoListProps {
   videos: Video[];
@@ -222,6 +224,17 @@ const Cell = (props: any) => {
               </div>
             </Tooltip>
             <Divider orientation="vertical" />
+            <PipelinePopover videoId={video.id}>
+              <Tooltip title={t('videos.pipeline.title')}>
+                <div
+                  className="w-7 h-7 rounded-full hover:bg-slate-100 dark:hover:bg-slate-700 text-slate-500 dark:text-slate-400 hover:text-orange-500 dark:hover:text-orange-400 flex items-center justify-center transition-colors"
+                  onClick={(e) => e.stopPropagation()}
+                >
+                  <NodeIndexOutlined />
+                </div>
+              </Tooltip>
+            </PipelinePopover>
+            <Divider orientation="vertical" />
             <Tooltip title={t('common.delete')}>
               <div
                 className="w-7 h-7 rounded-full hover:bg-red-50 dark:hover:bg-red-900/20 text-slate-500 dark:text-slate-400 hover:text-red-500 dark:hover:text-red-400 flex items-center justify-center transition-colors"
@@ -10,6 +10,7 @@ import VideoList from './VideoList';
 import SubtitlesModal from './SubtitlesModal';
 import HighlightsModal from './HighlightsModal';
 import { useVideoStore } from '@/store/useVideoStore';
+import { useRSSStore } from '@/store/useRSSStore';
 
 export default function Videos() {
   const { t } = useTranslation();
@@ -21,21 +22,33 @@ export default function Videos() {
     }))
   );
 
+  const { feeds, fetchFeeds } = useRSSStore(
+    useShallow((state) => ({
+      feeds: state.feeds,
+      fetchFeeds: state.fetchFeeds,
+    }))
+  );
+
   const [searchQuery, setSearchQuery] = useState('');
   const [filterStatus, setFilterStatus] = useState('all');
+  const [filterFeed, setFilterFeed] = useState('');
   const [selectedVideoId, setSelectedVideoId] = useState<string | null>(null);
   const [highlightVideoId, setHighlightVideoId] = useState<string | null>(null);
 
   useEffect(() => {
-    fetchVideos(filterStatus, searchQuery);
-  }, [searchQuery, filterStatus, fetchVideos]);
+    fetchFeeds();
+  }, [fetchFeeds]);
+
+  useEffect(() => {
+    fetchVideos(filterStatus, searchQuery, filterFeed);
+  }, [searchQuery, filterStatus, filterFeed, fetchVideos]);
 
   const handleSelectVideo = (video: Video) => {
     setSelectedVideoId(video.id);
   };
 
   const handleRefresh = () => {
-    fetchVideos(filterStatus, searchQuery);
+    fetchVideos(filterStatus, searchQuery, filterFeed);
   };
 
   const headerContent = (
@@ -58,6 +71,15 @@ export default function Videos() {
         ]}
         className="w-32"
       />
+      <Select
+        value={filterFeed}
+        onChange={setFilterFeed}
+        options={[
+          { value: '', label: t('videos.filter_all_feeds') },
+          ...feeds.map((feed) => ({ value: feed.id, label: feed.title })),
+        ]}
+        className="w-40"
+      />
     </div>
   );
 
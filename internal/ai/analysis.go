@@ -1,18 +1,21 @@
 package ai
 
 import (
+	"context"
 	_ "embed"
 	"encoding/json"
 	"log"
 	"strings"
 
 	"Kairo/internal/config"
+	"Kairo/internal/db/schema"
 )
 
 //go:embed prompts/analysis.txt
 var defaultAIPrompt string
 
 type VideoMetadata struct {
+	VideoID          string // Recorded with the usage of the call
 	Title            string
 	Description      string
 	Subtitles        string
@@ -24,30 +27,51 @@ type VideoMetadata struct {
 	Format           string
 	Size             string
 	Date             string
+	// Windows splits a transcript too long for one prompt, each window is
+	// analysed on its own before a final pass merges the results
+	Windows []TranscriptWindow
+	// Progress is called after each analysed window
+	Progress func(done, total int)
 }
 
 func DefaultPrompt() string {
 	return defaultAIPrompt
 }
 
+// Highlight is a highlight segment suggested by the model. Score rates how
+// engaging the segment is on a 1-10 scale, 0 when the model gave none.
+type Highlight struct {
+	Title       string  `json:"title"`
+	Start       string  `json:"start"`
+	End         string  `json:"end"`
+	Description string  `json:"description"`
+	Score       float64 `json:"score"`
+}
+
 type AnalysisResult struct {
-	Summary    string `json:"summary"`
-	Tags       string `json:"tags"`
-	Evaluation string `json:"evaluation"`
-	Highlights []struct {
-		Title       string `json:"title"`
-		Start       string `json:"start"`
-		End         string `json:"end"`
-		Description string `json:"description"`
-	} `json:"highlights"`
+	Summary    string      `json:"summary"`
+	Tags       string      `json:"tags"`
+	Evaluation string      `json:"evaluation"`
+	Highlights []Highlight `json:"highlights"`
 }
 
-func (m *Manager) Analyze(meta VideoMetadata, promptTemplate string) (*AnalysisResult, error) {
+func (m *Manager) Analyze(ctx context.Context, meta VideoMetadata, promptTemplate string) (*AnalysisResult, error) {
 	settings := config.GetSettings()
 	if !settings.AI.Enabled {
 		return nil, ErrAIDisabled
 	}
 
+	if len(meta.Windows) > 1 {
+		return m.analyzeChunked(ctx, settings, meta, promptTemplate)
+	}
+
+	prompt := renderAnalysisPrompt(settings, meta, promptTemplate, meta.Subtitles)
+	return m.analyzePrompt(ctx, settings, meta, prompt)
+}
+
+// renderAnalysisPrompt fills the analysis template, subtitles is the
+// transcript or, for long videos, the merged window results.
+func renderAnalysisPrompt(settings config.AppSettings, meta VideoMetadata, promptTemplate, subtitles string) string {
 	prompt := defaultAIPrompt
 	if strings.TrimSpace(promptTemplate) != "" {
 		prompt = promptTemplate
@@ -60,25 +84,19 @@ func (m *Manager) Analyze(meta VideoMetadata, promptTemplate string) (*AnalysisR
 	prompt = strings.ReplaceAll(prompt, "{{format}}", meta.Format)
 	prompt = strings.ReplaceAll(prompt, "{{size}}", meta.Size)
 	prompt = strings.ReplaceAll(prompt, "{{description}}", meta.Description)
-	prompt = strings.ReplaceAll(prompt, "{{subtitles}}", meta.Subtitles)
+	prompt = strings.ReplaceAll(prompt, "{{subtitles}}", subtitles)
 	prompt = strings.ReplaceAll(prompt, "{{subtitle_stats}}", meta.SubtitleStats)
 	prompt = strings.ReplaceAll(prompt, "{{energy_candidates}}", meta.EnergyCandidates)
 	prompt = strings.ReplaceAll(prompt, "{{language}}", settings.Language)
+	return prompt
+}
 
+func (m *Manager) analyzePrompt(ctx context.Context, settings config.AppSettings, meta VideoMetadata, prompt string) (*AnalysisResult, error) {
 	if settings.AI.Prompt != "" {
 		prompt = prompt + "\n\n" + settings.AI.Prompt
 	}
 
-	var content string
-	var err error
-	switch settings.AI.Provider {
-	case "openai", "local", "custom", "deepseek", "siliconflow":
-		content, err = m.callOpenAI(settings.AI, prompt, true)
-	case "anthropic":
-		content, err = m.callAnthropic(settings.AI, prompt)
-	default:
-		content, err = m.callOpenAI(settings.AI, prompt, true)
-	}
+	content, err := m.complete(ctx, settings.AI, schema.AIPurposeAnalysis, meta.VideoID, prompt, true)
 	if err != nil {
 		log.Printf("[Analysis] Error calling AI provider: %v", err)
 		return nil, err
@@ -91,12 +109,7 @@ func (m *Manager) Analyze(meta VideoMetadata, promptTemplate string) (*AnalysisR
 		log.Printf("[Analysis] Error unmarshalling analysis response: %v", err)
 		analysis.Summary = content
 		analysis.Tags = ""
-		analysis.Highlights = []struct {
-			Title       string `json:"title"`
-			Start       string `json:"start"`
-			End         string `json:"end"`
-			Description string `json:"description"`
-		}{}
+		analysis.Highlights = []Highlight{}
 		analysis.Evaluation = "Failed to parse JSON response"
 	}
 
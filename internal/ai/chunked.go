@@ -0,0 +1,297 @@
+package ai
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"Kairo/internal/config"
+	"Kairo/internal/db/schema"
+)
+
+//go:embed prompts/analysis_map.txt
+var analysisMapPrompt string
+
+//go:embed prompts/analysis_reduce.txt
+var analysisReducePrompt string
+
+const (
+	defaultChunkChars = 12000
+	minChunkChars     = 2000
+	// Tokens kept free in the context window for the prompt around the
+	// transcript and for the answer
+	chunkPromptReserve = 6000
+	// Windows analysed at once, the provider limiter still applies
+	analysisWindowWorkers = 3
+	// Candidates kept when the final pass picks no highlights, also the
+	// fewest capNotes cuts down to
+	fallbackHighlights = 6
+	// Shortest window summary capNotes cuts down to, in characters
+	minNoteSummary = 80
+)
+
+// TranscriptWindow is a time range of a transcript analysed on its own.
+type TranscriptWindow struct {
+	Start float64 // Seconds
+	End   float64
+	Text  string // Subtitle lines with absolute timestamps
+}
+
+// modelContextWindows lists the context window in tokens by model name
+// fragment, the first match wins.
+var modelContextWindows = []struct {
+	fragment string
+	tokens   int
+}{
+	{"gpt-3.5", 16385},
+	{"gpt-4.1", 1000000},
+	{"gpt-4o", 128000},
+	{"gpt-4-turbo", 128000},
+	{"gpt-5", 400000},
+	{"gpt-4", 8192},
+	{"o1", 128000},
+	{"o3", 200000},
+	{"o4", 200000},
+	{"claude", 200000},
+	{"gemini", 1000000},
+	{"deepseek", 64000},
+	{"qwen", 32768},
+	{"glm", 128000},
+	{"kimi", 128000},
+}
+
+// modelContextWindow guesses the context window of the model of cfg, a
+// local Ollama runs with the context size callOllama asks for.
+func modelContextWindow(cfg config.AIConfig) int {
+	if cfg.Provider == "ollama" {
+		return ollamaMaxCtx
+	}
+	model := strings.ToLower(cfg.ModelName)
+	for _, entry := range modelContextWindows {
+		if strings.Contains(model, entry.fragment) {
+			return entry.tokens
+		}
+	}
+	return 32768
+}
+
+// AnalysisChunkSize returns the transcript characters analysed in one
+// prompt. The configured size is capped by the context window of the model,
+// counting a token per character which holds for CJK transcripts.
+func AnalysisChunkSize(cfg config.AIConfig) int {
+	size := cfg.ChunkSize
+	if size <= 0 {
+		size = defaultChunkChars
+	}
+	size = min(size, modelContextWindow(cfg)-chunkPromptReserve)
+	return max(size, minChunkChars)
+}
+
+// windowAnalysis is the answer for one transcript window.
+type windowAnalysis struct {
+	Summary    string      `json:"summary"`
+	Highlights []Highlight `json:"highlights"`
+}
+
+// analyzeChunked summarizes every window and collects its highlight
+// candidates, then a final pass over the window results produces the
+// analysis of the whole video.
+func (m *Manager) analyzeChunked(ctx context.Context, settings config.AppSettings, meta VideoMetadata, promptTemplate string) (*AnalysisResult, error) {
+	windows := m.analyzeWindows(ctx, settings, meta)
+
+	var notes []windowNote
+	var candidates []Highlight
+	var firstErr error
+	for i, result := range windows {
+		if result.err != nil {
+			if firstErr == nil {
+				firstErr = result.err
+			}
+			continue
+		}
+		notes = append(notes, windowNote{
+			index:      i,
+			window:     meta.Windows[i],
+			summary:    strings.TrimSpace(result.analysis.Summary),
+			highlights: result.analysis.Highlights,
+		})
+		candidates = append(candidates, result.analysis.Highlights...)
+	}
+	if len(notes) == 0 {
+		return nil, firstErr
+	}
+	if firstErr != nil {
+		log.Printf("[Analysis] %d of %d windows failed, first error: %v", len(windows)-len(notes), len(windows), firstErr)
+	}
+
+	// The final pass has the same budget as a window, long videos would
+	// otherwise overflow the context with their notes
+	prompt := renderAnalysisPrompt(settings, meta, promptTemplate, capNotes(notes, candidates, AnalysisChunkSize(settings.AI)))
+	prompt += "\n\n" + analysisReducePrompt
+	analysis, err := m.analyzePrompt(ctx, settings, meta, prompt)
+	if err != nil {
+		return nil, err
+	}
+	if len(analysis.Highlights) == 0 {
+		analysis.Highlights = topCandidates(candidates, fallbackHighlights)
+	}
+	return analysis, nil
+}
+
+// windowNote is the result of one window as passed to the final pass.
+type windowNote struct {
+	index      int
+	window     TranscriptWindow
+	summary    string
+	highlights []Highlight
+}
+
+// capNotes renders the window notes within budget characters. Only the best
+// scored candidates are kept first, then every summary is shortened to an
+// equal share.
+func capNotes(notes []windowNote, candidates []Highlight, budget int) string {
+	keep := len(candidates)
+	summaryLimit := 0
+	for {
+		kept := make(map[string]struct{})
+		for _, h := range topCandidates(candidates, keep) {
+			kept[h.Start+"-"+h.End] = struct{}{}
+		}
+		text := renderNotes(notes, kept, summaryLimit)
+		length := utf8.RuneCountInString(text)
+		switch {
+		case length <= budget:
+			return text
+		case keep > fallbackHighlights:
+			keep = max(keep/2, fallbackHighlights)
+		case summaryLimit == 0:
+			summaries := 0
+			for _, note := range notes {
+				summaries += utf8.RuneCountInString(note.summary)
+			}
+			share := (budget-(length-summaries))/len(notes) - 1 // Room for the ellipsis
+			summaryLimit = max(share, minNoteSummary)
+		default:
+			// Even the shortest notes do not fit, the provider reports
+			// the overflow
+			return text
+		}
+	}
+}
+
+func renderNotes(notes []windowNote, kept map[string]struct{}, summaryLimit int) string {
+	var b strings.Builder
+	for _, note := range notes {
+		summary := note.summary
+		if summaryLimit > 0 && utf8.RuneCountInString(summary) > summaryLimit {
+			summary = string([]rune(summary)[:summaryLimit]) + "…"
+		}
+		fmt.Fprintf(&b, "### 窗口 %d [%s - %s]\n", note.index+1, formatWindowTime(note.window.Start), formatWindowTime(note.window.End))
+		fmt.Fprintf(&b, "摘要：%s\n", summary)
+		var highlights []Highlight
+		for _, h := range note.highlights {
+			if _, ok := kept[h.Start+"-"+h.End]; ok {
+				highlights = append(highlights, h)
+			}
+		}
+		if len(highlights) > 0 {
+			b.WriteString("候选高能片段：\n")
+		}
+		for _, h := range highlights {
+			fmt.Fprintf(&b, "- [%s - %s] (评分 %g) %s：%s\n", h.Start, h.End, h.Score, h.Title, h.Description)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+type windowResult struct {
+	analysis windowAnalysis
+	err      error
+}
+
+func (m *Manager) analyzeWindows(ctx context.Context, settings config.AppSettings, meta VideoMetadata) []windowResult {
+	results := make([]windowResult, len(meta.Windows))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	sem := make(chan struct{}, analysisWindowWorkers)
+	for i, window := range meta.Windows {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			analysis, err := m.analyzeWindow(ctx, settings, meta, i, window)
+			results[i] = windowResult{analysis: analysis, err: err}
+
+			mu.Lock()
+			defer mu.Unlock()
+			done++
+			if meta.Progress != nil {
+				meta.Progress(done, len(meta.Windows))
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func (m *Manager) analyzeWindow(ctx context.Context, settings config.AppSettings, meta VideoMetadata, index int, window TranscriptWindow) (windowAnalysis, error) {
+	prompt := analysisMapPrompt
+	prompt = strings.ReplaceAll(prompt, "{{title}}", meta.Title)
+	prompt = strings.ReplaceAll(prompt, "{{uploader}}", meta.Uploader)
+	prompt = strings.ReplaceAll(prompt, "{{duration}}", meta.Duration)
+	prompt = strings.ReplaceAll(prompt, "{{window_index}}", strconv.Itoa(index+1))
+	prompt = strings.ReplaceAll(prompt, "{{window_count}}", strconv.Itoa(len(meta.Windows)))
+	prompt = strings.ReplaceAll(prompt, "{{window_start}}", formatWindowTime(window.Start))
+	prompt = strings.ReplaceAll(prompt, "{{window_end}}", formatWindowTime(window.End))
+	prompt = strings.ReplaceAll(prompt, "{{subtitles}}", window.Text)
+	prompt = strings.ReplaceAll(prompt, "{{language}}", settings.Language)
+
+	var result windowAnalysis
+	content, err := m.complete(ctx, settings.AI, schema.AIPurposeAnalysis, meta.VideoID, prompt, true)
+	if err != nil {
+		log.Printf("[Analysis] Error analysing window %d: %v", index+1, err)
+		return result, err
+	}
+	if err := json.Unmarshal([]byte(sanitizeJSONContent(content)), &result); err != nil {
+		log.Printf("[Analysis] Error unmarshalling window %d response: %v", index+1, err)
+		return result, err
+	}
+	return result, nil
+}
+
+// topCandidates keeps the best scored candidates in time order, windows
+// overlap so the same candidate may be proposed twice.
+func topCandidates(candidates []Highlight, limit int) []Highlight {
+	var sorted []Highlight
+	seen := make(map[string]struct{})
+	for _, h := range candidates {
+		key := h.Start + "-" + h.End
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		sorted = append(sorted, h)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Score > sorted[j].Score })
+	if len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+	return sorted
+}
+
+func formatWindowTime(seconds float64) string {
+	total := int(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, total%3600/60, total%60)
+}
@@ -0,0 +1,135 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"Kairo/internal/config"
+)
+
+const geminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+func geminiBase(cfg config.AIConfig) string {
+	base := strings.TrimRight(cfg.BaseURL, "/")
+	// The OpenAI compatible endpoint lives below the native one
+	base = strings.TrimSuffix(base, "/openai")
+	if base == "" {
+		return geminiBaseURL
+	}
+	return base
+}
+
+type geminiProvider struct{}
+
+func (geminiProvider) Timeout() time.Duration { return completionTimeout }
+
+// NewRequest builds a generateContent request, JSON mode asks for an
+// application/json response.
+func (geminiProvider) NewRequest(ctx context.Context, cfg config.AIConfig, prompt string, jsonMode bool) (*http.Request, error) {
+	model := strings.TrimPrefix(cfg.ModelName, "models/")
+	url := fmt.Sprintf("%s/models/%s:generateContent", geminiBase(cfg), model)
+
+	reqBody := map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{"role": "user", "parts": []map[string]string{{"text": prompt}}},
+		},
+	}
+	if jsonMode {
+		reqBody["generationConfig"] = map[string]string{"responseMimeType": "application/json"}
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.APIKey != "" {
+		req.Header.Set("x-goog-api-key", cfg.APIKey)
+	}
+	return req, nil
+}
+
+func (geminiProvider) ParseResponse(cfg config.AIConfig, body []byte) (*Completion, error) {
+	var result struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text    string `json:"text"`
+					Thought bool   `json:"thought"`
+				} `json:"parts"`
+			} `json:"content"`
+			FinishReason string `json:"finishReason"`
+		} `json:"candidates"`
+		PromptFeedback struct {
+			BlockReason string `json:"blockReason"`
+		} `json:"promptFeedback"`
+		UsageMetadata struct {
+			PromptTokenCount     int `json:"promptTokenCount"`
+			CandidatesTokenCount int `json:"candidatesTokenCount"`
+			ThoughtsTokenCount   int `json:"thoughtsTokenCount"`
+		} `json:"usageMetadata"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	if result.PromptFeedback.BlockReason != "" {
+		return nil, fmt.Errorf("prompt blocked by Gemini: %s", result.PromptFeedback.BlockReason)
+	}
+	if len(result.Candidates) == 0 {
+		return nil, fmt.Errorf("no response from AI")
+	}
+	candidate := result.Candidates[0]
+	var content strings.Builder
+	for _, part := range candidate.Content.Parts {
+		if !part.Thought {
+			content.WriteString(part.Text)
+		}
+	}
+	if candidate.FinishReason == "MAX_TOKENS" {
+		return nil, fmt.Errorf("%w: output reached the model limit", ErrAITruncated)
+	}
+	if content.Len() == 0 {
+		return nil, fmt.Errorf("no response from AI (finish reason %s)", candidate.FinishReason)
+	}
+
+	return &Completion{
+		Content:      content.String(),
+		PromptTokens: result.UsageMetadata.PromptTokenCount,
+		// Thinking is billed as output
+		CompletionTokens: result.UsageMetadata.CandidatesTokenCount + result.UsageMetadata.ThoughtsTokenCount,
+	}, nil
+}
+
+func (geminiProvider) ParseError(resp *http.Response, body []byte) error {
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+		} `json:"error"`
+	}
+	message := string(body)
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error.Message != "" {
+		message = payload.Error.Status + ": " + payload.Error.Message
+	}
+
+	switch payload.Error.Status {
+	case "RESOURCE_EXHAUSTED":
+		return statusError(resp, ErrAIRateLimited, message)
+	case "UNAVAILABLE":
+		return statusError(resp, ErrAIOverloaded, message)
+	}
+	return statusError(resp, nil, message)
+}
@@ -5,21 +5,45 @@ import (
 	"errors"
 	"net/http"
 	"time"
+
+	"Kairo/internal/db/dal"
+	"Kairo/internal/deps"
+
+	"gorm.io/gorm"
 )
 
 var ErrAIDisabled = errors.New("ai is disabled")
 var ErrWhisperDisabled = errors.New("whisper is disabled")
 
+// Provider failures worth retrying later, wrapped with the provider message.
+var (
+	ErrAIRateLimited = errors.New("ai provider rate limited")
+	ErrAIOverloaded  = errors.New("ai provider overloaded")
+)
+
+// ErrAITruncated is returned when the answer hit the output limit of the
+// model. Translation retries with smaller batches, analysis fails with it.
+var ErrAITruncated = errors.New("ai response truncated")
+
 type Manager struct {
-	client *http.Client
-	ctx    context.Context
+	client   *http.Client
+	ctx      context.Context
+	usageDAL *dal.AIUsageDAL
+	deps     *deps.Manager // Resolves local whisper executables and models
 }
 
-func NewManager(ctx context.Context) *Manager {
-	return &Manager{
-		ctx: ctx,
+// NewManager creates an AI manager, calls are only recorded in the usage
+// table when db is set and local transcription needs d.
+func NewManager(ctx context.Context, db *gorm.DB, d *deps.Manager) *Manager {
+	m := &Manager{
+		ctx:  ctx,
+		deps: d,
 		client: &http.Client{
 			Timeout: 60 * time.Second,
 		},
 	}
+	if db != nil {
+		m.usageDAL = dal.NewAIUsageDAL(db)
+	}
+	return m
 }
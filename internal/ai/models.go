@@ -0,0 +1,104 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"Kairo/internal/config"
+)
+
+// ListModels asks the provider of cfg which models it serves, so settings
+// can be checked before they are saved.
+func (m *Manager) ListModels(cfg config.AIConfig) ([]string, error) {
+	return providerFor(cfg.Provider).ListModels(m.ctx, m.client, cfg)
+}
+
+func (openAIProvider) ListModels(ctx context.Context, client *http.Client, cfg config.AIConfig) ([]string, error) {
+	header := http.Header{}
+	if cfg.APIKey != "" {
+		header.Set("Authorization", "Bearer "+cfg.APIKey)
+	}
+	return fetchModels(ctx, client, strings.TrimRight(cfg.BaseURL, "/")+"/models", header)
+}
+
+func (anthropicProvider) ListModels(ctx context.Context, client *http.Client, cfg config.AIConfig) ([]string, error) {
+	header := http.Header{}
+	header.Set("anthropic-version", anthropicVersion)
+	if cfg.APIKey != "" {
+		header.Set("x-api-key", cfg.APIKey)
+	}
+	return fetchModels(ctx, client, anthropicBase(cfg)+"/models?limit=1000", header)
+}
+
+func (geminiProvider) ListModels(ctx context.Context, client *http.Client, cfg config.AIConfig) ([]string, error) {
+	header := http.Header{}
+	if cfg.APIKey != "" {
+		header.Set("x-goog-api-key", cfg.APIKey)
+	}
+	return fetchModels(ctx, client, geminiBase(cfg)+"/models?pageSize=1000", header)
+}
+
+func (ollamaProvider) ListModels(ctx context.Context, client *http.Client, cfg config.AIConfig) ([]string, error) {
+	return fetchModels(ctx, client, ollamaBase(cfg)+"/api/tags", http.Header{})
+}
+
+// fetchModels reads a model list, it understands the OpenAI and Anthropic
+// "data" list as well as the Gemini and Ollama "models" list.
+func fetchModels(ctx context.Context, client *http.Client, url string, header http.Header) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+
+	c := *client
+	c.Timeout = 15 * time.Second
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error: %s - %s", resp.Status, string(body))
+	}
+
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"` // OpenAI and Anthropic
+		Models []struct {
+			Name    string   `json:"name"`
+			Methods []string `json:"supportedGenerationMethods"`
+		} `json:"models"` // Gemini and Ollama
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	models := make([]string, 0, len(result.Data)+len(result.Models))
+	for _, model := range result.Data {
+		models = append(models, model.ID)
+	}
+	for _, model := range result.Models {
+		// Gemini also lists embedding models that cannot generate text
+		if len(model.Methods) > 0 && !slices.Contains(model.Methods, "generateContent") {
+			continue
+		}
+		models = append(models, strings.TrimPrefix(model.Name, "models/"))
+	}
+	sort.Strings(models)
+	return models, nil
+}
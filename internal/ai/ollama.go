@@ -0,0 +1,128 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"Kairo/internal/config"
+)
+
+const (
+	ollamaBaseURL = "http://localhost:11434"
+	// Local models on modest hardware can take many minutes for a long
+	// transcript, loading the model alone may take a while
+	ollamaTimeout   = 30 * time.Minute
+	ollamaKeepAlive = "10m" // Keeps the model loaded between translation batches
+	ollamaMinCtx    = 8192
+	ollamaMaxCtx    = 32768
+)
+
+// ollamaBase strips the /v1 suffix of Ollama's OpenAI compatible endpoint,
+// the native API lives at the root.
+func ollamaBase(cfg config.AIConfig) string {
+	base := strings.TrimRight(cfg.BaseURL, "/")
+	base = strings.TrimSuffix(base, "/v1")
+	if base == "" {
+		return ollamaBaseURL
+	}
+	return base
+}
+
+// ollamaContextSize sizes the context window for the prompt. Ollama
+// silently truncates prompts beyond its small default window, which would
+// cut off the subtitles of longer videos.
+func ollamaContextSize(prompt string) int {
+	// Roughly three bytes per token across latin and CJK text, plus room
+	// for the answer
+	need := len(prompt)/3 + 4096
+	size := ollamaMinCtx
+	for size < need && size < ollamaMaxCtx {
+		size *= 2
+	}
+	return min(size, ollamaMaxCtx)
+}
+
+type ollamaProvider struct{}
+
+func (ollamaProvider) Timeout() time.Duration { return ollamaTimeout }
+
+// NewRequest builds a request to Ollama's native chat API.
+func (ollamaProvider) NewRequest(ctx context.Context, cfg config.AIConfig, prompt string, jsonMode bool) (*http.Request, error) {
+	url := ollamaBase(cfg) + "/api/chat"
+
+	reqBody := map[string]interface{}{
+		"model": cfg.ModelName,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+		"stream":     false,
+		"keep_alive": ollamaKeepAlive,
+		"options": map[string]int{
+			"num_ctx": ollamaContextSize(prompt),
+		},
+	}
+	if jsonMode {
+		reqBody["format"] = "json"
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.APIKey != "" {
+		// Ollama behind an authenticating reverse proxy
+		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
+	}
+	return req, nil
+}
+
+func (ollamaProvider) ParseResponse(cfg config.AIConfig, body []byte) (*Completion, error) {
+	var result struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		DoneReason      string `json:"done_reason"`
+		PromptEvalCount int    `json:"prompt_eval_count"`
+		EvalCount       int    `json:"eval_count"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	if result.DoneReason == "length" {
+		return nil, fmt.Errorf("%w: output reached the context window", ErrAITruncated)
+	}
+	if result.Message.Content == "" {
+		return nil, fmt.Errorf("no response from AI")
+	}
+
+	return &Completion{
+		Content:          result.Message.Content,
+		PromptTokens:     result.PromptEvalCount,
+		CompletionTokens: result.EvalCount,
+	}, nil
+}
+
+func (ollamaProvider) ParseError(resp *http.Response, body []byte) error {
+	var payload struct {
+		Error string `json:"error"`
+	}
+	message := string(body)
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error != "" {
+		message = payload.Error
+	}
+	return statusError(resp, nil, message)
+}
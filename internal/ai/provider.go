@@ -0,0 +1,111 @@
+package ai
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"Kairo/internal/config"
+)
+
+// completionTimeout bounds one completion call of the hosted providers.
+const completionTimeout = 5 * time.Minute
+
+// Provider speaks the API of one AI vendor. Implementations only build the
+// request and parse the response, sending, logging, retries, rate limiting
+// and usage accounting are shared.
+type Provider interface {
+	// NewRequest builds the request answering prompt, in JSON mode with
+	// one JSON object.
+	NewRequest(ctx context.Context, cfg config.AIConfig, prompt string, jsonMode bool) (*http.Request, error)
+	// ParseResponse reads the completion out of a successful response.
+	ParseResponse(cfg config.AIConfig, body []byte) (*Completion, error)
+	// ParseError maps an error response onto the provider errors.
+	ParseError(resp *http.Response, body []byte) error
+	// Timeout bounds one completion call.
+	Timeout() time.Duration
+	// ListModels returns the models the endpoint of cfg serves.
+	ListModels(ctx context.Context, client *http.Client, cfg config.AIConfig) ([]string, error)
+}
+
+// Completion is the answer of a provider with the tokens it was billed.
+type Completion struct {
+	Content          string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = make(map[string]Provider)
+)
+
+// RegisterProvider makes a provider available under the name used in the
+// provider setting, replacing any provider registered before.
+func RegisterProvider(name string, p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[name] = p
+}
+
+// providerFor returns the provider registered for name. Unknown names
+// such as "custom" speak the OpenAI protocol.
+func providerFor(name string) Provider {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	if p, ok := providers[name]; ok {
+		return p
+	}
+	return providers["openai"]
+}
+
+// send makes one completion call through p.
+func (m *Manager) send(ctx context.Context, p Provider, cfg config.AIConfig, prompt string, jsonMode bool) (*Completion, error) {
+	req, err := p.NewRequest(ctx, cfg, prompt, jsonMode)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := prompt
+	if len(preview) > 5000 {
+		preview = preview[:5000] + "..."
+	}
+	log.Printf("[AI Request] URL: %s\nModel: %s\nPrompt: %s\n", req.URL, cfg.ModelName, preview)
+
+	client := *m.client
+	client.Timeout = p.Timeout()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, p.ParseError(resp, body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result, err := p.ParseResponse(cfg, body)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[AI Response] Content: %s\n", result.Content)
+	return result, nil
+}
+
+func init() {
+	openAI := openAIProvider{}
+	RegisterProvider("openai", openAI)
+	RegisterProvider("deepseek", openAI)
+	RegisterProvider("siliconflow", openAI)
+	RegisterProvider("custom", openAI)
+	RegisterProvider("local", openAI) // LM Studio, llama.cpp, vLLM and other compatible servers
+	RegisterProvider("anthropic", anthropicProvider{})
+	RegisterProvider("gemini", geminiProvider{})
+	RegisterProvider("ollama", ollamaProvider{})
+}
@@ -2,10 +2,9 @@ package ai
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -13,7 +12,11 @@ import (
 	"Kairo/internal/config"
 )
 
-func (m *Manager) callOpenAI(cfg config.AIConfig, prompt string, jsonMode bool) (string, error) {
+type openAIProvider struct{}
+
+func (openAIProvider) Timeout() time.Duration { return completionTimeout }
+
+func (openAIProvider) NewRequest(ctx context.Context, cfg config.AIConfig, prompt string, jsonMode bool) (*http.Request, error) {
 	url := fmt.Sprintf("%s/chat/completions", strings.TrimRight(cfg.BaseURL, "/"))
 
 	reqBody := map[string]interface{}{
@@ -28,62 +31,182 @@ func (m *Manager) callOpenAI(cfg config.AIConfig, prompt string, jsonMode bool)
 
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", err
-	}
-
-	preview := prompt
-	if len(preview) > 5000 {
-		preview = preview[:5000] + "..."
+		return nil, err
 	}
-	log.Printf("[AI Request] URL: %s\nModel: %s\nPrompt: %s\n", url, cfg.ModelName, preview)
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	if cfg.APIKey != "" {
 		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
 	}
+	return req, nil
+}
 
-	client := *m.client
-	client.Timeout = 5 * time.Minute
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API error: %s - %s", resp.Status, string(body))
-	}
-
+func (openAIProvider) ParseResponse(cfg config.AIConfig, body []byte) (*Completion, error) {
 	var result struct {
 		Choices []struct {
 			Message struct {
 				Content string `json:"content"`
 			} `json:"message"`
 		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", err
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
 	}
 
 	if len(result.Choices) == 0 {
-		return "", fmt.Errorf("no response from AI")
+		return nil, fmt.Errorf("no response from AI")
+	}
+
+	return &Completion{
+		Content:          result.Choices[0].Message.Content,
+		PromptTokens:     result.Usage.PromptTokens,
+		CompletionTokens: result.Usage.CompletionTokens,
+	}, nil
+}
+
+func (openAIProvider) ParseError(resp *http.Response, body []byte) error {
+	return statusError(resp, nil, string(body))
+}
+
+const (
+	anthropicVersion = "2023-06-01"
+	jsonSystemPrompt = "Respond with a single valid JSON object only. Do not wrap it in markdown code fences and do not add any text before or after it."
+)
+
+// anthropicLegacyModels stop at 4096 output tokens, later models allow
+// at least 8192.
+var anthropicLegacyModels = []string{"claude-3-haiku", "claude-3-opus", "claude-3-sonnet", "claude-2", "claude-instant"}
+
+// anthropicMaxTokens returns the output limit requested for model.
+func anthropicMaxTokens(model string) int {
+	model = strings.ToLower(model)
+	for _, fragment := range anthropicLegacyModels {
+		if strings.Contains(model, fragment) {
+			return 4096
+		}
 	}
+	return 8192
+}
 
-	content := result.Choices[0].Message.Content
-	log.Printf("[AI Response] Content: %s\n", content)
-	return content, nil
+func anthropicBase(cfg config.AIConfig) string {
+	base := strings.TrimRight(cfg.BaseURL, "/")
+	if base == "" {
+		return "https://api.anthropic.com/v1"
+	}
+	if !strings.HasSuffix(base, "/v1") {
+		base += "/v1"
+	}
+	return base
 }
 
-func (m *Manager) callAnthropic(cfg config.AIConfig, prompt string) (string, error) {
-	return "", fmt.Errorf("Anthropic provider not fully implemented yet")
+type anthropicProvider struct{}
+
+func (anthropicProvider) Timeout() time.Duration { return completionTimeout }
+
+// NewRequest builds a Messages API request. In JSON mode a system prompt
+// restricts the answer to one JSON object, Anthropic has no
+// response_format switch.
+func (anthropicProvider) NewRequest(ctx context.Context, cfg config.AIConfig, prompt string, jsonMode bool) (*http.Request, error) {
+	url := anthropicBase(cfg) + "/messages"
+
+	reqBody := map[string]interface{}{
+		"model":      cfg.ModelName,
+		"max_tokens": anthropicMaxTokens(cfg.ModelName),
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+	if jsonMode {
+		reqBody["system"] = jsonSystemPrompt
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("anthropic-version", anthropicVersion)
+	if cfg.APIKey != "" {
+		req.Header.Set("x-api-key", cfg.APIKey)
+	}
+	return req, nil
+}
+
+func (anthropicProvider) ParseResponse(cfg config.AIConfig, body []byte) (*Completion, error) {
+	var result struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		StopReason string `json:"stop_reason"`
+		Usage      struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	var content strings.Builder
+	for _, block := range result.Content {
+		if block.Type == "text" {
+			content.WriteString(block.Text)
+		}
+	}
+	if content.Len() == 0 {
+		return nil, fmt.Errorf("no response from AI")
+	}
+	if result.StopReason == "max_tokens" {
+		// A cut off JSON object cannot be parsed, let the caller retry
+		// with less input
+		return nil, fmt.Errorf("%w: output reached %d tokens", ErrAITruncated, anthropicMaxTokens(cfg.ModelName))
+	}
+
+	return &Completion{
+		Content:          content.String(),
+		PromptTokens:     result.Usage.InputTokens,
+		CompletionTokens: result.Usage.OutputTokens,
+	}, nil
+}
+
+// ParseError maps an error response onto the provider errors.
+func (anthropicProvider) ParseError(resp *http.Response, body []byte) error {
+	var payload struct {
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	message := string(body)
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error.Message != "" {
+		message = payload.Error.Type + ": " + payload.Error.Message
+	}
+
+	switch payload.Error.Type {
+	case "rate_limit_error":
+		return statusError(resp, ErrAIRateLimited, message)
+	case "overloaded_error":
+		return statusError(resp, ErrAIOverloaded, message)
+	}
+	return statusError(resp, nil, message)
 }
 
 func sanitizeJSONContent(content string) string {
@@ -0,0 +1,191 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"Kairo/internal/deps"
+)
+
+const (
+	aiMaxAttempts = 4
+	aiRetryBase   = 2 * time.Second
+	aiRetryMax    = time.Minute
+	// Calls in flight per provider when providerConcurrency has no entry,
+	// shared by analysis, translation and transcription
+	defaultProviderConcurrency = 4
+)
+
+// providerConcurrency overrides the calls in flight of a provider. Local
+// engines already use the whole GPU or CPU, parallel calls only queue up.
+var providerConcurrency = map[string]int{
+	"local":                  1,
+	"ollama":                 1,
+	deps.WhisperEngineCpp:    1,
+	deps.WhisperEngineFaster: 1,
+}
+
+// providerError is a failed call worth retrying, it wraps ErrAIRateLimited
+// or ErrAIOverloaded and keeps the Retry-After hint of the response.
+type providerError struct {
+	kind       error
+	message    string
+	retryAfter time.Duration
+}
+
+func (e *providerError) Error() string {
+	return fmt.Sprintf("%v: %s", e.kind, e.message)
+}
+
+func (e *providerError) Unwrap() error {
+	return e.kind
+}
+
+// statusError maps a failed response onto the provider errors. A nil kind
+// is derived from the status, 429 is rate limited and 5xx overloaded.
+func statusError(resp *http.Response, kind error, message string) error {
+	if kind == nil {
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			kind = ErrAIRateLimited
+		case resp.StatusCode >= 500:
+			kind = ErrAIOverloaded
+		default:
+			return fmt.Errorf("API error: %s - %s", resp.Status, message)
+		}
+	}
+	return &providerError{
+		kind:       kind,
+		message:    message,
+		retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+}
+
+// parseRetryAfter reads both forms of Retry-After, seconds and HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
+
+func retryable(err error) bool {
+	return errors.Is(err, ErrAIRateLimited) || errors.Is(err, ErrAIOverloaded)
+}
+
+// retryDelay follows the Retry-After hint and backs off exponentially
+// with jitter otherwise.
+func retryDelay(err error, attempt int) time.Duration {
+	var perr *providerError
+	if errors.As(err, &perr) && perr.retryAfter > 0 {
+		return min(perr.retryAfter, aiRetryMax)
+	}
+	delay := min(aiRetryBase<<(attempt-1), aiRetryMax)
+	return delay/2 + rand.N(delay/2)
+}
+
+// providerLimiter bounds the calls in flight to one provider across the
+// process and holds every caller back once the provider rate limits.
+type providerLimiter struct {
+	slots    chan struct{}
+	mu       sync.Mutex
+	resumeAt time.Time
+}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = make(map[string]*providerLimiter)
+)
+
+func limiterFor(provider string) *providerLimiter {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	l, ok := limiters[provider]
+	if !ok {
+		size := defaultProviderConcurrency
+		if n, ok := providerConcurrency[provider]; ok && n > 0 {
+			size = n
+		}
+		l = &providerLimiter{slots: make(chan struct{}, size)}
+		limiters[provider] = l
+	}
+	return l
+}
+
+func (l *providerLimiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	l.mu.Lock()
+	wait := time.Until(l.resumeAt)
+	l.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release()
+		return ctx.Err()
+	}
+}
+
+func (l *providerLimiter) release() {
+	<-l.slots
+}
+
+// pause holds back the calls to the provider for d.
+func (l *providerLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if at := time.Now().Add(d); at.After(l.resumeAt) {
+		l.resumeAt = at
+	}
+}
+
+// call runs fn within the limiter of provider and retries it while the
+// provider is rate limited or overloaded. It returns the attempts made.
+func (m *Manager) call(ctx context.Context, provider string, fn func() error) (int, error) {
+	limiter := limiterFor(provider)
+	for attempt := 1; ; attempt++ {
+		if err := limiter.acquire(ctx); err != nil {
+			return attempt - 1, err
+		}
+		err := fn()
+		limiter.release()
+		if err == nil || !retryable(err) || attempt == aiMaxAttempts {
+			return attempt, err
+		}
+
+		delay := retryDelay(err, attempt)
+		if errors.Is(err, ErrAIRateLimited) {
+			limiter.pause(delay)
+		}
+		log.Printf("[AI] %s attempt %d failed, retrying in %s: %v", provider, attempt, delay.Round(time.Millisecond), err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, ctx.Err()
+		}
+	}
+}
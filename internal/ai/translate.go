@@ -1,20 +1,23 @@
 package ai
 
 import (
+	"context"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
 	"sync"
 
 	"Kairo/internal/config"
+	"Kairo/internal/db/schema"
 )
 
 //go:embed prompts/translate.txt
 var defaultTranslatePrompt string
 
-func (m *Manager) TranslateSegments(targetLanguage string, segments []string) ([]string, error) {
+func (m *Manager) TranslateSegments(ctx context.Context, videoID, targetLanguage string, segments []string) ([]string, error) {
 	settings := config.GetSettings()
 	if !settings.TranslateAI.Enabled {
 		return nil, ErrAIDisabled
@@ -53,7 +56,7 @@ func (m *Manager) TranslateSegments(targetLanguage string, segments []string) ([
 			}
 			mu.Unlock()
 
-			translated, err := m.translateBatch(settings, targetLanguage, segs)
+			translated, err := m.translateSplitting(ctx, settings, videoID, targetLanguage, segs)
 
 			mu.Lock()
 			defer mu.Unlock()
@@ -84,7 +87,26 @@ func (m *Manager) TranslateSegments(targetLanguage string, segments []string) ([
 	return finalTranslations, nil
 }
 
-func (m *Manager) translateBatch(settings config.AppSettings, targetLanguage string, segments []string) ([]string, error) {
+// translateSplitting translates a batch, halving it while the answer is cut
+// off by the output limit of the model.
+func (m *Manager) translateSplitting(ctx context.Context, settings config.AppSettings, videoID, targetLanguage string, segments []string) ([]string, error) {
+	translated, err := m.translateBatch(ctx, settings, videoID, targetLanguage, segments)
+	if !errors.Is(err, ErrAITruncated) || len(segments) < 2 {
+		return translated, err
+	}
+	half := len(segments) / 2
+	first, err := m.translateSplitting(ctx, settings, videoID, targetLanguage, segments[:half])
+	if err != nil {
+		return nil, err
+	}
+	second, err := m.translateSplitting(ctx, settings, videoID, targetLanguage, segments[half:])
+	if err != nil {
+		return nil, err
+	}
+	return append(first, second...), nil
+}
+
+func (m *Manager) translateBatch(ctx context.Context, settings config.AppSettings, videoID, targetLanguage string, segments []string) ([]string, error) {
 	payload, _ := json.Marshal(segments)
 
 	prompt := defaultTranslatePrompt
@@ -94,16 +116,7 @@ func (m *Manager) translateBatch(settings config.AppSettings, targetLanguage str
 		prompt = prompt + "\n\n" + settings.TranslateAI.Prompt
 	}
 
-	var content string
-	var err error
-	switch settings.TranslateAI.Provider {
-	case "openai", "local", "custom", "deepseek", "siliconflow":
-		content, err = m.callOpenAI(settings.TranslateAI, prompt, true)
-	case "anthropic":
-		content, err = m.callAnthropic(settings.TranslateAI, prompt)
-	default:
-		content, err = m.callOpenAI(settings.TranslateAI, prompt, true)
-	}
+	content, err := m.complete(ctx, settings.TranslateAI, schema.AIPurposeTranslation, videoID, prompt, true)
 	if err != nil {
 		log.Printf("[Translate] Error calling AI provider: %v", err)
 		return nil, err
@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"Kairo/internal/config"
+	"Kairo/internal/db/schema"
+
+	"github.com/google/uuid"
+)
+
+// complete sends the prompt to the provider of cfg and records the call
+// for videoID under purpose.
+func (m *Manager) complete(ctx context.Context, cfg config.AIConfig, purpose schema.AIUsagePurpose, videoID, prompt string, jsonMode bool) (string, error) {
+	provider := providerFor(cfg.Provider)
+	var result *Completion
+	start := time.Now()
+	attempts, err := m.call(ctx, cfg.Provider, func() error {
+		var err error
+		result, err = m.send(ctx, provider, cfg, prompt, jsonMode)
+		return err
+	})
+
+	usage := &schema.AIUsage{
+		Provider: cfg.Provider,
+		Model:    cfg.ModelName,
+		Purpose:  purpose,
+		VideoID:  videoID,
+		Attempts: attempts,
+	}
+	if err != nil {
+		usage.Error = err.Error()
+	} else {
+		usage.PromptTokens = result.PromptTokens
+		usage.CompletionTokens = result.CompletionTokens
+	}
+	m.recordUsage(usage, start)
+
+	if err != nil {
+		return "", err
+	}
+	return result.Content, nil
+}
+
+func (m *Manager) recordUsage(usage *schema.AIUsage, start time.Time) {
+	if m.usageDAL == nil {
+		return
+	}
+	usage.ID = uuid.New().String()
+	usage.LatencyMs = time.Since(start).Milliseconds()
+	usage.Day = start.Format(time.DateOnly)
+	if err := m.usageDAL.Create(m.ctx, usage); err != nil {
+		log.Printf("[AI] Failed to record usage: %v", err)
+	}
+}
+
+// GetUsage sums the AI calls of the last days by day and by feature.
+func (m *Manager) GetUsage(days int) (*schema.AIUsageReport, error) {
+	if m.usageDAL == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	if days <= 0 {
+		days = 30
+	}
+	now := time.Now()
+	since := time.Date(now.Year(), now.Month(), now.Day()-days+1, 0, 0, 0, 0, now.Location()).Unix()
+
+	byDay, err := m.usageDAL.SumBy(m.ctx, "day", since)
+	if err != nil {
+		return nil, err
+	}
+	byPurpose, err := m.usageDAL.SumBy(m.ctx, "purpose", since)
+	if err != nil {
+		return nil, err
+	}
+	return &schema.AIUsageReport{Since: since, ByDay: byDay, ByPurpose: byPurpose}, nil
+}
@@ -2,6 +2,7 @@ package ai
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,6 +15,8 @@ import (
 	"time"
 
 	"Kairo/internal/config"
+	"Kairo/internal/db/schema"
+	"Kairo/internal/deps"
 )
 
 type whisperVerboseResponse struct {
@@ -24,8 +27,17 @@ type whisperVerboseResponse struct {
 	} `json:"segments"`
 }
 
-func (m *Manager) TranscribeWhisper(filePath string) (string, error) {
+// TranscribeWhisper transcribes filePath into VTT. Local engines report
+// their progress in percent, a remote API does not.
+func (m *Manager) TranscribeWhisper(ctx context.Context, videoID, filePath string, progress func(float64)) (string, error) {
 	cfg := config.GetSettings().WhisperAI
+	if deps.IsWhisperEngine(cfg.Provider) {
+		if !cfg.Enabled {
+			return "", ErrWhisperDisabled
+		}
+		return m.transcribeLocal(ctx, cfg, videoID, filePath, progress)
+	}
+
 	model := cfg.ModelName
 	if model == "" {
 		model = "whisper-1"
@@ -36,7 +48,7 @@ func (m *Manager) TranscribeWhisper(filePath string) (string, error) {
 		responseFormat = "verbose_json"
 	}
 
-	data, err := m.transcribeWhisperRaw(filePath, responseFormat)
+	data, err := m.transcribeWhisperRaw(ctx, videoID, filePath, responseFormat)
 	log.Printf("[TranscribeWhisper] transcribe whisper raw, responseFormat: %s", responseFormat)
 	if err != nil {
 		log.Printf("[TranscribeWhisper] Error transcribing whisper raw: %v", err)
@@ -88,7 +100,7 @@ func formatVttTimestamp(seconds float64) string {
 	return fmt.Sprintf("%02d:%02d:%02d.%03d", hours, minutes, secs, millis)
 }
 
-func (m *Manager) transcribeWhisperRaw(filePath string, responseFormat string) ([]byte, error) {
+func (m *Manager) transcribeWhisperRaw(ctx context.Context, videoID, filePath string, responseFormat string) ([]byte, error) {
 	cfg := config.GetSettings().WhisperAI
 	if !cfg.Enabled {
 		return nil, ErrWhisperDisabled
@@ -130,33 +142,53 @@ func (m *Manager) transcribeWhisperRaw(filePath string, responseFormat string) (
 	}
 
 	url := fmt.Sprintf("%s/audio/transcriptions", strings.TrimRight(cfg.BaseURL, "/"))
-	req, err := http.NewRequest("POST", url, body)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	if cfg.APIKey != "" {
-		switch strings.ToLower(cfg.Provider) {
-		case "openai", "local", "siliconflow":
-			req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
-		default:
-			req.Header.Set("api-key", cfg.APIKey)
-		}
-	}
-
 	client := *m.client
 	client.Timeout = 5 * time.Minute
-	resp, err := client.Do(req)
+
+	var data []byte
+	start := time.Now()
+	attempts, err := m.call(ctx, cfg.Provider, func() error {
+		// The form is sent again on every attempt
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		if cfg.APIKey != "" {
+			switch strings.ToLower(cfg.Provider) {
+			case "openai", "local", "siliconflow":
+				req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
+			default:
+				req.Header.Set("api-key", cfg.APIKey)
+			}
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			respBody, _ := io.ReadAll(resp.Body)
+			return statusError(resp, nil, string(respBody))
+		}
+		data, err = io.ReadAll(resp.Body)
+		return err
+	})
+
+	usage := &schema.AIUsage{
+		Provider: cfg.Provider,
+		Model:    model,
+		Purpose:  schema.AIPurposeTranscription,
+		VideoID:  videoID,
+		Attempts: attempts,
+	}
 	if err != nil {
-		return nil, err
+		usage.Error = err.Error()
 	}
-	defer resp.Body.Close()
+	m.recordUsage(usage, start)
 
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error: %s - %s", resp.Status, string(respBody))
-	}
-	data, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
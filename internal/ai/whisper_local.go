@@ -0,0 +1,205 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"Kairo/internal/config"
+	"Kairo/internal/db/schema"
+	"Kairo/internal/deps"
+	"Kairo/internal/utils"
+)
+
+const defaultLocalWhisperModel = "base"
+
+// whisperProgressPattern matches the progress lines of the engines,
+// "progress = 42%" of whisper.cpp with --print-progress and the tqdm bar
+// " 42%|####  |" of faster-whisper with --print_progress. Other lines
+// mentioning a percentage stay in the error tail.
+var whisperProgressPattern = regexp.MustCompile(`progress\s*=\s*(\d{1,3})%|^(\d{1,3})%\|`)
+
+// transcribeLocal runs a local whisper executable on filePath and returns
+// the VTT it writes. Audio never leaves the machine.
+func (m *Manager) transcribeLocal(ctx context.Context, cfg config.AIConfig, videoID, filePath string, progress func(float64)) (string, error) {
+	if m.deps == nil {
+		return "", fmt.Errorf("local whisper is not available")
+	}
+	binary, err := m.deps.GetWhisperPath(cfg.Provider)
+	if err != nil {
+		return "", err
+	}
+	model := cfg.ModelName
+	if model == "" || model == "whisper-1" {
+		model = defaultLocalWhisperModel
+	}
+
+	outDir, err := os.MkdirTemp("", "kairo-asr-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(outDir)
+
+	var args []string
+	switch cfg.Provider {
+	case deps.WhisperEngineCpp:
+		// A first use downloads the model, its progress is reported before
+		// the transcription starts over at zero
+		modelPath, err := m.deps.GetWhisperModelPath(ctx, model, progress)
+		if err != nil {
+			return "", err
+		}
+		args = []string{
+			"-m", modelPath,
+			"-f", filePath,
+			"-l", "auto",
+			"-ovtt",
+			"-of", filepath.Join(outDir, "transcript"),
+			"--print-progress",
+		}
+		if cfg.Prompt != "" {
+			args = append(args, "--prompt", cfg.Prompt)
+		}
+	case deps.WhisperEngineFaster:
+		modelDir, err := m.deps.GetWhisperModelDir()
+		if err != nil {
+			return "", err
+		}
+		args = []string{
+			filePath,
+			"--model", model,
+			"--model_dir", modelDir,
+			"--output_format", "vtt",
+			"--output_dir", outDir,
+			"--print_progress",
+		}
+		if cfg.Prompt != "" {
+			args = append(args, "--initial_prompt", cfg.Prompt)
+		}
+	default:
+		return "", fmt.Errorf("unknown whisper engine %q", cfg.Provider)
+	}
+
+	start := time.Now()
+	attempts, err := m.call(ctx, cfg.Provider, func() error {
+		return m.runWhisper(ctx, binary, args, progress)
+	})
+	var content []byte
+	if err == nil {
+		content, err = readTranscript(outDir)
+	}
+
+	usage := &schema.AIUsage{
+		Provider: cfg.Provider,
+		Model:    model,
+		Purpose:  schema.AIPurposeTranscription,
+		VideoID:  videoID,
+		Attempts: attempts,
+	}
+	if err != nil {
+		usage.Error = err.Error()
+	}
+	m.recordUsage(usage, start)
+
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// runWhisper runs the engine and reports the progress it prints, the tail
+// of its output explains a failure.
+func (m *Manager) runWhisper(ctx context.Context, binary string, args []string, progress func(float64)) error {
+	cmd := utils.CreateCommandContext(ctx, binary, args...)
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan []string)
+	go func() {
+		var tail []string
+		last := -1
+		sc := bufio.NewScanner(pr)
+		sc.Split(scanLinesOrCR)
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			if line == "" {
+				continue
+			}
+			if match := whisperProgressPattern.FindStringSubmatch(line); match != nil {
+				if p, err := strconv.Atoi(match[1] + match[2]); err == nil && p > last && p <= 100 {
+					last = p
+					if progress != nil {
+						progress(float64(p))
+					}
+				}
+				continue
+			}
+			tail = append(tail, line)
+			if len(tail) > 20 {
+				tail = tail[1:]
+			}
+		}
+		// Drain the rest so the process never blocks on a full pipe
+		_, _ = io.Copy(io.Discard, pr)
+		done <- tail
+	}()
+
+	err := cmd.Wait()
+	pw.Close()
+	tail := <-done
+	if err != nil {
+		log.Printf("[TranscribeWhisper] local whisper failed: %v\n%s", err, strings.Join(tail, "\n"))
+		if len(tail) > 0 {
+			return fmt.Errorf("whisper failed: %v: %s", err, tail[len(tail)-1])
+		}
+		return fmt.Errorf("whisper failed: %v", err)
+	}
+	return nil
+}
+
+// readTranscript returns the VTT file the engine wrote into dir.
+func readTranscript(dir string) ([]byte, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.vtt"))
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("whisper wrote no subtitles")
+	}
+	content, err := os.ReadFile(matches[0])
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Contains(content, []byte("-->")) {
+		return nil, fmt.Errorf("whisper found no speech")
+	}
+	return content, nil
+}
+
+// scanLinesOrCR splits on newlines and carriage returns, progress bars
+// redraw their line with \r.
+func scanLinesOrCR(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+
+	"Kairo/internal/db/schema"
+)
+
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := pageParams(r)
+	q := r.URL.Query()
+	resp, err := s.opts.Jobs.ListJobs(schema.JobFilter{
+		Type:     q.Get("type"),
+		Status:   q.Get("status"),
+		RefID:    q.Get("ref_id"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	data := resp.Data
+	if data == nil {
+		data = []schema.Job{}
+	}
+	writeJSON(w, http.StatusOK, pageResponse[schema.Job]{
+		Total:    resp.Total,
+		Page:     page,
+		PageSize: pageSize,
+		Data:     data,
+	})
+}
+
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	job, err := s.opts.Jobs.GetJob(r.PathValue("id"))
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (s *Server) handleCancelJob(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.Jobs.Cancel(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handleRerunJob(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.Jobs.Rerun(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
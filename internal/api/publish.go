@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+
+	"Kairo/internal/db/schema"
+)
+
+func (s *Server) handleListPublishAccounts(w http.ResponseWriter, r *http.Request) {
+	platformID := r.URL.Query().Get("platform_id")
+	if platformID == "" {
+		platformID = "all"
+	}
+	accounts, err := s.opts.PublishManager.ListAccounts(platformID)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]schema.PublishAccount{"data": accounts})
+}
+
+func (s *Server) handleListPublishTasks(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := pageParams(r)
+	resp, err := s.opts.PublishManager.ListTasks(r.URL.Query().Get("status"), r.URL.Query().Get("platform_id"), page, pageSize)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	data := resp.Data
+	if data == nil {
+		data = []schema.PublishTask{}
+	}
+	writeJSON(w, http.StatusOK, pageResponse[schema.PublishTask]{
+		Total:    resp.Total,
+		Page:     page,
+		PageSize: pageSize,
+		Data:     data,
+	})
+}
+
+func (s *Server) handleCreatePublishTask(w http.ResponseWriter, r *http.Request) {
+	var req schema.CreatePublishTaskRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	t, err := s.opts.PublishManager.CreateTask(req)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, t)
+}
+
+func (s *Server) handleDeletePublishTask(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.PublishManager.DeleteTask(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handleCancelPublishTask(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.PublishManager.CancelTask(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handleRetryPublishTask(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.PublishManager.RetryTask(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handlePublishTaskNow(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.PublishManager.PublishTaskNow(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
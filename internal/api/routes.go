@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+)
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/v1/health", s.handleHealth)
+	mux.HandleFunc("GET /api/v1/events", s.handleEvents)
+
+	// Tasks
+	mux.HandleFunc("GET /api/v1/tasks", s.handleListTasks)
+	mux.HandleFunc("POST /api/v1/tasks", s.handleAddTask)
+	mux.HandleFunc("POST /api/v1/tasks/playlist", s.handleAddPlaylistTask)
+	mux.HandleFunc("POST /api/v1/tasks/rss", s.handleAddRSSTask)
+	mux.HandleFunc("GET /api/v1/tasks/queue", s.handleGetTaskQueue)
+	mux.HandleFunc("GET /api/v1/tasks/queue/status", s.handleGetQueueStatus)
+	mux.HandleFunc("GET /api/v1/tasks/{id}", s.handleGetTask)
+	mux.HandleFunc("DELETE /api/v1/tasks/{id}", s.handleDeleteTask)
+	mux.HandleFunc("POST /api/v1/tasks/{id}/pause", s.handlePauseTask)
+	mux.HandleFunc("POST /api/v1/tasks/{id}/resume", s.handleResumeTask)
+	mux.HandleFunc("POST /api/v1/tasks/{id}/retry", s.handleRetryTask)
+	mux.HandleFunc("POST /api/v1/tasks/{id}/move", s.handleMoveTask)
+	mux.HandleFunc("PUT /api/v1/tasks/{id}/priority", s.handleSetTaskPriority)
+	mux.HandleFunc("GET /api/v1/tasks/{id}/logs", s.handleGetTaskLogs)
+	mux.HandleFunc("POST /api/v1/tasks/{id}/sync", s.handleSyncPlaylist)
+	mux.HandleFunc("PUT /api/v1/tasks/{id}/sync", s.handleSetPlaylistSyncInterval)
+	mux.HandleFunc("POST /api/v1/tasks/{id}/group/pause", s.handlePauseGroup)
+	mux.HandleFunc("POST /api/v1/tasks/{id}/group/resume", s.handleResumeGroup)
+	mux.HandleFunc("POST /api/v1/tasks/{id}/group/retry-failed", s.handleRetryFailedInGroup)
+	mux.HandleFunc("POST /api/v1/tasks/{id}/group/cancel", s.handleCancelGroup)
+	mux.HandleFunc("GET /api/v1/video-info", s.handleGetVideoInfo)
+
+	// Library
+	mux.HandleFunc("GET /api/v1/videos", s.handleListVideos)
+	mux.HandleFunc("GET /api/v1/videos/{id}", s.handleGetVideo)
+	mux.HandleFunc("DELETE /api/v1/videos/{id}", s.handleDeleteVideo)
+	mux.HandleFunc("GET /api/v1/videos/{id}/subtitles", s.handleGetVideoSubtitles)
+	mux.HandleFunc("POST /api/v1/videos/{id}/subtitles/fetch", s.handleFetchSubtitles)
+	mux.HandleFunc("GET /api/v1/videos/{id}/highlights", s.handleGetVideoHighlights)
+	mux.HandleFunc("GET /api/v1/videos/{id}/pipeline", s.handleGetVideoPipeline)
+	mux.HandleFunc("POST /api/v1/videos/{id}/analyze", s.handleAnalyzeVideo)
+	mux.HandleFunc("GET /api/v1/ai/usage", s.handleGetAIUsage)
+	mux.HandleFunc("GET /api/v1/categories", s.handleListCategories)
+	mux.HandleFunc("GET /api/v1/categories/{id}/recipe", s.handleGetCategoryRecipe)
+	mux.HandleFunc("PUT /api/v1/categories/{id}/recipe", s.handleSaveCategoryRecipe)
+	mux.HandleFunc("DELETE /api/v1/categories/{id}/recipe", s.handleDeleteCategoryRecipe)
+
+	// Background jobs
+	mux.HandleFunc("GET /api/v1/jobs", s.handleListJobs)
+	mux.HandleFunc("GET /api/v1/jobs/{id}", s.handleGetJob)
+	mux.HandleFunc("POST /api/v1/jobs/{id}/cancel", s.handleCancelJob)
+	mux.HandleFunc("POST /api/v1/jobs/{id}/rerun", s.handleRerunJob)
+
+	// RSS
+	mux.HandleFunc("GET /api/v1/feeds", s.handleListFeeds)
+	mux.HandleFunc("POST /api/v1/feeds", s.handleAddFeed)
+	mux.HandleFunc("GET /api/v1/feeds/opml", s.handleExportOPML)
+	mux.HandleFunc("GET /api/v1/feeds/unhealthy", s.handleListUnhealthyFeeds)
+	mux.HandleFunc("POST /api/v1/feeds/opml", s.handleImportOPML)
+	mux.HandleFunc("POST /api/v1/feeds/prune", s.handlePruneFeedItems)
+	mux.HandleFunc("DELETE /api/v1/feeds/{id}", s.handleDeleteFeed)
+	mux.HandleFunc("GET /api/v1/feeds/{id}/items", s.handleListFeedItems)
+	mux.HandleFunc("POST /api/v1/feeds/{id}/refresh", s.handleRefreshFeed)
+	mux.HandleFunc("POST /api/v1/feeds/{id}/filters/preview", s.handlePreviewFeedFilters)
+
+	// Publish
+	mux.HandleFunc("GET /api/v1/publish/accounts", s.handleListPublishAccounts)
+	mux.HandleFunc("GET /api/v1/publish/tasks", s.handleListPublishTasks)
+	mux.HandleFunc("POST /api/v1/publish/tasks", s.handleCreatePublishTask)
+	mux.HandleFunc("DELETE /api/v1/publish/tasks/{id}", s.handleDeletePublishTask)
+	mux.HandleFunc("POST /api/v1/publish/tasks/{id}/cancel", s.handleCancelPublishTask)
+	mux.HandleFunc("POST /api/v1/publish/tasks/{id}/retry", s.handleRetryPublishTask)
+	mux.HandleFunc("POST /api/v1/publish/tasks/{id}/publish", s.handlePublishTaskNow)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not_found", "route not found")
+	})
+
+	return mux
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
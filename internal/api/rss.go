@@ -0,0 +1,122 @@
+package api
+
+import (
+	"io"
+	"net/http"
+
+	"Kairo/internal/db/schema"
+)
+
+func (s *Server) handleListFeeds(w http.ResponseWriter, r *http.Request) {
+	feeds, err := s.opts.RSSManager.GetFeeds()
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	page, pageSize := pageParams(r)
+	writeJSON(w, http.StatusOK, paginate(feeds, page, pageSize))
+}
+
+func (s *Server) handleListUnhealthyFeeds(w http.ResponseWriter, r *http.Request) {
+	feeds, err := s.opts.RSSManager.GetUnhealthyFeeds()
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	page, pageSize := pageParams(r)
+	writeJSON(w, http.StatusOK, paginate(feeds, page, pageSize))
+}
+
+func (s *Server) handlePruneFeedItems(w http.ResponseWriter, r *http.Request) {
+	result, err := s.opts.RSSManager.PruneItems()
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleAddFeed(w http.ResponseWriter, r *http.Request) {
+	var input schema.AddRSSFeedInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	feed, err := s.opts.RSSManager.AddFeed(input)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, feed)
+}
+
+// handleImportOPML takes the OPML document as request body, the folders
+// query parameter picks category (default) or tag folders.
+func (s *Server) handleImportOPML(w http.ResponseWriter, r *http.Request) {
+	mode := schema.OPMLFolderMode(r.URL.Query().Get("folders"))
+	if mode == "" {
+		mode = schema.OPMLFolderCategory
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 8<<20))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", err.Error())
+		return
+	}
+	result, err := s.opts.RSSManager.ImportOPML(data, mode)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleExportOPML(w http.ResponseWriter, r *http.Request) {
+	data, err := s.opts.RSSManager.ExportOPML()
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/x-opml; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="kairo.opml"`)
+	_, _ = w.Write(data)
+}
+
+func (s *Server) handleDeleteFeed(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.RSSManager.DeleteFeed(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handleListFeedItems(w http.ResponseWriter, r *http.Request) {
+	items, err := s.opts.RSSManager.GetFeedItems(r.PathValue("id"))
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	page, pageSize := pageParams(r)
+	writeJSON(w, http.StatusOK, paginate(items, page, pageSize))
+}
+
+func (s *Server) handleRefreshFeed(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.RSSManager.RefreshFeed(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handlePreviewFeedFilters(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Filters string `json:"filters"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	preview, err := s.opts.RSSManager.PreviewFilters(r.PathValue("id"), req.Filters)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]schema.RSSFilterPreview{"data": preview})
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"Kairo/internal/category"
+	"Kairo/internal/config"
+	"Kairo/internal/deps"
+	"Kairo/internal/events"
+	"Kairo/internal/jobs"
+	"Kairo/internal/publish"
+	"Kairo/internal/rss"
+	"Kairo/internal/task"
+	"Kairo/internal/video"
+)
+
+type Options struct {
+	Deps            *deps.Manager
+	TaskManager     *task.Manager
+	VideoManager    *video.Manager
+	RSSManager      *rss.Manager
+	CategoryManager *category.Manager
+	PublishManager  *publish.PublishManager
+	Jobs            *jobs.Queue
+	Bus             *events.Bus
+}
+
+// Server exposes the App operations over a local HTTP/JSON API so that
+// scripts and other services can drive Kairo without the UI.
+type Server struct {
+	ctx     context.Context
+	opts    Options
+	mu      sync.Mutex
+	httpSrv *http.Server
+	running config.APIConfig
+}
+
+func NewServer(ctx context.Context, opts Options) *Server {
+	return &Server{
+		ctx:  ctx,
+		opts: opts,
+	}
+}
+
+// Reload starts, stops or restarts the server to match the current settings.
+func (s *Server) Reload() error {
+	cfg := config.GetAPIConfig()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.httpSrv != nil {
+		if cfg.Enabled && cfg == s.running {
+			return nil
+		}
+		s.stopLocked()
+	}
+	if !cfg.Enabled {
+		return nil
+	}
+	if strings.TrimSpace(cfg.Token) == "" {
+		return fmt.Errorf("api token is empty")
+	}
+
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	// Event streams never finish on their own, their requests are cancelled
+	// when the server stops so Shutdown does not wait for them
+	baseCtx, closeStreams := context.WithCancel(s.ctx)
+	srv := &http.Server{
+		Handler:           s.authMiddleware(cfg.Token, s.routes()),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
+	}
+	srv.RegisterOnShutdown(closeStreams)
+	s.httpSrv = srv
+	s.running = cfg
+
+	go func() {
+		log.Printf("[API] listening on %s", addr)
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("[API] server error: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (s *Server) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopLocked()
+}
+
+func (s *Server) stopLocked() {
+	if s.httpSrv == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.httpSrv.Shutdown(ctx); err != nil {
+		log.Printf("[API] shutdown error: %v", err)
+	}
+	s.httpSrv = nil
+	s.running = config.APIConfig{}
+}
+
+func (s *Server) authMiddleware(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/health" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		provided := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		if provided == "" {
+			provided = r.Header.Get("X-Kairo-Token")
+		}
+		if provided == "" && r.URL.Path == "/api/v1/events" {
+			// EventSource clients cannot set headers, other endpoints do not
+			// take the token from the URL where it ends up in logs
+			provided = r.URL.Query().Get("token")
+		}
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "unauthorized", "missing or invalid api token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"Kairo/internal/db/schema"
+)
+
+func (s *Server) handleListTasks(w http.ResponseWriter, r *http.Request) {
+	status := strings.TrimSpace(r.URL.Query().Get("status"))
+	errorKind := strings.TrimSpace(r.URL.Query().Get("error_kind"))
+	parentID, filterParent := r.URL.Query()["parent_id"]
+
+	tasks := make([]schema.Task, 0)
+	for _, t := range s.opts.TaskManager.GetTasks() {
+		if status != "" && status != "all" && string(t.Status) != status {
+			continue
+		}
+		if filterParent && t.ParentID != parentID[0] {
+			continue
+		}
+		if errorKind != "" && string(t.ErrorKind) != errorKind {
+			continue
+		}
+		tasks = append(tasks, *t)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].CreatedAt == tasks[j].CreatedAt {
+			return tasks[i].ID < tasks[j].ID
+		}
+		return tasks[i].CreatedAt > tasks[j].CreatedAt
+	})
+
+	page, pageSize := pageParams(r)
+	writeJSON(w, http.StatusOK, paginate(tasks, page, pageSize))
+}
+
+func (s *Server) handleGetTask(w http.ResponseWriter, r *http.Request) {
+	t, err := s.opts.TaskManager.GetTask(r.PathValue("id"))
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (s *Server) handleAddTask(w http.ResponseWriter, r *http.Request) {
+	var input schema.AddTaskInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	id, err := s.opts.TaskManager.AddTask(input)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"id": id})
+}
+
+func (s *Server) handleAddPlaylistTask(w http.ResponseWriter, r *http.Request) {
+	var input schema.AddPlaylistTaskInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	id, err := s.opts.TaskManager.AddPlaylistTask(input)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"id": id})
+}
+
+func (s *Server) handleAddRSSTask(w http.ResponseWriter, r *http.Request) {
+	var input schema.AddRSSTaskInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	id, err := s.opts.TaskManager.AddRSSTask(input)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"id": id})
+}
+
+func (s *Server) handleDeleteTask(w http.ResponseWriter, r *http.Request) {
+	ids, err := s.opts.TaskManager.DeleteTask(r.PathValue("id"), queryBool(r, "delete_file"))
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"deleted_ids": ids})
+}
+
+func (s *Server) handlePauseTask(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.TaskManager.PauseTask(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handleResumeTask(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.TaskManager.ResumeTask(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handleRetryTask(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.TaskManager.RetryTask(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handleGetTaskQueue(w http.ResponseWriter, r *http.Request) {
+	tasks, err := s.opts.TaskManager.GetQueue()
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+func (s *Server) handleGetQueueStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.opts.TaskManager.GetQueueStatus())
+}
+
+func (s *Server) handleMoveTask(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Move schema.TaskMove `json:"move"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if err := s.opts.TaskManager.MoveTask(r.PathValue("id"), body.Move); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handleSetTaskPriority(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Priority int `json:"priority"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if err := s.opts.TaskManager.SetTaskPriority(r.PathValue("id"), body.Priority); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handleSyncPlaylist(w http.ResponseWriter, r *http.Request) {
+	added, err := s.opts.TaskManager.SyncPlaylist(r.PathValue("id"))
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"added": added})
+}
+
+func (s *Server) handleSetPlaylistSyncInterval(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Interval int `json:"interval"` // Minutes, 0 turns automatic syncs off
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if err := s.opts.TaskManager.SetPlaylistSyncInterval(r.PathValue("id"), body.Interval); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handlePauseGroup(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.TaskManager.PauseGroup(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handleResumeGroup(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.TaskManager.ResumeGroup(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handleRetryFailedInGroup(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.TaskManager.RetryFailedInGroup(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handleCancelGroup(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.TaskManager.CancelGroup(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handleGetTaskLogs(w http.ResponseWriter, r *http.Request) {
+	logs, err := s.opts.TaskManager.GetTaskLogs(r.PathValue("id"))
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"logs": logs})
+}
+
+func (s *Server) handleGetVideoInfo(w http.ResponseWriter, r *http.Request) {
+	url := strings.TrimSpace(r.URL.Query().Get("url"))
+	if url == "" {
+		writeError(w, http.StatusBadRequest, "invalid_query", "url is required")
+		return
+	}
+	info, err := s.opts.Deps.GetVideoInfo(url)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
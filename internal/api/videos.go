@@ -0,0 +1,137 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"Kairo/internal/db/schema"
+)
+
+func (s *Server) handleListVideos(w http.ResponseWriter, r *http.Request) {
+	filter := schema.VideoFilter{
+		Status: r.URL.Query().Get("status"),
+		Query:  r.URL.Query().Get("query"),
+		FeedID: r.URL.Query().Get("feed_id"),
+	}
+	videos, err := s.opts.VideoManager.ListVideos(filter)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	page, pageSize := pageParams(r)
+	writeJSON(w, http.StatusOK, paginate(videos, page, pageSize))
+}
+
+func (s *Server) handleGetVideo(w http.ResponseWriter, r *http.Request) {
+	v, err := s.opts.VideoManager.GetVideoById(r.PathValue("id"))
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+func (s *Server) handleDeleteVideo(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.VideoManager.DeleteVideo(r.PathValue("id"), queryBool(r, "delete_file")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handleGetVideoSubtitles(w http.ResponseWriter, r *http.Request) {
+	subs, err := s.opts.VideoManager.GetVideoSubtitles(r.PathValue("id"))
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]schema.VideoSubtitle{"data": subs})
+}
+
+func (s *Server) handleFetchSubtitles(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.VideoManager.FetchSubtitles(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
+
+func (s *Server) handleGetVideoHighlights(w http.ResponseWriter, r *http.Request) {
+	highlights, err := s.opts.VideoManager.GetHighlights(r.PathValue("id"))
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]schema.VideoHighlight{"data": highlights})
+}
+
+func (s *Server) handleGetVideoPipeline(w http.ResponseWriter, r *http.Request) {
+	steps, err := s.opts.VideoManager.GetVideoPipeline(r.PathValue("id"))
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]schema.VideoPipelineStep{"data": steps})
+}
+
+func (s *Server) handleAnalyzeVideo(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.VideoManager.AnalyzeVideo(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]bool{"ok": true})
+}
+
+func (s *Server) handleGetAIUsage(w http.ResponseWriter, r *http.Request) {
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	report, err := s.opts.VideoManager.GetAIUsage(days)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (s *Server) handleListCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := s.opts.CategoryManager.GetCategories()
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]schema.Category{"data": categories})
+}
+
+func (s *Server) handleGetCategoryRecipe(w http.ResponseWriter, r *http.Request) {
+	recipe, err := s.opts.CategoryManager.GetRecipe(r.PathValue("id"))
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	if recipe == nil {
+		writeError(w, http.StatusNotFound, "not_found", "category has no recipe")
+		return
+	}
+	writeJSON(w, http.StatusOK, recipe)
+}
+
+func (s *Server) handleSaveCategoryRecipe(w http.ResponseWriter, r *http.Request) {
+	var req schema.SaveRecipeRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	req.CategoryID = r.PathValue("id")
+	recipe, err := s.opts.CategoryManager.SaveRecipe(req)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, recipe)
+}
+
+func (s *Server) handleDeleteCategoryRecipe(w http.ResponseWriter, r *http.Request) {
+	if err := s.opts.CategoryManager.DeleteRecipe(r.PathValue("id")); err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeOK(w)
+}
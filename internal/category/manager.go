@@ -15,9 +15,10 @@ import (
 )
 
 type Manager struct {
-	ctx context.Context
-	db  *gorm.DB
-	dal *dal.CategoryDAL
+	ctx       context.Context
+	db        *gorm.DB
+	dal       *dal.CategoryDAL
+	recipeDAL *dal.RecipeDAL
 }
 
 func NewManager(ctx context.Context, db *gorm.DB) *Manager {
@@ -27,6 +28,7 @@ func NewManager(ctx context.Context, db *gorm.DB) *Manager {
 	}
 	if db != nil {
 		m.dal = dal.NewCategoryDAL(db)
+		m.recipeDAL = dal.NewRecipeDAL(db)
 	}
 	return m
 }
@@ -105,5 +107,8 @@ func (m *Manager) DeleteCategory(id string) error {
 	if cat.Source == schema.CategorySourceBuiltin {
 		return fmt.Errorf("category is builtin")
 	}
+	if err := m.recipeDAL.DeleteByCategory(m.ctx, id); err != nil {
+		return err
+	}
 	return m.dal.Delete(m.ctx, id)
 }
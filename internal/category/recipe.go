@@ -0,0 +1,118 @@
+package category
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"Kairo/internal/db/schema"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetRecipe returns the recipe of a category, or nil when the category uses
+// the default processing.
+func (m *Manager) GetRecipe(categoryID string) (*schema.Recipe, error) {
+	if m.recipeDAL == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	recipe, err := m.recipeDAL.GetByCategory(m.ctx, categoryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return recipe, nil
+}
+
+func (m *Manager) ListRecipes() ([]schema.Recipe, error) {
+	if m.recipeDAL == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return m.recipeDAL.List(m.ctx)
+}
+
+func (m *Manager) SaveRecipe(req schema.SaveRecipeRequest) (*schema.Recipe, error) {
+	if m.recipeDAL == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	if _, err := m.dal.GetByID(m.ctx, req.CategoryID); err != nil {
+		return nil, fmt.Errorf("category not found: %v", err)
+	}
+	for i := range req.Steps {
+		req.Steps[i].Language = strings.TrimSpace(req.Steps[i].Language)
+		req.Steps[i].Prompt = strings.TrimSpace(req.Steps[i].Prompt)
+		req.Steps[i].AccountID = strings.TrimSpace(req.Steps[i].AccountID)
+	}
+	if err := ValidateRecipeSteps(req.Steps); err != nil {
+		return nil, err
+	}
+	steps, err := json.Marshal(req.Steps)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe, err := m.GetRecipe(req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if recipe == nil {
+		recipe = &schema.Recipe{
+			ID:         uuid.New().String(),
+			CategoryID: req.CategoryID,
+		}
+	}
+	recipe.Steps = string(steps)
+	recipe.StepList = req.Steps
+	recipe.IsEnabled = req.IsEnabled
+	if err := m.recipeDAL.Save(m.ctx, recipe); err != nil {
+		return nil, err
+	}
+	return recipe, nil
+}
+
+func (m *Manager) DeleteRecipe(categoryID string) error {
+	if m.recipeDAL == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	return m.recipeDAL.DeleteByCategory(m.ctx, categoryID)
+}
+
+// ValidateRecipeSteps checks that every step is known, has the options it
+// needs and comes after the steps it depends on.
+func ValidateRecipeSteps(steps []schema.RecipeStep) error {
+	if len(steps) == 0 {
+		return fmt.Errorf("recipe has no steps")
+	}
+	seen := make(map[schema.RecipeStepType]bool)
+	for i, step := range steps {
+		switch step.Type {
+		case schema.RecipeStepSubtitles, schema.RecipeStepAnalyze:
+		case schema.RecipeStepTranslate:
+			if strings.TrimSpace(step.Language) == "" {
+				return fmt.Errorf("step %d: target language is empty", i+1)
+			}
+		case schema.RecipeStepClip:
+			if !seen[schema.RecipeStepAnalyze] {
+				return fmt.Errorf("step %d: clip must come after analyze", i+1)
+			}
+		case schema.RecipeStepPublish:
+			if strings.TrimSpace(step.AccountID) == "" {
+				return fmt.Errorf("step %d: publish account is empty", i+1)
+			}
+			if !seen[schema.RecipeStepClip] {
+				return fmt.Errorf("step %d: publish must come after clip", i+1)
+			}
+		default:
+			return fmt.Errorf("step %d: unknown step type %q", i+1, step.Type)
+		}
+		if step.MinScore < 0 || step.MinScore > 10 {
+			return fmt.Errorf("step %d: min score must be between 0 and 10", i+1)
+		}
+		seen[step.Type] = true
+	}
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+
+	"Kairo/internal/utils"
 )
 
 type CookieConfig struct {
@@ -17,11 +19,12 @@ type CookieConfig struct {
 
 type AIConfig struct {
 	Enabled   bool   `json:"enabled"`
-	Provider  string `json:"provider"` // "openai", "anthropic", "gemini", "deepseek", "siliconflow", "local", "custom"
+	Provider  string `json:"provider"` // "openai", "anthropic", "gemini", "deepseek", "siliconflow", "ollama", "local", "custom"
 	BaseURL   string `json:"baseUrl"`
 	APIKey    string `json:"apiKey"`
 	ModelName string `json:"modelName"`
 	Prompt    string `json:"prompt"`
+	ChunkSize int    `json:"chunkSize"` // Transcript characters per analysis window, 0 sizes it from the model
 }
 
 type DatabaseResolverConfig struct {
@@ -45,21 +48,78 @@ type DatabaseConfig struct {
 	Resolver     []DatabaseResolverConfig `json:"resolver"`
 }
 
+type APIConfig struct {
+	Enabled bool   `json:"enabled"`
+	Host    string `json:"host"`
+	Port    int    `json:"port"`
+	Token   string `json:"token"`
+}
+
+// RetryPolicy controls how failed downloads of one error kind are retried.
+// Delays are in seconds and double after every attempt up to MaxDelay.
+type RetryPolicy struct {
+	MaxAttempts    int  `json:"maxAttempts"`
+	BaseDelay      int  `json:"baseDelay"`
+	MaxDelay       int  `json:"maxDelay"`
+	CookieFallback bool `json:"cookieFallback"`
+	ProxyFallback  bool `json:"proxyFallback"`
+}
+
+// SiteLimit throttles downloads from one site, zero values mean no limit.
+type SiteLimit struct {
+	MaxConcurrent    int     `json:"maxConcurrent"`
+	MaxPerHour       int     `json:"maxPerHour"`
+	SleepRequests    float64 `json:"sleepRequests"`    // Seconds between requests during extraction
+	SleepInterval    int     `json:"sleepInterval"`    // Seconds to sleep before each download
+	MaxSleepInterval int     `json:"maxSleepInterval"` // Upper bound for a randomized sleep
+}
+
+type RetryConfig struct {
+	FallbackProxy string                 `json:"fallbackProxy"`
+	Policies      map[string]RetryPolicy `json:"policies"` // Overrides keyed by error kind
+}
+
+// DiskGuardConfig keeps downloads, clips and ASR temp files from filling the
+// disk. The download queue pauses while the reserve would be undercut.
+type DiskGuardConfig struct {
+	Enabled       bool    `json:"enabled"`
+	MinFreeMB     int64   `json:"minFreeMB"`     // Space always left free
+	SafetyFactor  float64 `json:"safetyFactor"`  // Multiplier of the expected size covering merging and trimming
+	CheckInterval int     `json:"checkInterval"` // Seconds between checks while the queue is paused
+}
+
+// RSSRetentionConfig bounds the stored feed items, feeds may override the
+// limits. The periodic cleanup is off unless enabled, downloaded and queued
+// items are never pruned.
+type RSSRetentionConfig struct {
+	Enabled       bool `json:"enabled"`
+	MaxItems      int  `json:"maxItems"`      // Newest items kept per feed, 0 keeps all
+	ReadDays      int  `json:"readDays"`      // Read items older than this are dropped, 0 keeps them
+	CheckInterval int  `json:"checkInterval"` // Hours between cleanups
+}
+
 type AppSettings struct {
-	DownloadDir         string         `json:"downloadDir"`
-	DownloadConcurrency int            `json:"downloadConcurrency"`
-	MaxDownloadSpeed    *int           `json:"maxDownloadSpeed"` // MB/s
-	Language            string         `json:"language"`
-	ProxyUrl            string         `json:"proxyUrl"`
-	UserAgent           string         `json:"userAgent"`
-	Referer             string         `json:"referer"`
-	GeoBypass           bool           `json:"geoBypass"`
-	Cookie              CookieConfig   `json:"cookie"`
-	AI                  AIConfig       `json:"ai"`
-	WhisperAI           AIConfig       `json:"whisperAi"`
-	TranslateAI         AIConfig       `json:"translateAi"`
-	RSSCheckInterval    int            `json:"rssCheckInterval"` // Minutes
-	Database            DatabaseConfig `json:"database"`
+	DownloadDir         string               `json:"downloadDir"`
+	DownloadConcurrency int                  `json:"downloadConcurrency"`
+	MaxDownloadSpeed    *int                 `json:"maxDownloadSpeed"` // MB/s
+	Language            string               `json:"language"`
+	ProxyUrl            string               `json:"proxyUrl"`
+	UserAgent           string               `json:"userAgent"`
+	Referer             string               `json:"referer"`
+	GeoBypass           bool                 `json:"geoBypass"`
+	Cookie              CookieConfig         `json:"cookie"`
+	AI                  AIConfig             `json:"ai"`
+	WhisperAI           AIConfig             `json:"whisperAi"`
+	TranslateAI         AIConfig             `json:"translateAi"`
+	RSSCheckInterval    int                  `json:"rssCheckInterval"` // Minutes
+	Database            DatabaseConfig       `json:"database"`
+	API                 APIConfig            `json:"api"`
+	JobWorkers          map[string]int       `json:"jobWorkers"` // Worker count per background job type
+	Retry               RetryConfig          `json:"retry"`
+	SiteLimits          map[string]SiteLimit `json:"siteLimits"` // Keyed by site name or host, hosts of known sites match their name
+	SiteProfiles        []SiteProfile        `json:"siteProfiles"`
+	DiskGuard           DiskGuardConfig      `json:"diskGuard"`
+	RSSRetention        RSSRetentionConfig   `json:"rssRetention"`
 }
 
 var (
@@ -80,6 +140,10 @@ func init() {
 			MaxIdleConns: 25,
 			AutoMigrate:  true,
 		},
+		API: APIConfig{
+			Host: "127.0.0.1",
+			Port: 17890,
+		},
 		AI: AIConfig{
 			Provider:  "openai",
 			BaseURL:   "https://api.openai.com/v1",
@@ -96,12 +160,55 @@ func init() {
 			ModelName: "gpt-3.5-turbo",
 		},
 		Language: "cn",
+		SiteLimits: map[string]SiteLimit{
+			"youtube":  {MaxConcurrent: 2, SleepRequests: 1, SleepInterval: 2, MaxSleepInterval: 5},
+			"bilibili": {MaxConcurrent: 2, SleepRequests: 0.5, SleepInterval: 1, MaxSleepInterval: 3},
+		},
+		DiskGuard: DiskGuardConfig{
+			Enabled:       true,
+			MinFreeMB:     1024,
+			SafetyFactor:  2,
+			CheckInterval: 30,
+		},
+		// Pruning deletes items, users opt in through config.json
+		RSSRetention: RSSRetentionConfig{
+			Enabled:       false,
+			MaxItems:      500,
+			ReadDays:      90,
+			CheckInterval: 24,
+		},
 	}
 }
 
 func UpdateSettings(cfg AppSettings) {
 	configMu.Lock()
 	defer configMu.Unlock()
+	// Sections only edited in config.json are missing from the frontend
+	// payload, keep the current values instead of wiping them.
+	if cfg.Database.Type == "" {
+		cfg.Database = currentConfig.Database
+	}
+	if cfg.API.Port == 0 {
+		cfg.API = currentConfig.API
+	}
+	if cfg.JobWorkers == nil {
+		cfg.JobWorkers = currentConfig.JobWorkers
+	}
+	if cfg.Retry.Policies == nil && cfg.Retry.FallbackProxy == "" {
+		cfg.Retry = currentConfig.Retry
+	}
+	if cfg.SiteLimits == nil {
+		cfg.SiteLimits = currentConfig.SiteLimits
+	}
+	if cfg.SiteProfiles == nil {
+		cfg.SiteProfiles = currentConfig.SiteProfiles
+	}
+	if cfg.DiskGuard == (DiskGuardConfig{}) {
+		cfg.DiskGuard = currentConfig.DiskGuard
+	}
+	if cfg.RSSRetention == (RSSRetentionConfig{}) {
+		cfg.RSSRetention = currentConfig.RSSRetention
+	}
 	currentConfig = cfg
 	// Save settings to disk
 	go SaveSettings()
@@ -199,6 +306,12 @@ func LoadSettings() error {
 	if currentConfig.Database.MaxIdleConns <= 0 {
 		currentConfig.Database.MaxIdleConns = 25
 	}
+	if currentConfig.API.Host == "" {
+		currentConfig.API.Host = "127.0.0.1"
+	}
+	if currentConfig.API.Port <= 0 {
+		currentConfig.API.Port = 17890
+	}
 
 	return nil
 }
@@ -268,6 +381,20 @@ func GetBinDir() (string, error) {
 	return base, nil
 }
 
+// GetModelDir returns the directory local ASR models are kept in.
+func GetModelDir() (string, error) {
+	cfg, err := os.UserConfigDir()
+	if err != nil || cfg == "" {
+		home, _ := os.UserHomeDir()
+		cfg = filepath.Join(home, ".config")
+	}
+	base := filepath.Join(cfg, "Kairo", "models")
+	if err := os.MkdirAll(base, 0o755); err != nil {
+		return "", err
+	}
+	return base, nil
+}
+
 func GetMaxConcurrentDownloads() int {
 	configMu.RLock()
 	defer configMu.RUnlock()
@@ -277,6 +404,33 @@ func GetMaxConcurrentDownloads() int {
 	return currentConfig.DownloadConcurrency
 }
 
+func GetSiteLimit(site string) SiteLimit {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	if limit, ok := currentConfig.SiteLimits[site]; ok {
+		return limit
+	}
+	site = utils.NormalizeSiteKey(site)
+	for key, limit := range currentConfig.SiteLimits {
+		if utils.NormalizeSiteKey(key) == site {
+			return limit
+		}
+	}
+	return SiteLimit{}
+}
+
+func GetDiskGuard() DiskGuardConfig {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return currentConfig.DiskGuard
+}
+
+func GetRSSRetention() RSSRetentionConfig {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return currentConfig.RSSRetention
+}
+
 func GetDownloadRateLimit() string {
 	configMu.RLock()
 	defer configMu.RUnlock()
@@ -310,19 +464,27 @@ func GetGeoBypass() bool {
 	return currentConfig.GeoBypass
 }
 
-func GetCookieArgs() []string {
+func GetAPIConfig() APIConfig {
 	configMu.RLock()
-	cookieConfig := currentConfig.Cookie
-	configMu.RUnlock()
+	defer configMu.RUnlock()
+	return currentConfig.API
+}
 
-	if cookieConfig.Enabled {
-		if cookieConfig.Source == "browser" && cookieConfig.Browser != "" {
-			return []string{"--cookies-from-browser", cookieConfig.Browser}
-		}
-		if cookieConfig.Source == "file" && cookieConfig.File != "" {
-			return []string{"--cookies", cookieConfig.File}
-		}
-	}
+func GetRetryPolicy(kind string) (RetryPolicy, bool) {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	policy, ok := currentConfig.Retry.Policies[kind]
+	return policy, ok
+}
 
-	return nil
+func GetFallbackProxy() string {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return currentConfig.Retry.FallbackProxy
+}
+
+func GetJobWorkers(jobType string) int {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return currentConfig.JobWorkers[jobType]
 }
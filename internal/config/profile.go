@@ -0,0 +1,280 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+)
+
+// SiteProfile overrides the network settings for URLs whose host matches one
+// of its patterns. A matched profile always replaces the global cookies, so
+// the cookies of one site are never sent to another.
+type SiteProfile struct {
+	Name      string       `json:"name"`
+	Enabled   bool         `json:"enabled"`
+	Hosts     []string     `json:"hosts"` // "bilibili.com" also matches its subdomains
+	Cookie    CookieConfig `json:"cookie"`
+	ProxyUrl  string       `json:"proxyUrl"`  // Empty inherits the global proxy
+	UserAgent string       `json:"userAgent"` // Empty inherits the global user agent
+	Referer   string       `json:"referer"`   // Empty inherits the global referer
+	GeoBypass *bool        `json:"geoBypass"` // Nil inherits the global setting
+	ExtraArgs []string     `json:"extraArgs"` // Passed to yt-dlp as is
+}
+
+// NetworkOptions are the resolved yt-dlp network options for one URL.
+type NetworkOptions struct {
+	Profile   string
+	Proxy     string
+	UserAgent string
+	Referer   string
+	GeoBypass bool
+	Cookie    CookieConfig
+	ExtraArgs []string
+}
+
+// allowedExtraArgs are the yt-dlp options a site profile may pass through,
+// mapped to whether they take a value. Anything else, such as the output
+// path, exec hooks or config files, is controlled by the app itself.
+var allowedExtraArgs = map[string]bool{
+	// Network and geo restriction
+	"--referer": true, "--add-headers": true, "--add-header": true, "--user-agent": true,
+	"--impersonate": true, "--source-address": true, "--socket-timeout": true,
+	"--force-ipv4": false, "-4": false, "--force-ipv6": false, "-6": false,
+	"--no-check-certificates": false, "--legacy-server-connect": false,
+	"--geo-bypass": false, "--no-geo-bypass": false, "--geo-bypass-country": true, "--xff": true,
+	// Pacing and retries
+	"--limit-rate": true, "-r": true, "--throttled-rate": true, "--http-chunk-size": true,
+	"--concurrent-fragments": true, "-N": true,
+	"--retries": true, "-R": true, "--fragment-retries": true, "--extractor-retries": true,
+	"--retry-sleep": true, "--sleep-requests": true, "--sleep-interval": true,
+	"--min-sleep-interval": true, "--max-sleep-interval": true, "--sleep-subtitles": true,
+	// Extraction
+	"--extractor-args": true, "--compat-options": true, "--age-limit": true,
+	"--username": true, "-u": true, "--password": true, "-p": true, "--twofactor": true, "-2": true,
+	"--video-password": true, "--ap-mso": true, "--ap-username": true, "--ap-password": true,
+	"--no-playlist": false, "--yes-playlist": false, "--live-from-start": false,
+	"--no-live-from-start": false, "--wait-for-video": true, "--match-filters": true,
+	"--hls-use-mpegts": false, "--no-hls-use-mpegts": false,
+	// Subtitles and embedding
+	"--write-subs": false, "--write-auto-subs": false, "--sub-langs": true, "--sub-format": true,
+	"--embed-subs": false, "--embed-thumbnail": false, "--embed-metadata": false,
+	"--embed-chapters": false, "--no-embed-subs": false, "--no-embed-thumbnail": false,
+	"--no-embed-metadata": false, "--no-embed-chapters": false,
+	// File handling inside the download directory
+	"--no-part": false, "--no-mtime": false, "--restrict-filenames": false,
+	"--windows-filenames": false, "--trim-filenames": true, "--no-cache-dir": false,
+	// Diagnostics
+	"--no-warnings": false, "--verbose": false, "-v": false, "--ignore-errors": false, "-i": false,
+}
+
+// ValidateExtraArgs checks passthrough yt-dlp arguments against
+// allowedExtraArgs. Short options must stand alone, so neither attached
+// values such as -o/path nor combined flags get past the list, and values
+// are only accepted after an option that takes one, so no extra URLs can be
+// smuggled in.
+func ValidateExtraArgs(args []string) error {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.TrimSpace(arg) == "" {
+			return fmt.Errorf("empty argument")
+		}
+		if !strings.HasPrefix(arg, "-") {
+			return fmt.Errorf("unexpected value %q, arguments must start with an option", arg)
+		}
+		name, _, hasValue := strings.Cut(arg, "=")
+		if !strings.HasPrefix(name, "--") {
+			if len(arg) != 2 {
+				return fmt.Errorf("option %s is not allowed, write short options on their own", arg)
+			}
+			hasValue = false
+		}
+		takesValue, ok := allowedExtraArgs[name]
+		if !ok {
+			return fmt.Errorf("option %s is not allowed", name)
+		}
+		switch {
+		case hasValue && !takesValue:
+			return fmt.Errorf("option %s takes no value", name)
+		case takesValue && !hasValue:
+			if i+1 >= len(args) {
+				return fmt.Errorf("option %s needs a value", name)
+			}
+			i++
+		}
+	}
+	return nil
+}
+
+// ValidateSiteProfiles checks names, host patterns and extra arguments.
+func ValidateSiteProfiles(profiles []SiteProfile) error {
+	names := make(map[string]bool)
+	for _, p := range profiles {
+		name := strings.TrimSpace(p.Name)
+		if name == "" {
+			return fmt.Errorf("profile name is empty")
+		}
+		if names[name] {
+			return fmt.Errorf("duplicate profile %q", name)
+		}
+		names[name] = true
+		if len(p.Hosts) == 0 {
+			return fmt.Errorf("profile %q has no hosts", name)
+		}
+		for _, host := range p.Hosts {
+			if normalizeHostPattern(host) == "" {
+				return fmt.Errorf("profile %q: invalid host %q", name, host)
+			}
+		}
+		if p.ProxyUrl != "" {
+			if _, err := url.Parse(p.ProxyUrl); err != nil {
+				return fmt.Errorf("profile %q: invalid proxy: %v", name, err)
+			}
+		}
+		if err := ValidateExtraArgs(p.ExtraArgs); err != nil {
+			return fmt.Errorf("profile %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func GetSiteProfiles() []SiteProfile {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return append([]SiteProfile(nil), currentConfig.SiteProfiles...)
+}
+
+func SaveSiteProfiles(profiles []SiteProfile) error {
+	for i := range profiles {
+		profiles[i].Name = strings.TrimSpace(profiles[i].Name)
+		hosts := make([]string, 0, len(profiles[i].Hosts))
+		for _, host := range profiles[i].Hosts {
+			if host = strings.TrimSpace(host); host != "" {
+				hosts = append(hosts, host)
+			}
+		}
+		profiles[i].Hosts = hosts
+	}
+	if err := ValidateSiteProfiles(profiles); err != nil {
+		return err
+	}
+	configMu.Lock()
+	currentConfig.SiteProfiles = profiles
+	configMu.Unlock()
+	return SaveSettings()
+}
+
+// ResolveNetworkOptions merges the global network settings with the first
+// enabled profile matching the host of rawURL.
+func ResolveNetworkOptions(rawURL string) NetworkOptions {
+	configMu.RLock()
+	opts := NetworkOptions{
+		Proxy:     currentConfig.ProxyUrl,
+		UserAgent: currentConfig.UserAgent,
+		Referer:   currentConfig.Referer,
+		GeoBypass: currentConfig.GeoBypass,
+		Cookie:    currentConfig.Cookie,
+	}
+	profile := matchSiteProfile(currentConfig.SiteProfiles, rawURL)
+	configMu.RUnlock()
+
+	if profile == nil {
+		return opts
+	}
+	opts.Profile = profile.Name
+	opts.Cookie = profile.Cookie
+	if profile.ProxyUrl != "" {
+		opts.Proxy = profile.ProxyUrl
+	}
+	if profile.UserAgent != "" {
+		opts.UserAgent = profile.UserAgent
+	}
+	if profile.Referer != "" {
+		opts.Referer = profile.Referer
+	}
+	if profile.GeoBypass != nil {
+		opts.GeoBypass = *profile.GeoBypass
+	}
+	if err := ValidateExtraArgs(profile.ExtraArgs); err != nil {
+		log.Printf("[SiteProfile] ignoring extra args of %s: %v", profile.Name, err)
+	} else {
+		opts.ExtraArgs = profile.ExtraArgs
+	}
+	return opts
+}
+
+// CookieArgs returns the cookie options, force uses the configured cookie
+// source even when cookies are disabled.
+func (o NetworkOptions) CookieArgs(force bool) []string {
+	if !o.Cookie.Enabled && !force {
+		return nil
+	}
+	if o.Cookie.Source == "browser" && o.Cookie.Browser != "" {
+		return []string{"--cookies-from-browser", o.Cookie.Browser}
+	}
+	if o.Cookie.Source == "file" && o.Cookie.File != "" {
+		return []string{"--cookies", o.Cookie.File}
+	}
+	return nil
+}
+
+// Args returns the yt-dlp options for proxy, headers, geo bypass, cookies and
+// the extra arguments of the matched profile.
+func (o NetworkOptions) Args() []string {
+	var args []string
+	if o.Proxy != "" {
+		args = append(args, "--proxy", o.Proxy)
+	}
+	if o.UserAgent != "" {
+		args = append(args, "--user-agent", o.UserAgent)
+	}
+	if o.Referer != "" {
+		args = append(args, "--referer", o.Referer)
+	}
+	if o.GeoBypass {
+		args = append(args, "--geo-bypass")
+	} else {
+		args = append(args, "--no-geo-bypass")
+	}
+	args = append(args, o.CookieArgs(false)...)
+	return append(args, o.ExtraArgs...)
+}
+
+func matchSiteProfile(profiles []SiteProfile, rawURL string) *SiteProfile {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return nil
+	}
+	for i := range profiles {
+		if !profiles[i].Enabled {
+			continue
+		}
+		for _, pattern := range profiles[i].Hosts {
+			pattern = normalizeHostPattern(pattern)
+			if pattern != "" && (host == pattern || strings.HasSuffix(host, "."+pattern)) {
+				return &profiles[i]
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeHostPattern accepts "example.com", "*.example.com" or a full URL.
+func normalizeHostPattern(pattern string) string {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if strings.Contains(pattern, "://") {
+		if u, err := url.Parse(pattern); err == nil {
+			pattern = u.Hostname()
+		}
+	}
+	pattern = strings.TrimPrefix(pattern, "*.")
+	pattern = strings.TrimPrefix(pattern, "www.")
+	if pattern == "" || strings.ContainsAny(pattern, "/ *") {
+		return ""
+	}
+	return pattern
+}
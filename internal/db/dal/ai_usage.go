@@ -0,0 +1,41 @@
+package dal
+
+import (
+	"context"
+	"fmt"
+
+	"Kairo/internal/db/schema"
+
+	"gorm.io/gorm"
+)
+
+type AIUsageDAL struct {
+	db *gorm.DB
+}
+
+func NewAIUsageDAL(db *gorm.DB) *AIUsageDAL {
+	return &AIUsageDAL{db: db}
+}
+
+func (d *AIUsageDAL) Create(ctx context.Context, usage *schema.AIUsage) error {
+	return d.db.WithContext(ctx).Create(usage).Error
+}
+
+// SumBy totals the calls made since the unix time, grouped by the day or
+// purpose column.
+func (d *AIUsageDAL) SumBy(ctx context.Context, column string, since int64) ([]schema.AIUsageTotal, error) {
+	if column != "day" && column != "purpose" {
+		return nil, fmt.Errorf("unknown usage grouping %q", column)
+	}
+	totals := []schema.AIUsageTotal{}
+	err := d.db.WithContext(ctx).Model(&schema.AIUsage{}).
+		Select(column+" AS group_key, COUNT(*) AS calls, "+
+			"SUM(CASE WHEN error <> '' THEN 1 ELSE 0 END) AS failures, "+
+			"SUM(prompt_tokens) AS prompt_tokens, SUM(completion_tokens) AS completion_tokens, "+
+			"AVG(latency_ms) AS avg_latency_ms").
+		Where("created_at >= ?", since).
+		Group(column).
+		Order(column).
+		Scan(&totals).Error
+	return totals, err
+}
@@ -0,0 +1,149 @@
+package dal
+
+import (
+	"context"
+	"time"
+
+	"Kairo/internal/db/schema"
+
+	"gorm.io/gorm"
+)
+
+type JobDAL struct {
+	db *gorm.DB
+}
+
+func NewJobDAL(db *gorm.DB) *JobDAL {
+	return &JobDAL{db: db}
+}
+
+func (d *JobDAL) Create(ctx context.Context, job *schema.Job) error {
+	return d.db.WithContext(ctx).Create(job).Error
+}
+
+func (d *JobDAL) Save(ctx context.Context, job *schema.Job) error {
+	return d.db.WithContext(ctx).Save(job).Error
+}
+
+func (d *JobDAL) GetByID(ctx context.Context, id string) (*schema.Job, error) {
+	var job schema.Job
+	err := d.db.WithContext(ctx).First(&job, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FindActive returns the pending or running job for a type and reference, if any.
+func (d *JobDAL) FindActive(ctx context.Context, jobType, refID string) (*schema.Job, error) {
+	var job schema.Job
+	err := d.db.WithContext(ctx).
+		Where("type = ? AND ref_id = ? AND status IN ?", jobType, refID,
+			[]schema.JobStatus{schema.JobStatusPending, schema.JobStatusRunning}).
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (d *JobDAL) List(ctx context.Context, filter schema.JobFilter) ([]schema.Job, int64, error) {
+	db := d.db.WithContext(ctx).Model(&schema.Job{})
+	if filter.Type != "" && filter.Type != "all" {
+		db = db.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" && filter.Status != "all" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	if filter.RefID != "" {
+		db = db.Where("ref_id = ?", filter.RefID)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var jobs []schema.Job
+	if filter.PageSize > 0 {
+		page := filter.Page
+		if page < 1 {
+			page = 1
+		}
+		db = db.Offset((page - 1) * filter.PageSize).Limit(filter.PageSize)
+	}
+	err := db.Order("created_at desc").Find(&jobs).Error
+	return jobs, total, err
+}
+
+// Claim atomically moves the oldest due pending job of the given type to
+// running and returns it, or nil when nothing is due.
+func (d *JobDAL) Claim(ctx context.Context, jobType string) (*schema.Job, error) {
+	nowMs := time.Now().UnixMilli()
+	for {
+		// Find instead of First so an idle poll does not log "record not found"
+		var due []schema.Job
+		err := d.db.WithContext(ctx).
+			Where("type = ? AND status = ? AND run_at <= ?", jobType, schema.JobStatusPending, nowMs).
+			Order("run_at asc, created_at asc").
+			Limit(1).
+			Find(&due).Error
+		if err != nil {
+			return nil, err
+		}
+		if len(due) == 0 {
+			return nil, nil
+		}
+		job := due[0]
+
+		res := d.db.WithContext(ctx).Model(&schema.Job{}).
+			Where("id = ? AND status = ?", job.ID, schema.JobStatusPending).
+			Updates(map[string]interface{}{
+				"status":     schema.JobStatusRunning,
+				"started_at": nowMs,
+				"updated_at": nowMs,
+			})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if res.RowsAffected == 1 {
+			job.Status = schema.JobStatusRunning
+			job.StartedAt = nowMs
+			job.UpdatedAt = nowMs
+			return &job, nil
+		}
+		// Another worker claimed it first, try the next one
+	}
+}
+
+// CancelPending cancels the job if it is still pending and reports whether
+// it did, a worker may have claimed it in the meantime.
+func (d *JobDAL) CancelPending(ctx context.Context, id string) (bool, error) {
+	nowMs := time.Now().UnixMilli()
+	res := d.db.WithContext(ctx).Model(&schema.Job{}).
+		Where("id = ? AND status = ?", id, schema.JobStatusPending).
+		Updates(map[string]interface{}{
+			"status":      schema.JobStatusCancelled,
+			"error":       "cancelled",
+			"finished_at": nowMs,
+			"updated_at":  nowMs,
+		})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (d *JobDAL) UpdateProgress(ctx context.Context, id string, progress float64) error {
+	return d.db.WithContext(ctx).Model(&schema.Job{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"progress":   progress,
+		"updated_at": time.Now().UnixMilli(),
+	}).Error
+}
+
+// ResetRunning returns jobs interrupted by a shutdown to the pending state.
+func (d *JobDAL) ResetRunning(ctx context.Context) error {
+	return d.db.WithContext(ctx).Model(&schema.Job{}).
+		Where("status = ?", schema.JobStatusRunning).
+		Updates(map[string]interface{}{
+			"status":     schema.JobStatusPending,
+			"updated_at": time.Now().UnixMilli(),
+		}).Error
+}
@@ -0,0 +1,80 @@
+package dal
+
+import (
+	"context"
+
+	"Kairo/internal/db/schema"
+
+	"gorm.io/gorm"
+)
+
+type RecipeDAL struct {
+	db *gorm.DB
+}
+
+func NewRecipeDAL(db *gorm.DB) *RecipeDAL {
+	return &RecipeDAL{db: db}
+}
+
+func (d *RecipeDAL) List(ctx context.Context) ([]schema.Recipe, error) {
+	var recipes []schema.Recipe
+	err := d.db.WithContext(ctx).Order("created_at asc").Find(&recipes).Error
+	return recipes, err
+}
+
+func (d *RecipeDAL) GetByCategory(ctx context.Context, categoryID string) (*schema.Recipe, error) {
+	var recipe schema.Recipe
+	err := d.db.WithContext(ctx).First(&recipe, "category_id = ?", categoryID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &recipe, nil
+}
+
+func (d *RecipeDAL) Save(ctx context.Context, recipe *schema.Recipe) error {
+	return d.db.WithContext(ctx).Save(recipe).Error
+}
+
+func (d *RecipeDAL) DeleteByCategory(ctx context.Context, categoryID string) error {
+	return d.db.WithContext(ctx).Delete(&schema.Recipe{}, "category_id = ?", categoryID).Error
+}
+
+type VideoPipelineDAL struct {
+	db *gorm.DB
+}
+
+func NewVideoPipelineDAL(db *gorm.DB) *VideoPipelineDAL {
+	return &VideoPipelineDAL{db: db}
+}
+
+func (d *VideoPipelineDAL) ListByVideo(ctx context.Context, videoID string) ([]schema.VideoPipelineStep, error) {
+	var steps []schema.VideoPipelineStep
+	err := d.db.WithContext(ctx).Where("video_id = ?", videoID).Order("position asc").Find(&steps).Error
+	return steps, err
+}
+
+func (d *VideoPipelineDAL) GetByID(ctx context.Context, id string) (*schema.VideoPipelineStep, error) {
+	var step schema.VideoPipelineStep
+	err := d.db.WithContext(ctx).First(&step, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &step, nil
+}
+
+func (d *VideoPipelineDAL) ExistsForVideo(ctx context.Context, videoID string) (bool, error) {
+	var count int64
+	err := d.db.WithContext(ctx).Model(&schema.VideoPipelineStep{}).Where("video_id = ?", videoID).Count(&count).Error
+	return count > 0, err
+}
+
+func (d *VideoPipelineDAL) CreateBatch(ctx context.Context, steps []schema.VideoPipelineStep) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	return d.db.WithContext(ctx).Create(&steps).Error
+}
+
+func (d *VideoPipelineDAL) Save(ctx context.Context, step *schema.VideoPipelineStep) error {
+	return d.db.WithContext(ctx).Save(step).Error
+}
@@ -43,6 +43,21 @@ func (d *RSSDAL) GetFeedByID(ctx context.Context, id string) (*schema.Feed, erro
 	return &feed, nil
 }
 
+// ListDueFeeds returns the enabled feeds whose next refresh is due at now.
+func (d *RSSDAL) ListDueFeeds(ctx context.Context, now int64) ([]schema.Feed, error) {
+	var feeds []schema.Feed
+	err := d.db.WithContext(ctx).Where("enabled = ? AND next_refresh_at <= ?", true, now).Order("next_refresh_at asc").Find(&feeds).Error
+	return feeds, err
+}
+
+// ListUnhealthyFeeds returns the feeds whose last refresh failed, most
+// failures first.
+func (d *RSSDAL) ListUnhealthyFeeds(ctx context.Context) ([]schema.Feed, error) {
+	var feeds []schema.Feed
+	err := d.db.WithContext(ctx).Where("failure_count > 0").Order("failure_count desc").Find(&feeds).Error
+	return feeds, err
+}
+
 func (d *RSSDAL) GetFeedURLByID(ctx context.Context, id string) (string, error) {
 	var feed schema.Feed
 	err := d.db.WithContext(ctx).Select("url").First(&feed, "id = ?", id).Error
@@ -61,6 +76,9 @@ func (d *RSSDAL) DeleteFeed(ctx context.Context, id string) error {
 		if err := tx.Delete(&schema.FeedItem{}, "feed_id = ?", id).Error; err != nil {
 			return err
 		}
+		if err := tx.Delete(&schema.FeedItemTombstone{}, "feed_id = ?", id).Error; err != nil {
+			return err
+		}
 		return tx.Delete(&schema.Feed{}, "id = ?", id).Error
 	})
 }
@@ -81,6 +99,10 @@ func (d *RSSDAL) UpdateFeedItemStatusByID(ctx context.Context, id string, status
 	return d.db.WithContext(ctx).Model(&schema.FeedItem{}).Where("id = ?", id).Update("status", status).Error
 }
 
+func (d *RSSDAL) UpdateFeedItem(ctx context.Context, id string, updates map[string]interface{}) error {
+	return d.db.WithContext(ctx).Model(&schema.FeedItem{}).Where("id = ?", id).Updates(updates).Error
+}
+
 func (d *RSSDAL) UpdateFeedItemStatusByIDIf(ctx context.Context, id string, fromStatus int, toStatus int) error {
 	return d.db.WithContext(ctx).Model(&schema.FeedItem{}).Where("id = ? AND status = ?", id, fromStatus).Update("status", toStatus).Error
 }
@@ -112,3 +134,62 @@ func (d *RSSDAL) CountUnread(ctx context.Context, feedID string, status int) (in
 	err := d.db.WithContext(ctx).Model(&schema.FeedItem{}).Where("feed_id = ? AND status = ?", feedID, status).Count(&count).Error
 	return count, err
 }
+
+// PruneFeedItems deletes the given items of a feed and leaves a tombstone
+// for each of their links.
+func (d *RSSDAL) PruneFeedItems(ctx context.Context, feedID string, items []schema.FeedItem, now int64) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(items))
+	tombstones := make([]schema.FeedItemTombstone, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+		tombstones = append(tombstones, schema.FeedItemTombstone{
+			FeedID:   feedID,
+			Link:     item.Link,
+			PrunedAt: now,
+			SeenAt:   now,
+		})
+	}
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&schema.FeedItem{}, "feed_id = ? AND id IN ?", feedID, ids).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{"pruned_at", "seen_at"}),
+		}).CreateInBatches(&tombstones, 200).Error
+	})
+}
+
+// ListTombstonedLinks returns which of the given links were pruned from the
+// feed before.
+func (d *RSSDAL) ListTombstonedLinks(ctx context.Context, feedID string, links []string) ([]string, error) {
+	var found []string
+	if len(links) == 0 {
+		return found, nil
+	}
+	err := d.db.WithContext(ctx).Model(&schema.FeedItemTombstone{}).Where("feed_id = ? AND link IN ?", feedID, links).Pluck("link", &found).Error
+	return found, err
+}
+
+// TouchTombstones marks the tombstones of the given links as still listed,
+// nil links touch every tombstone of the feed.
+func (d *RSSDAL) TouchTombstones(ctx context.Context, feedID string, links []string, now int64) error {
+	query := d.db.WithContext(ctx).Model(&schema.FeedItemTombstone{}).Where("feed_id = ?", feedID)
+	if links != nil {
+		if len(links) == 0 {
+			return nil
+		}
+		query = query.Where("link IN ?", links)
+	}
+	return query.Update("seen_at", now).Error
+}
+
+// DeleteStaleTombstones drops the tombstones no fetch has listed since
+// before. Disabled feeds are not fetched, their tombstones are kept.
+func (d *RSSDAL) DeleteStaleTombstones(ctx context.Context, before int64) (int64, error) {
+	enabled := d.db.Model(&schema.Feed{}).Select("id").Where("enabled = ?", true)
+	result := d.db.WithContext(ctx).Where("seen_at < ? AND feed_id IN (?)", before, enabled).Delete(&schema.FeedItemTombstone{})
+	return result.RowsAffected, result.Error
+}
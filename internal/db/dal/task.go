@@ -2,12 +2,24 @@ package dal
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	"Kairo/internal/db/schema"
 
 	"gorm.io/gorm"
 )
 
+// queueOrder is the order in which pending tasks are started.
+const queueOrder = "priority desc, queue_order asc, created_at asc"
+
+// downloadTasks leaves out the parents of playlists and RSS feeds, their
+// status is derived from their children and they are never downloaded.
+func downloadTasks(db *gorm.DB) *gorm.DB {
+	return db.Where("NOT (parent_id = ? AND source_type IN ?)", "",
+		[]int{int(schema.SourceTypePlaylist), int(schema.SourceTypeRSS)})
+}
+
 type TaskDAL struct {
 	db *gorm.DB
 }
@@ -49,14 +61,87 @@ func (d *TaskDAL) FindBySourceAndURL(ctx context.Context, sourceType int, url st
 	return &task, nil
 }
 
-func (d *TaskDAL) ListPending(ctx context.Context, limit int) ([]schema.Task, error) {
+// ListPending returns pending tasks that are not waiting for a retry at now.
+func (d *TaskDAL) ListPending(ctx context.Context, now int64, offset, limit int) ([]schema.Task, error) {
+	var tasks []schema.Task
+	err := d.db.WithContext(ctx).Scopes(downloadTasks).Where("status = ? AND next_retry_at <= ?", "pending", now).Order(queueOrder).Offset(offset).Limit(limit).Find(&tasks).Error
+	return tasks, err
+}
+
+// ListStartedSince returns the URL and start time of the downloads started
+// at or after since.
+func (d *TaskDAL) ListStartedSince(ctx context.Context, since int64) ([]schema.Task, error) {
 	var tasks []schema.Task
-	err := d.db.WithContext(ctx).Where("status = ?", "pending").Order("created_at asc").Limit(limit).Find(&tasks).Error
+	err := d.db.WithContext(ctx).Scopes(downloadTasks).Select("id", "url", "started_at").
+		Where("started_at >= ?", since).Order("started_at asc").Find(&tasks).Error
 	return tasks, err
 }
 
+// ListQueue returns every pending task in the order they will be started.
+func (d *TaskDAL) ListQueue(ctx context.Context) ([]schema.Task, error) {
+	var tasks []schema.Task
+	err := d.db.WithContext(ctx).Scopes(downloadTasks).Where("status = ?", "pending").Order(queueOrder).Find(&tasks).Error
+	return tasks, err
+}
+
+// SaveQueue stores the priority and queue order of the tasks.
+func (d *TaskDAL) SaveQueue(ctx context.Context, tasks []schema.Task) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, t := range tasks {
+			err := tx.Model(&schema.Task{}).Where("id = ?", t.ID).
+				Updates(map[string]interface{}{"priority": t.Priority, "queue_order": t.QueueOrder}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// NextRetryAt returns the earliest retry time after now, or 0 if no pending
+// task is waiting for a retry.
+func (d *TaskDAL) NextRetryAt(ctx context.Context, now int64) (int64, error) {
+	var next *int64
+	err := d.db.WithContext(ctx).Model(&schema.Task{}).Scopes(downloadTasks).
+		Where("status = ? AND next_retry_at > ?", "pending", now).
+		Select("MIN(next_retry_at)").Scan(&next).Error
+	if err != nil || next == nil {
+		return 0, err
+	}
+	return *next, nil
+}
+
+// Save writes every column except removed_entries, which only
+// AddRemovedEntries changes so a stale copy of the parent cannot drop entries.
 func (d *TaskDAL) Save(ctx context.Context, task *schema.Task) error {
-	return d.db.WithContext(ctx).Save(task).Error
+	return d.db.WithContext(ctx).Omit("removed_entries").Save(task).Error
+}
+
+// SaveProgress writes only the download progress columns, so a runner
+// reporting progress cannot overwrite a status set by a pause or cancel.
+func (d *TaskDAL) SaveProgress(ctx context.Context, task *schema.Task) error {
+	return d.db.WithContext(ctx).Model(&schema.Task{}).Where("id = ?", task.ID).
+		Select("progress", "downloaded_bytes", "total_bytes", "speed_bps", "eta_seconds",
+			"fragment_index", "fragment_count", "file_path", "updated_at").
+		Updates(task).Error
+}
+
+// AddRemovedEntries appends keys to the deleted entries of a playlist parent.
+func (d *TaskDAL) AddRemovedEntries(ctx context.Context, id string, keys []string) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var task schema.Task
+		if err := tx.Select("id", "removed_entries").First(&task, "id = ?", id).Error; err != nil {
+			return err
+		}
+		entries := strings.Fields(task.RemovedEntries)
+		for _, key := range keys {
+			if !slices.Contains(entries, key) {
+				entries = append(entries, key)
+			}
+		}
+		return tx.Model(&schema.Task{}).Where("id = ?", id).
+			Update("removed_entries", strings.Join(entries, "\n")).Error
+	})
 }
 
 func (d *TaskDAL) DeleteByID(ctx context.Context, id string) error {
@@ -64,11 +149,20 @@ func (d *TaskDAL) DeleteByID(ctx context.Context, id string) error {
 }
 
 func (d *TaskDAL) ResetInterrupted(ctx context.Context, pausedStatus string, statuses []string) error {
-	return d.db.WithContext(ctx).Model(&schema.Task{}).Where("status IN ?", statuses).Update("status", pausedStatus).Error
+	return d.db.WithContext(ctx).Model(&schema.Task{}).Scopes(downloadTasks).Where("status IN ?", statuses).Update("status", pausedStatus).Error
 }
 
-func (d *TaskDAL) CountByStatus(ctx context.Context, statuses []string) (int64, error) {
-	var count int64
-	err := d.db.WithContext(ctx).Model(&schema.Task{}).Where("status IN ?", statuses).Count(&count).Error
-	return count, err
+func (d *TaskDAL) ListByStatus(ctx context.Context, statuses []string) ([]schema.Task, error) {
+	var tasks []schema.Task
+	err := d.db.WithContext(ctx).Scopes(downloadTasks).Where("status IN ?", statuses).Find(&tasks).Error
+	return tasks, err
+}
+
+// ListDueSyncs returns the playlist parents whose sync interval has elapsed at now.
+func (d *TaskDAL) ListDueSyncs(ctx context.Context, now int64) ([]schema.Task, error) {
+	var tasks []schema.Task
+	err := d.db.WithContext(ctx).
+		Where("parent_id = ? AND sync_interval > 0 AND last_synced_at + sync_interval * 60 <= ?", "", now).
+		Find(&tasks).Error
+	return tasks, err
 }
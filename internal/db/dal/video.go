@@ -35,7 +35,7 @@ func (d *VideoDAL) ExistsByTaskOrPath(ctx context.Context, taskID, filePath stri
 	return count > 0, err
 }
 
-func (d *VideoDAL) List(ctx context.Context, statusFilter, query string) ([]schema.Video, error) {
+func (d *VideoDAL) List(ctx context.Context, statusFilter, query, feedID string) ([]schema.Video, error) {
 	db := d.db.WithContext(ctx).Model(&schema.Video{})
 	if statusFilter != "" && statusFilter != "all" {
 		if statusFilter == "analyzed" {
@@ -47,6 +47,9 @@ func (d *VideoDAL) List(ctx context.Context, statusFilter, query string) ([]sche
 	if query != "" {
 		db = db.Where("title LIKE ?", "%"+query+"%")
 	}
+	if feedID != "" {
+		db = db.Where("feed_id = ?", feedID)
+	}
 	var videos []schema.Video
 	err := db.Order("created_at desc").Find(&videos).Error
 	return videos, err
@@ -100,6 +103,9 @@ func (d *VideoDAL) DeleteVideoCascade(ctx context.Context, id string) error {
 		if err := tx.Delete(&schema.VideoSubtitle{}, "video_id = ?", id).Error; err != nil {
 			return err
 		}
+		if err := tx.Delete(&schema.VideoPipelineStep{}, "video_id = ?", id).Error; err != nil {
+			return err
+		}
 		return tx.Delete(&schema.Video{}, "id = ?", id).Error
 	})
 }
@@ -96,12 +96,17 @@ func migrateSchema(db *gorm.DB) error {
 		new(schema.VideoHighlight),
 		new(schema.Feed),
 		new(schema.FeedItem),
+		new(schema.FeedItemTombstone),
 		new(schema.Category),
 		new(schema.PublishPlatform),
 		new(schema.PublishTask),
 		new(schema.PublishAccount),
 		new(schema.PublishAutomation),
 		new(schema.PublishRecord),
+		new(schema.Job),
+		new(schema.Recipe),
+		new(schema.VideoPipelineStep),
+		new(schema.AIUsage),
 	)
 }
 
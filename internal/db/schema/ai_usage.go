@@ -0,0 +1,42 @@
+package schema
+
+// AIUsagePurpose tells which feature made an AI call.
+type AIUsagePurpose string
+
+const (
+	AIPurposeAnalysis      AIUsagePurpose = "analysis"
+	AIPurposeTranslation   AIUsagePurpose = "translation"
+	AIPurposeTranscription AIUsagePurpose = "transcription"
+)
+
+// AIUsage records one call to an AI provider, retries included.
+type AIUsage struct {
+	ID               string         `gorm:"primaryKey;size:36" json:"id"`
+	Provider         string         `gorm:"index" json:"provider"`
+	Model            string         `json:"model"`
+	Purpose          AIUsagePurpose `gorm:"index" json:"purpose"`
+	VideoID          string         `gorm:"index" json:"video_id"`
+	PromptTokens     int            `json:"prompt_tokens"`
+	CompletionTokens int            `json:"completion_tokens"`
+	LatencyMs        int64          `json:"latency_ms"`
+	Attempts         int            `json:"attempts"`
+	Error            string         `gorm:"type:text" json:"error"` // Empty when the call succeeded
+	Day              string         `gorm:"index" json:"day"`       // Local date as YYYY-MM-DD
+	CreatedAt        int64          `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// AIUsageTotal sums the calls of one day or one purpose.
+type AIUsageTotal struct {
+	Key              string  `gorm:"column:group_key" json:"key"`
+	Calls            int64   `json:"calls"`
+	Failures         int64   `json:"failures"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	AvgLatencyMs     float64 `json:"avg_latency_ms"`
+}
+
+type AIUsageReport struct {
+	Since     int64          `json:"since"`
+	ByDay     []AIUsageTotal `json:"by_day"`
+	ByPurpose []AIUsageTotal `json:"by_purpose"`
+}
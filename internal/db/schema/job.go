@@ -0,0 +1,41 @@
+package schema
+
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCancelled JobStatus = "cancelled"
+)
+
+type Job struct {
+	ID          string    `gorm:"primaryKey;size:36" json:"id"`
+	Type        string    `gorm:"index" json:"type"`
+	RefID       string    `gorm:"index" json:"ref_id"`
+	Payload     string    `gorm:"type:text" json:"payload"`
+	Status      JobStatus `gorm:"index" json:"status"`
+	Attempts    int       `json:"attempts"`
+	MaxAttempts int       `json:"max_attempts"`
+	Progress    float64   `json:"progress"`
+	Error       string    `gorm:"type:text" json:"error"`
+	RunAt       int64     `gorm:"index" json:"run_at"`
+	StartedAt   int64     `json:"started_at"`
+	FinishedAt  int64     `json:"finished_at"`
+	CreatedAt   int64     `gorm:"autoCreateTime:milli" json:"created_at"`
+	UpdatedAt   int64     `gorm:"autoUpdateTime:milli" json:"updated_at"`
+}
+
+type JobFilter struct {
+	Type     string `json:"type"`
+	Status   string `json:"status"`
+	RefID    string `json:"ref_id"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
+
+type JobListResponse struct {
+	Total int64 `json:"total"`
+	Data  []Job `json:"data"`
+}
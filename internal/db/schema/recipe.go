@@ -0,0 +1,81 @@
+package schema
+
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+type RecipeStepType string
+
+const (
+	RecipeStepSubtitles RecipeStepType = "subtitles"
+	RecipeStepTranslate RecipeStepType = "translate"
+	RecipeStepAnalyze   RecipeStepType = "analyze"
+	RecipeStepClip      RecipeStepType = "clip"
+	RecipeStepPublish   RecipeStepType = "publish"
+)
+
+// RecipeStep is one step of a processing recipe. Only the options relevant
+// to the step type are used.
+type RecipeStep struct {
+	Type      RecipeStepType `json:"type"`
+	SkipASR   bool           `json:"skip_asr,omitempty"`   // subtitles: never fall back to speech recognition
+	Language  string         `json:"language,omitempty"`   // translate: target language
+	Prompt    string         `json:"prompt,omitempty"`     // analyze: overrides the category prompt
+	MinScore  float64        `json:"min_score,omitempty"`  // clip, publish: only highlights scored at least this
+	AccountID string         `json:"account_id,omitempty"` // publish: target account
+}
+
+// Recipe declares which steps run, in order, for videos of a category once
+// their download completes.
+type Recipe struct {
+	ID         string `gorm:"primaryKey;size:36" json:"id"`
+	CategoryID string `gorm:"uniqueIndex" json:"category_id"`
+	Steps      string `gorm:"type:text" json:"-"`
+	IsEnabled  bool   `json:"is_enabled"`
+	CreatedAt  int64  `gorm:"autoCreateTime:milli" json:"created_at"`
+	UpdatedAt  int64  `gorm:"autoUpdateTime:milli" json:"updated_at"`
+
+	StepList []RecipeStep `gorm:"-" json:"steps"`
+}
+
+// AfterFind hook to parse Steps JSON to StepList
+func (r *Recipe) AfterFind(tx *gorm.DB) (err error) {
+	r.StepList = []RecipeStep{}
+	if r.Steps != "" {
+		_ = json.Unmarshal([]byte(r.Steps), &r.StepList)
+	}
+	return
+}
+
+type SaveRecipeRequest struct {
+	CategoryID string       `json:"category_id"`
+	Steps      []RecipeStep `json:"steps"`
+	IsEnabled  bool         `json:"is_enabled"`
+}
+
+type PipelineStepStatus string
+
+const (
+	PipelineStepPending   PipelineStepStatus = "pending"
+	PipelineStepRunning   PipelineStepStatus = "running"
+	PipelineStepCompleted PipelineStepStatus = "completed"
+	PipelineStepFailed    PipelineStepStatus = "failed"
+	PipelineStepSkipped   PipelineStepStatus = "skipped"
+)
+
+// VideoPipelineStep records the progress of one recipe step for a video.
+type VideoPipelineStep struct {
+	ID         string             `gorm:"primaryKey;size:36" json:"id"`
+	VideoID    string             `gorm:"index" json:"video_id"`
+	Position   int                `json:"position"`
+	Type       RecipeStepType     `json:"type"`
+	Options    string             `gorm:"type:text" json:"options"`
+	Status     PipelineStepStatus `gorm:"index" json:"status"`
+	Error      string             `gorm:"type:text" json:"error"`
+	StartedAt  int64              `json:"started_at"`
+	FinishedAt int64              `json:"finished_at"`
+	CreatedAt  int64              `gorm:"autoCreateTime:milli" json:"created_at"`
+	UpdatedAt  int64              `gorm:"autoUpdateTime:milli" json:"updated_at"`
+}
@@ -10,55 +10,142 @@ const (
 	RSSItemStatusDownloaded RSSItemStatus = 4
 )
 
+// FeedSourceType tells how the entries of a subscription are fetched.
+type FeedSourceType int
+
+const (
+	FeedSourceRSS     FeedSourceType = 0 // RSS or Atom document
+	FeedSourceChannel FeedSourceType = 1 // Channel or uploader page listed by yt-dlp
+)
+
 type Feed struct {
-	ID               string `gorm:"primaryKey;size:36" json:"id"`
-	URL              string `gorm:"index" json:"url"`
-	Title            string `json:"title"`
-	Description      string `json:"description"`
-	Thumbnail        string `json:"thumbnail"`
-	LastUpdated      int64  `json:"last_updated"`
-	UnreadCount      int    `json:"unread_count"`
-	CustomDir        string `json:"custom_dir"`
-	DownloadLatest   bool   `json:"download_latest"`
-	Filters          string `json:"filters"`
-	Tags             string `json:"tags"`
-	FilenameTemplate string `json:"filename_template"`
-	CategoryID       string `gorm:"index" json:"category_id"`
-	Enabled          bool   `gorm:"index" json:"enabled"`
-	CreatedAt        int64  `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt        int64  `gorm:"autoUpdateTime" json:"updated_at"`
+	ID               string         `gorm:"primaryKey;size:36" json:"id"`
+	URL              string         `gorm:"index" json:"url"`
+	SourceType       FeedSourceType `gorm:"default:0" json:"source_type"`
+	Podcast          bool           `json:"podcast"` // Download item enclosures directly instead of the item link
+	Title            string         `json:"title"`
+	Description      string         `json:"description"`
+	Thumbnail        string         `json:"thumbnail"`
+	LastUpdated      int64          `json:"last_updated"`
+	UnreadCount      int            `json:"unread_count"`
+	CustomDir        string         `json:"custom_dir"`
+	DownloadLatest   bool           `json:"download_latest"`
+	Filters          string         `json:"filters"`
+	Tags             string         `json:"tags"`
+	FilenameTemplate string         `json:"filename_template"`
+	CategoryID       string         `gorm:"index" json:"category_id"`
+	Enabled          bool           `gorm:"index" json:"enabled"`
+	RefreshInterval  int            `json:"refresh_interval"` // Minutes, 0 uses the global interval
+	RefreshCron      string         `json:"refresh_cron"`     // Standard cron spec, wins over the interval
+	NextRefreshAt    int64          `gorm:"index;default:0;not null" json:"next_refresh_at"`
+	ETag             string         `gorm:"column:etag" json:"-"`
+	LastModified     string         `json:"-"`
+	LastSuccessAt    int64          `json:"last_success_at"`
+	LastError        string         `json:"last_error"`
+	LastErrorAt      int64          `json:"last_error_at"`
+	FailureCount     int            `gorm:"index;default:0;not null" json:"failure_count"` // Consecutive failed refreshes
+	RetentionItems   int            `json:"retention_items"`                               // Newest items kept, 0 uses the global limit, -1 keeps all
+	RetentionDays    int            `json:"retention_days"`                                // Days read items are kept, 0 uses the global limit, -1 keeps them
+	CreatedAt        int64          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        int64          `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 type FeedItem struct {
-	ID          string        `gorm:"primaryKey;size:36" json:"id"`
-	FeedID      string        `gorm:"index;uniqueIndex:idx_feed_items_link" json:"feed_id"`
-	Title       string        `json:"title"`
-	Link        string        `gorm:"uniqueIndex:idx_feed_items_link" json:"link"`
-	Description string        `json:"description"`
-	PubDate     int64         `gorm:"index" json:"pub_date"`
-	Status      RSSItemStatus `gorm:"index" json:"status"`
-	Thumbnail   string        `json:"thumbnail"`
-	CreatedAt   int64         `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   int64         `gorm:"autoUpdateTime" json:"updated_at"`
+	ID              string        `gorm:"primaryKey;size:36" json:"id"`
+	FeedID          string        `gorm:"index;uniqueIndex:idx_feed_items_link" json:"feed_id"`
+	Title           string        `json:"title"`
+	Link            string        `gorm:"uniqueIndex:idx_feed_items_link" json:"link"`
+	Description     string        `json:"description"`
+	PubDate         int64         `gorm:"index" json:"pub_date"`
+	Status          RSSItemStatus `gorm:"index" json:"status"`
+	Thumbnail       string        `json:"thumbnail"`
+	Duration        float64       `json:"duration"`
+	Author          string        `json:"author"`
+	EnclosureURL    string        `json:"enclosure_url"` // Media of the item, podcast feeds download it directly
+	EnclosureType   string        `json:"enclosure_type"`
+	EnclosureLength int64         `json:"enclosure_length"`
+	CreatedAt       int64         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       int64         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// FeedItemTombstone remembers the link of a pruned item so refreshing the
+// feed does not add it back and download it again. It lives as long as the
+// feed keeps listing the link.
+type FeedItemTombstone struct {
+	FeedID   string `gorm:"primaryKey;size:36" json:"feed_id"`
+	Link     string `gorm:"primaryKey" json:"link"`
+	PrunedAt int64  `json:"pruned_at"`
+	SeenAt   int64  `gorm:"index" json:"seen_at"` // Last fetch still listing the link
 }
 
 type AddRSSTaskInput struct {
-	FeedURL       string `json:"feed_url"`
-	FeedTitle     string `json:"feed_title"`
-	FeedThumbnail string `json:"feed_thumbnail"`
-	ItemURL       string `json:"item_url"`
-	ItemTitle     string `json:"item_title"`
-	ItemThumbnail string `json:"item_thumbnail"`
-	Dir           string `json:"dir"`
-	CategoryID    string `json:"category_id"`
+	FeedURL          string `json:"feed_url"`
+	FeedTitle        string `json:"feed_title"`
+	FeedThumbnail    string `json:"feed_thumbnail"`
+	ItemURL          string `json:"item_url"`
+	ItemTitle        string `json:"item_title"`
+	ItemThumbnail    string `json:"item_thumbnail"`
+	Dir              string `json:"dir"`
+	CategoryID       string `json:"category_id"`
+	FilenameTemplate string `json:"filename_template"`
+	FeedID           string `json:"feed_id"`
+	FeedItemID       string `json:"feed_item_id"`
+	ItemDescription  string `json:"item_description"`
+	FeedTags         string `json:"feed_tags"`
+	Priority         int    `json:"priority"`
+	ItemAuthor       string `json:"item_author"`
+	ItemPubDate      int64  `json:"item_pub_date"`
+	EnclosureURL     string `json:"enclosure_url"` // Set for podcast feeds
+	EnclosureType    string `json:"enclosure_type"`
+	EnclosureLength  int64  `json:"enclosure_length"`
 }
 
 type AddRSSFeedInput struct {
-	URL              string `json:"url"`
-	CustomDir        string `json:"custom_dir"`
-	DownloadLatest   bool   `json:"download_latest"`
-	Filters          string `json:"filters"`
-	Tags             string `json:"tags"`
-	FilenameTemplate string `json:"filename_template"`
-	CategoryID       string `json:"category_id"`
+	URL              string         `json:"url"`
+	SourceType       FeedSourceType `json:"source_type"`
+	Podcast          bool           `json:"podcast"`
+	CustomDir        string         `json:"custom_dir"`
+	DownloadLatest   bool           `json:"download_latest"`
+	Filters          string         `json:"filters"`
+	Tags             string         `json:"tags"`
+	FilenameTemplate string         `json:"filename_template"`
+	CategoryID       string         `json:"category_id"`
+	RefreshInterval  int            `json:"refresh_interval"`
+	RefreshCron      string         `json:"refresh_cron"`
+	RetentionItems   int            `json:"retention_items"`
+	RetentionDays    int            `json:"retention_days"`
+}
+
+// RSSFilterPreview reports how a feed filter evaluates against one item.
+type RSSFilterPreview struct {
+	Item    FeedItem `json:"item"`
+	Matched bool     `json:"matched"`
+	Reason  string   `json:"reason"`
+}
+
+// RSSPruneResult counts what one retention cleanup removed.
+type RSSPruneResult struct {
+	Feeds      int   `json:"feeds"` // Feeds that lost items
+	Items      int64 `json:"items"`
+	Tombstones int64 `json:"tombstones"` // Expired tombstones
+}
+
+// OPMLFolderMode tells what the folders of an imported OPML file become.
+type OPMLFolderMode string
+
+const (
+	OPMLFolderCategory OPMLFolderMode = "category" // Innermost folder is the category
+	OPMLFolderTag      OPMLFolderMode = "tag"      // Every folder is added as a tag
+)
+
+type OPMLImportResult struct {
+	Added   int                 `json:"added"`
+	Skipped int                 `json:"skipped"` // Already subscribed
+	Failed  []OPMLImportFailure `json:"failed"`
+}
+
+type OPMLImportFailure struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	Error string `json:"error"`
 }
@@ -12,6 +12,43 @@ const (
 	TaskStatusCompleted   TaskStatus = "completed"
 	TaskStatusTrimFailed  TaskStatus = "trim_failed"
 	TaskStatusError       TaskStatus = "error"
+	TaskStatusCancelled   TaskStatus = "cancelled"
+	// Playlist and RSS parents only, some children failed and the rest completed
+	TaskStatusPartialFailed TaskStatus = "partial_failed"
+)
+
+// TaskErrorKind classifies why a download failed, parsed from yt-dlp output.
+type TaskErrorKind string
+
+const (
+	TaskErrorRateLimited       TaskErrorKind = "rate_limited"
+	TaskErrorForbidden         TaskErrorKind = "forbidden"
+	TaskErrorNetwork           TaskErrorKind = "network"
+	TaskErrorGeoBlocked        TaskErrorKind = "geo_blocked"
+	TaskErrorLoginRequired     TaskErrorKind = "login_required"
+	TaskErrorUnavailable       TaskErrorKind = "unavailable"
+	TaskErrorFormatUnavailable TaskErrorKind = "format_unavailable"
+	TaskErrorUnsupported       TaskErrorKind = "unsupported"
+	TaskErrorInternal          TaskErrorKind = "internal"
+	TaskErrorUnknown           TaskErrorKind = "unknown"
+)
+
+// Pending tasks run by priority, highest first, then by queue order.
+const (
+	TaskPriorityLow    = -1
+	TaskPriorityNormal = 0
+	TaskPriorityHigh   = 1
+	TaskPriorityUrgent = 2
+)
+
+// TaskMove is a manual reordering of a pending task within the queue.
+type TaskMove string
+
+const (
+	TaskMoveUp     TaskMove = "up"
+	TaskMoveDown   TaskMove = "down"
+	TaskMoveTop    TaskMove = "top"
+	TaskMoveBottom TaskMove = "bottom"
 )
 
 type SourceType int
@@ -31,30 +68,70 @@ const (
 )
 
 type Task struct {
-	ID         string     `gorm:"primaryKey;size:36" json:"id"`
-	URL        string     `gorm:"index" json:"url"`
-	Dir        string     `json:"dir"`
-	Quality    string     `json:"quality"`
-	Format     string     `json:"format"`
-	FormatID   string     `json:"format_id"`
-	ParentID   string     `gorm:"index" json:"parent_id"`
-	SourceType SourceType `gorm:"index" json:"source_type"`
-	Status     TaskStatus `gorm:"index" json:"status"`
-	Progress   float64    `json:"progress"`
-	Title      string     `json:"title"`
-	Thumbnail  string     `json:"thumbnail"`
-	Speed      string     `json:"speed"`
-	Eta        string     `json:"eta"`
-	LogPath    string     `json:"log_path"`
-	FileExists bool       `gorm:"column:file_exists" json:"file_exists"`
-	FilePath   string     `json:"file_path"`
-	TotalBytes int64      `json:"total_bytes"`
-	TrimStart  string     `json:"trim_start"`
-	TrimEnd    string     `json:"trim_end"`
-	TrimMode   TrimMode   `json:"trim_mode"`
-	CategoryID string     `gorm:"index" json:"category_id"`
-	CreatedAt  int64      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt  int64      `gorm:"autoUpdateTime" json:"updated_at"`
+	ID               string        `gorm:"primaryKey;size:36" json:"id"`
+	URL              string        `gorm:"index" json:"url"`
+	Dir              string        `json:"dir"`
+	Quality          string        `json:"quality"`
+	Format           string        `json:"format"`
+	FormatID         string        `json:"format_id"`
+	ParentID         string        `gorm:"index" json:"parent_id"`
+	SourceType       SourceType    `gorm:"index" json:"source_type"`
+	Status           TaskStatus    `gorm:"index" json:"status"`
+	Progress         float64       `json:"progress"`
+	Title            string        `json:"title"`
+	Thumbnail        string        `json:"thumbnail"`
+	DownloadedBytes  int64         `json:"downloaded_bytes"`
+	SpeedBps         float64       `json:"speed_bps"`   // Bytes per second
+	EtaSeconds       int64         `json:"eta_seconds"` // Zero when unknown
+	FragmentIndex    int           `json:"fragment_index"`
+	FragmentCount    int           `json:"fragment_count"`
+	LogPath          string        `json:"log_path"`
+	FileExists       bool          `gorm:"column:file_exists" json:"file_exists"`
+	FilePath         string        `json:"file_path"`
+	TotalBytes       int64         `json:"total_bytes"`
+	TrimStart        string        `json:"trim_start"`
+	TrimEnd          string        `json:"trim_end"`
+	TrimMode         TrimMode      `json:"trim_mode"`
+	CategoryID       string        `gorm:"index" json:"category_id"`
+	OutputTemplate   string        `json:"output_template"`
+	FeedID           string        `gorm:"index" json:"feed_id"`
+	FeedItemID       string        `json:"feed_item_id"`
+	Description      string        `gorm:"type:text" json:"description"`
+	Tags             string        `json:"tags"`
+	Priority         int           `gorm:"index;default:0;not null" json:"priority"`
+	QueueOrder       int64         `gorm:"default:0;not null" json:"queue_order"`
+	ErrorKind        TaskErrorKind `json:"error_kind"`
+	ErrorMessage     string        `gorm:"type:text" json:"error_message"`
+	Attempts         int           `gorm:"default:0;not null" json:"attempts"`
+	NextRetryAt      int64         `gorm:"index;default:0;not null" json:"next_retry_at"`
+	StartedAt        int64         `gorm:"index;default:0;not null" json:"started_at"` // Last time the scheduler started the download
+	FallbackCookies  bool          `json:"fallback_cookies"`
+	FallbackProxy    bool          `json:"fallback_proxy"`
+	ExternalID       string        `json:"external_id"`                // Extractor ID of a playlist entry
+	FilenameTemplate string        `json:"filename_template"`          // Raw filename template of a playlist parent
+	SyncInterval     int           `gorm:"index" json:"sync_interval"` // Minutes between playlist syncs, 0 is manual
+	RemovedEntries   string        `gorm:"type:text" json:"-"`         // Deleted playlist entries skipped by syncs, one per line
+	LastSyncedAt     int64         `json:"last_synced_at"`
+	EnclosureURL     string        `json:"enclosure_url"` // Fetched over plain HTTP instead of yt-dlp
+	Author           string        `json:"author"`
+	PublishedAt      int64         `json:"published_at"`
+	CreatedAt        int64         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        int64         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type QueuePauseReason string
+
+const (
+	QueuePausedDiskSpace QueuePauseReason = "disk_space"
+)
+
+// QueueStatus tells whether the scheduler holds back pending downloads.
+type QueueStatus struct {
+	Paused        bool             `json:"paused"`
+	Reason        QueuePauseReason `json:"reason"`
+	Dir           string           `json:"dir"`
+	FreeBytes     int64            `json:"free_bytes"`
+	RequiredBytes int64            `json:"required_bytes"`
 }
 
 type DownloadFile struct {
@@ -65,26 +142,31 @@ type DownloadFile struct {
 }
 
 type AddTaskInput struct {
-	URL        string     `json:"url"`
-	Quality    string     `json:"quality"`
-	Format     string     `json:"format"`
-	FormatID   string     `json:"format_id"`
-	Dir        string     `json:"dir"`
-	Title      string     `json:"title"`
-	Thumbnail  string     `json:"thumbnail"`
-	TotalBytes int64      `json:"total_bytes"`
-	TrimStart  string     `json:"trim_start"`
-	TrimEnd    string     `json:"trim_end"`
-	TrimMode   TrimMode   `json:"trim_mode"`
-	SourceType SourceType `json:"source_type"`
-	CategoryID string     `json:"category_id"`
+	URL              string     `json:"url"`
+	Quality          string     `json:"quality"`
+	Format           string     `json:"format"`
+	FormatID         string     `json:"format_id"`
+	Dir              string     `json:"dir"`
+	Title            string     `json:"title"`
+	Thumbnail        string     `json:"thumbnail"`
+	TotalBytes       int64      `json:"total_bytes"`
+	TrimStart        string     `json:"trim_start"`
+	TrimEnd          string     `json:"trim_end"`
+	TrimMode         TrimMode   `json:"trim_mode"`
+	SourceType       SourceType `json:"source_type"`
+	CategoryID       string     `json:"category_id"`
+	FilenameTemplate string     `json:"filename_template"`
+	Priority         int        `json:"priority"`
 }
 
 type AddPlaylistTaskInput struct {
-	URL           string         `json:"url"`
-	Dir           string         `json:"dir"`
-	Title         string         `json:"title"`
-	Thumbnail     string         `json:"thumbnail"`
-	PlaylistItems []PlaylistItem `json:"playlist_items"`
-	CategoryID    string         `json:"category_id"`
+	URL              string         `json:"url"`
+	Dir              string         `json:"dir"`
+	Title            string         `json:"title"`
+	Thumbnail        string         `json:"thumbnail"`
+	PlaylistItems    []PlaylistItem `json:"playlist_items"`
+	CategoryID       string         `json:"category_id"`
+	FilenameTemplate string         `json:"filename_template"`
+	Priority         int            `json:"priority"`
+	SyncInterval     int            `json:"sync_interval"` // Minutes, 0 only syncs on demand
 }
@@ -27,6 +27,9 @@ type Video struct {
 	Evaluation  string  `json:"evaluation"`
 	CategoryID  string  `gorm:"index" json:"category_id"`
 	Status      string  `gorm:"index" json:"status"`
+	FeedID      string  `gorm:"index" json:"feed_id"`
+	FeedItemID  string  `json:"feed_item_id"`
+	SourceTags  string  `json:"source_tags"`
 
 	// Virtual fields for JSON
 	TagsList   []string         `gorm:"-" json:"tags"`
@@ -49,11 +52,15 @@ func (v *Video) AfterFind(tx *gorm.DB) (err error) {
 }
 
 type PlaylistItem struct {
+	ID        string  `json:"id"` // Extractor ID of the entry
 	Index     int     `json:"index"`
 	Title     string  `json:"title"`
 	Duration  float64 `json:"duration"`
 	Thumbnail string  `json:"thumbnail"`
 	URL       string  `json:"url"`
+	// Only filled when the site reports them in the listing
+	Description string `json:"description,omitempty"`
+	PublishedAt int64  `json:"published_at,omitempty"`
 }
 
 type QualityOption struct {
@@ -81,4 +88,5 @@ type VideoInfo struct {
 type VideoFilter struct {
 	Status string `json:"status"` // "all", "analyzed", "unanalyzed"
 	Query  string `json:"query"`
+	FeedID string `json:"feed_id"`
 }
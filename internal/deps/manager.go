@@ -3,6 +3,7 @@ package deps
 import (
 	"context"
 	"errors"
+	"sync"
 )
 
 type AssetProvider func(name string) ([]byte, error)
@@ -12,12 +13,20 @@ type Manager struct {
 	AssetProvider AssetProvider
 	YtDlpPath     string
 	FFmpegPath    string
+	WhisperPaths  map[string]string // Executable of each local ASR engine
+
+	whisperMu        sync.Mutex
+	whisperInstallMu sync.Mutex // Serializes the whisper.cpp download
+	downloadMu       sync.Mutex
+	modelDownloads   map[string]*modelDownload // In flight model downloads by file
 }
 
 func NewManager(ctx context.Context, ap AssetProvider) *Manager {
 	return &Manager{
-		Ctx:           ctx,
-		AssetProvider: ap,
+		Ctx:            ctx,
+		AssetProvider:  ap,
+		WhisperPaths:   make(map[string]string),
+		modelDownloads: make(map[string]*modelDownload),
 	}
 }
 
@@ -0,0 +1,458 @@
+package deps
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	runtime "runtime"
+	"strings"
+
+	"Kairo/internal/config"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Local ASR engines, selectable as the provider of the Whisper settings.
+const (
+	WhisperEngineCpp    = "whispercpp"     // whisper.cpp, runs ggml model files
+	WhisperEngineFaster = "faster-whisper" // Standalone faster-whisper build, fetches its own models
+)
+
+// whisperBinaries lists the executable names of each engine, newest first.
+var whisperBinaries = map[string][]string{
+	WhisperEngineCpp:    {"whisper-cli", "whisper-cpp"},
+	WhisperEngineFaster: {"faster-whisper-xxl", "faster-whisper"},
+}
+
+const (
+	whisperModelURL = "https://huggingface.co/ggerganov/whisper.cpp/resolve/main/ggml-%s.bin"
+	// Pinned whisper.cpp release installed on Windows, the archive is
+	// checked against the digest GitHub publishes for it
+	whisperCppRepo    = "ggml-org/whisper.cpp"
+	whisperCppVersion = "v1.7.6"
+	whisperCppAsset   = "whisper-bin-x64.zip"
+)
+
+// IsWhisperEngine tells whether provider names a local ASR engine.
+func IsWhisperEngine(provider string) bool {
+	_, ok := whisperBinaries[provider]
+	return ok
+}
+
+func (m *Manager) GetWhisperPath(engine string) (string, error) {
+	m.EnsureWhisper(engine)
+	m.whisperMu.Lock()
+	defer m.whisperMu.Unlock()
+	if m.WhisperPaths[engine] == "" {
+		return "", fmt.Errorf("%s not found, install it or place it in the bin directory", engine)
+	}
+	return m.WhisperPaths[engine], nil
+}
+
+// EnsureWhisper looks for the executable of a local ASR engine in the bin
+// directory, the embedded assets and PATH. On Windows the whisper.cpp
+// release is downloaded when nothing is found.
+func (m *Manager) EnsureWhisper(engine string) {
+	if !IsWhisperEngine(engine) || m.findWhisper(engine) {
+		return
+	}
+	if engine != WhisperEngineCpp || runtime.GOOS != "windows" {
+		wailsRuntime.LogWarning(m.Ctx, "未找到 "+engine+"，请先安装或放入 bin 目录")
+		return
+	}
+
+	// The download runs outside whisperMu so lookups are not held up by it,
+	// concurrent callers wait here and find the installed copy
+	m.whisperInstallMu.Lock()
+	defer m.whisperInstallMu.Unlock()
+	if m.findWhisper(engine) {
+		return
+	}
+	if final := m.installWhisperCpp(); final != "" {
+		m.whisperMu.Lock()
+		m.WhisperPaths[engine] = final
+		m.whisperMu.Unlock()
+	}
+}
+
+// findWhisper records the executable of engine found in the bin directory,
+// the embedded assets or PATH and reports whether there is one.
+func (m *Manager) findWhisper(engine string) bool {
+	m.whisperMu.Lock()
+	defer m.whisperMu.Unlock()
+	if p := m.WhisperPaths[engine]; p != "" {
+		if _, err := os.Stat(p); err == nil {
+			return true
+		}
+	}
+	base, err := config.GetBinDir()
+	if err != nil {
+		return false
+	}
+
+	names := whisperBinaries[engine]
+	for _, name := range names {
+		if runtime.GOOS == "windows" {
+			name += ".exe"
+		}
+		final := filepath.Join(base, name)
+		if _, err := os.Stat(final); err == nil {
+			m.WhisperPaths[engine] = final
+			return true
+		}
+		if m.AssetProvider != nil {
+			if data, err := m.AssetProvider(name); err == nil && len(data) > 0 {
+				tmp := final + ".tmp"
+				if err := os.WriteFile(tmp, data, 0o755); err == nil {
+					_ = os.Chmod(tmp, 0o755)
+					if err := os.Rename(tmp, final); err == nil {
+						wailsRuntime.LogInfo(m.Ctx, "已从内置资源安装 "+name)
+						m.WhisperPaths[engine] = final
+						return true
+					}
+				}
+			}
+		}
+	}
+	for _, name := range names {
+		// whisper.cpp needs the libraries next to the executable, so the
+		// one found on PATH is used in place
+		if p, err := exec.LookPath(name); err == nil {
+			m.WhisperPaths[engine] = p
+			return true
+		}
+	}
+	return false
+}
+
+// installWhisperCpp downloads the pinned whisper.cpp release into the bin
+// directory and returns the path of whisper-cli.exe, empty on failure.
+func (m *Manager) installWhisperCpp() string {
+	base, err := config.GetBinDir()
+	if err != nil {
+		return ""
+	}
+	wailsRuntime.LogInfo(m.Ctx, "正在下载 whisper.cpp "+whisperCppVersion+"...")
+	digest, err := githubAssetSHA256(m.Ctx, whisperCppRepo, whisperCppVersion, whisperCppAsset)
+	if err != nil {
+		wailsRuntime.LogError(m.Ctx, "whisper.cpp 校验信息获取失败: "+err.Error())
+		return ""
+	}
+	url := fmt.Sprintf("https://github.com/%s/releases/download/%s/%s", whisperCppRepo, whisperCppVersion, whisperCppAsset)
+	tmpArchive := filepath.Join(base, "whisper_archive.tmp")
+	defer os.Remove(tmpArchive)
+	if err := downloadFile(m.Ctx, url, tmpArchive, digest, nil); err != nil {
+		wailsRuntime.LogError(m.Ctx, "whisper.cpp 下载失败: "+err.Error())
+		return ""
+	}
+	if err := extractWhisperZip(tmpArchive, base); err != nil {
+		wailsRuntime.LogError(m.Ctx, "whisper.cpp 解压失败: "+err.Error())
+		return ""
+	}
+	final := filepath.Join(base, "whisper-cli.exe")
+	if _, err := os.Stat(final); err != nil {
+		wailsRuntime.LogError(m.Ctx, "whisper.cpp 安装包中没有 whisper-cli.exe")
+		return ""
+	}
+	wailsRuntime.LogInfo(m.Ctx, "whisper.cpp 安装成功")
+	return final
+}
+
+// modelDownload is a model file being fetched, shared by every caller
+// waiting for it. The download stops once no caller waits anymore.
+type modelDownload struct {
+	done    chan struct{}
+	err     error
+	cancel  context.CancelFunc
+	waiters int
+}
+
+// GetWhisperModelPath returns the ggml model file of whisper.cpp for a
+// model name such as "base" or "large-v3-turbo", downloading it on first
+// use. A path to an existing file is returned as is. Concurrent callers
+// share one download, progress is only reported to the one starting it.
+func (m *Manager) GetWhisperModelPath(ctx context.Context, model string, progress func(float64)) (string, error) {
+	if _, err := os.Stat(model); err == nil {
+		return model, nil
+	}
+	if model == "" || strings.ContainsAny(model, `/\`) {
+		return "", fmt.Errorf("invalid whisper model %q", model)
+	}
+	dir, err := config.GetModelDir()
+	if err != nil {
+		return "", err
+	}
+	final := filepath.Join(dir, "ggml-"+model+".bin")
+	if _, err := os.Stat(final); err == nil {
+		return final, nil
+	}
+
+	m.downloadMu.Lock()
+	d := m.modelDownloads[final]
+	if d == nil {
+		dctx, cancel := context.WithCancel(m.Ctx)
+		d = &modelDownload{done: make(chan struct{}), cancel: cancel}
+		m.modelDownloads[final] = d
+		go m.downloadModel(dctx, d, model, final, progress)
+	}
+	d.waiters++
+	m.downloadMu.Unlock()
+
+	select {
+	case <-d.done:
+		if d.err != nil {
+			return "", d.err
+		}
+		return final, nil
+	case <-ctx.Done():
+		m.downloadMu.Lock()
+		d.waiters--
+		if d.waiters == 0 {
+			d.cancel()
+			// Later callers start a new download instead of joining this one
+			if m.modelDownloads[final] == d {
+				delete(m.modelDownloads, final)
+			}
+		}
+		m.downloadMu.Unlock()
+		return "", ctx.Err()
+	}
+}
+
+func (m *Manager) downloadModel(ctx context.Context, d *modelDownload, model, final string, progress func(float64)) {
+	defer func() {
+		m.downloadMu.Lock()
+		if m.modelDownloads[final] == d {
+			delete(m.modelDownloads, final)
+		}
+		m.downloadMu.Unlock()
+		d.cancel()
+		close(d.done)
+	}()
+
+	wailsRuntime.LogInfo(m.Ctx, "正在下载 Whisper 模型: "+model)
+	url := fmt.Sprintf(whisperModelURL, model)
+	digest, err := huggingFaceSHA256(ctx, url)
+	if err != nil {
+		d.err = fmt.Errorf("download whisper model %s: %w", model, err)
+		return
+	}
+	// A cancelled download may still be winding down, each one gets its own
+	// temporary file
+	f, err := os.CreateTemp(filepath.Dir(final), filepath.Base(final)+".*.tmp")
+	if err != nil {
+		d.err = err
+		return
+	}
+	tmp := f.Name()
+	f.Close()
+	if err := downloadFile(ctx, url, tmp, digest, progress); err != nil {
+		_ = os.Remove(tmp)
+		d.err = fmt.Errorf("download whisper model %s: %w", model, err)
+		return
+	}
+	if err := os.Rename(tmp, final); err != nil {
+		_ = os.Remove(tmp)
+		d.err = err
+		return
+	}
+	wailsRuntime.LogInfo(m.Ctx, "Whisper 模型下载完成: "+model)
+}
+
+// GetWhisperModelDir returns where faster-whisper keeps the models it
+// fetches.
+func (m *Manager) GetWhisperModelDir() (string, error) {
+	dir, err := config.GetModelDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "faster-whisper")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// githubAssetSHA256 returns the SHA-256 GitHub publishes for an asset of a
+// release.
+func githubAssetSHA256(ctx context.Context, repo, tag, asset string) (string, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/releases/tags/%s", repo, tag)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New(resp.Status)
+	}
+	var release struct {
+		Assets []struct {
+			Name   string `json:"name"`
+			Digest string `json:"digest"`
+		} `json:"assets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", err
+	}
+	for _, a := range release.Assets {
+		if a.Name == asset {
+			return parseSHA256(strings.TrimPrefix(a.Digest, "sha256:"))
+		}
+	}
+	return "", fmt.Errorf("%s not found in release %s", asset, tag)
+}
+
+// huggingFaceSHA256 returns the SHA-256 of a file stored with Git LFS on
+// Hugging Face, sent as the linked ETag before the redirect to the CDN.
+func huggingFaceSHA256(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+	if err != nil {
+		return "", err
+	}
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", errors.New(resp.Status)
+	}
+	return parseSHA256(strings.Trim(resp.Header.Get("X-Linked-Etag"), `"`))
+}
+
+func parseSHA256(digest string) (string, error) {
+	digest = strings.ToLower(strings.TrimSpace(digest))
+	if b, err := hex.DecodeString(digest); err != nil || len(b) != sha256.Size {
+		return "", errors.New("no sha256 checksum published")
+	}
+	return digest, nil
+}
+
+// downloadFile fetches url into dest and fails unless its SHA-256 equals
+// digest. progress receives the percentage when the size is known.
+func downloadFile(ctx context.Context, url, dest, digest string, progress func(float64)) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	// Large models take a while on slow connections, only ctx bounds it
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(resp.Status)
+	}
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	var body io.Reader = resp.Body
+	if progress != nil && resp.ContentLength > 0 {
+		body = &progressReader{r: resp.Body, total: resp.ContentLength, progress: progress, last: -1}
+	}
+	if _, err := io.Copy(io.MultiWriter(out, hash), body); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != digest {
+		return fmt.Errorf("checksum mismatch, got sha256 %s, want %s", got, digest)
+	}
+	return nil
+}
+
+// progressReader reports the whole percentages read of a known size.
+type progressReader struct {
+	r        io.Reader
+	read     int64
+	total    int64
+	last     int
+	progress func(float64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+	if percent := int(p.read * 100 / p.total); percent > p.last {
+		p.last = percent
+		p.progress(float64(min(percent, 100)))
+	}
+	return n, err
+}
+
+// extractWhisperZip flattens the directory holding whisper-cli.exe into
+// dest, the executable loads the DLLs shipped beside it.
+func extractWhisperZip(src, dest string) error {
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	dir := ""
+	found := false
+	for _, f := range r.File {
+		if path.Base(f.Name) == "whisper-cli.exe" {
+			dir = path.Dir(f.Name)
+			found = true
+			break
+		}
+	}
+	if !found {
+		return errors.New("whisper-cli.exe not found in zip")
+	}
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || path.Dir(f.Name) != dir {
+			continue
+		}
+		if err := extractZipFile(f, filepath.Join(dest, path.Base(f.Name))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractZipFile(f *zip.File, dest string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, rc); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
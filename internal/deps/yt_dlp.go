@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	runtime "runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -147,6 +148,32 @@ func (m *Manager) GetVideoInfo(url string) (*schema.VideoInfo, error) {
 	return nil, err
 }
 
+// GetVideoDetails returns the title, thumbnail and duration of a single
+// video URL. Flat playlist listings leave them out on some sites.
+func (m *Manager) GetVideoDetails(url string) (*schema.VideoInfo, error) {
+	m.EnsureYtDlp()
+	if m.YtDlpPath == "" {
+		return nil, errors.New("yt-dlp not found")
+	}
+	return m.getSingleVideoInfo(url)
+}
+
+// GetPlaylistInfo extracts the entries of a playlist URL.
+func (m *Manager) GetPlaylistInfo(url string) (*schema.VideoInfo, error) {
+	m.EnsureYtDlp()
+	if m.YtDlpPath == "" {
+		return nil, errors.New("yt-dlp not found")
+	}
+	info, err := m.getPlaylistInfo(url)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, errors.New("url is not a playlist")
+	}
+	return info, nil
+}
+
 func (m *Manager) isLikelyPlaylist(url string) bool {
 	lower := strings.ToLower(url)
 	return strings.Contains(lower, "list=") ||
@@ -161,25 +188,7 @@ func (m *Manager) isLikelyPlaylist(url string) bool {
 
 func (m *Manager) getSingleVideoInfo(url string) (*schema.VideoInfo, error) {
 	args := []string{"--dump-json", "--no-playlist"}
-	if proxy := config.GetProxyUrl(); proxy != "" {
-		args = append(args, "--proxy", proxy)
-	}
-
-	if ua := config.GetUserAgent(); ua != "" {
-		args = append(args, "--user-agent", ua)
-	}
-	if ref := config.GetReferer(); ref != "" {
-		args = append(args, "--referer", ref)
-	}
-	if config.GetGeoBypass() {
-		args = append(args, "--geo-bypass")
-	} else {
-		args = append(args, "--no-geo-bypass")
-	}
-
-	if cookieArgs := config.GetCookieArgs(); len(cookieArgs) > 0 {
-		args = append(args, cookieArgs...)
-	}
+	args = append(args, config.ResolveNetworkOptions(url).Args()...)
 
 	args = append(args, url)
 
@@ -196,33 +205,57 @@ func (m *Manager) getSingleVideoInfo(url string) (*schema.VideoInfo, error) {
 	return m.ParseVideoInfo(output)
 }
 
-func (m *Manager) getPlaylistInfo(url string) (*schema.VideoInfo, error) {
-	args := []string{"--dump-single-json"}
-
-	lowerURL := strings.ToLower(url)
-	isBilibili := strings.Contains(lowerURL, "bilibili.com") || strings.Contains(lowerURL, "b23.tv")
-	if !isBilibili {
-		args = append(args, "--flat-playlist")
+// GetChannelEntries lists the latest uploads of a channel or user page, at
+// most limit entries. Dates are only known when the site reports them.
+func (m *Manager) GetChannelEntries(url string, limit int) (*schema.VideoInfo, error) {
+	m.EnsureYtDlp()
+	if m.YtDlpPath == "" {
+		return nil, errors.New("yt-dlp not found")
 	}
-	if proxy := config.GetProxyUrl(); proxy != "" {
-		args = append(args, "--proxy", proxy)
+	// Always flat, polling a channel must not resolve every upload. Titles
+	// Bilibili leaves out are resolved when an entry is downloaded or filtered.
+	args := []string{"--dump-single-json", "--flat-playlist"}
+	if limit > 0 {
+		args = append(args, "--playlist-end", strconv.Itoa(limit))
 	}
+	args = append(args, config.ResolveNetworkOptions(url).Args()...)
+	args = append(args, url)
 
-	if ua := config.GetUserAgent(); ua != "" {
-		args = append(args, "--user-agent", ua)
+	cmd := utils.CreateCommand(m.YtDlpPath, args...)
+	output, err := cmd.Output()
+	if err != nil {
+		log.Printf("[GetChannelEntries] yt-dlp error: %s", err.Error())
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("yt-dlp error: %s", string(exitErr.Stderr))
+		}
+		return nil, err
 	}
-	if ref := config.GetReferer(); ref != "" {
-		args = append(args, "--referer", ref)
+
+	info, err := parsePlaylistInfo(output)
+	if err != nil {
+		return nil, err
 	}
-	if config.GetGeoBypass() {
-		args = append(args, "--geo-bypass")
-	} else {
-		args = append(args, "--no-geo-bypass")
+	if info == nil {
+		return nil, errors.New("url is not a channel")
 	}
+	return info, nil
+}
 
-	if cookieArgs := config.GetCookieArgs(); len(cookieArgs) > 0 {
-		args = append(args, cookieArgs...)
+// playlistArgs lists the entries of a playlist without resolving each one,
+// except on Bilibili where flat entries lack titles.
+func playlistArgs(url string) []string {
+	args := []string{"--dump-single-json"}
+	lowerURL := strings.ToLower(url)
+	isBilibili := strings.Contains(lowerURL, "bilibili.com") || strings.Contains(lowerURL, "b23.tv")
+	if !isBilibili {
+		args = append(args, "--flat-playlist")
 	}
+	return args
+}
+
+func (m *Manager) getPlaylistInfo(url string) (*schema.VideoInfo, error) {
+	args := playlistArgs(url)
+	args = append(args, config.ResolveNetworkOptions(url).Args()...)
 	args = append(args, url)
 
 	cmd := utils.CreateCommand(m.YtDlpPath, args...)
@@ -234,19 +267,28 @@ func (m *Manager) getPlaylistInfo(url string) (*schema.VideoInfo, error) {
 		}
 		return nil, err
 	}
+	return parsePlaylistInfo(output)
+}
 
+// parsePlaylistInfo parses the --dump-single-json output of a playlist, it
+// returns nil when the output is a single video.
+func parsePlaylistInfo(output []byte) (*schema.VideoInfo, error) {
 	var rawInfo struct {
 		Title      string           `json:"title"`
 		Thumbnail  string           `json:"thumbnail"`
 		Thumbnails []thumbnailEntry `json:"thumbnails"`
 		Type       string           `json:"_type"`
 		Entries    []struct {
-			Title      string           `json:"title"`
-			Duration   float64          `json:"duration"`
-			Thumbnail  string           `json:"thumbnail"`
-			Thumbnails []thumbnailEntry `json:"thumbnails"`
-			URL        string           `json:"url"`
-			WebpageURL string           `json:"webpage_url"`
+			ID          string           `json:"id"`
+			Title       string           `json:"title"`
+			Description string           `json:"description"`
+			Duration    float64          `json:"duration"`
+			Thumbnail   string           `json:"thumbnail"`
+			Thumbnails  []thumbnailEntry `json:"thumbnails"`
+			URL         string           `json:"url"`
+			WebpageURL  string           `json:"webpage_url"`
+			Timestamp   float64          `json:"timestamp"`
+			UploadDate  string           `json:"upload_date"`
 		} `json:"entries"`
 	}
 
@@ -278,12 +320,21 @@ func (m *Manager) getPlaylistInfo(url string) (*schema.VideoInfo, error) {
 		if itemURL == "" {
 			itemURL = strings.TrimSpace(entry.URL)
 		}
+		publishedAt := int64(entry.Timestamp)
+		if publishedAt == 0 && entry.UploadDate != "" {
+			if t, err := time.Parse("20060102", entry.UploadDate); err == nil {
+				publishedAt = t.Unix()
+			}
+		}
 		items = append(items, schema.PlaylistItem{
-			Index:     i + 1,
-			Title:     itemTitle,
-			Duration:  entry.Duration,
-			Thumbnail: itemThumbnail,
-			URL:       itemURL,
+			ID:          entry.ID,
+			Index:       i + 1,
+			Title:       itemTitle,
+			Duration:    entry.Duration,
+			Thumbnail:   itemThumbnail,
+			URL:         itemURL,
+			Description: entry.Description,
+			PublishedAt: publishedAt,
 		})
 	}
 
@@ -0,0 +1,162 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	TaskUpdate    = "task:update"
+	TaskLog       = "task:log"
+	VideoAIStatus = "video:ai_status"
+	VideoPipeline = "video:pipeline"
+	JobUpdate     = "job:update"
+	QueueStatus   = "queue:status"
+)
+
+const (
+	defaultHistorySize = 500
+	subscriberBuffer   = 256
+)
+
+type Event struct {
+	ID   uint64      `json:"id"`
+	Type string      `json:"type"`
+	Time int64       `json:"time"`
+	Data interface{} `json:"data"`
+}
+
+// Bus fans out events published by the managers to every listener and
+// subscriber, keeping the most recent events so reconnecting clients can
+// catch up.
+type Bus struct {
+	mu        sync.RWMutex
+	nextID    uint64
+	history   []Event
+	size      int
+	listeners []func(Event)
+	subs      map[*Subscription]struct{}
+}
+
+type Subscription struct {
+	C      chan Event
+	bus    *Bus
+	types  map[string]struct{}
+	closed bool
+}
+
+func NewBus(historySize int) *Bus {
+	if historySize <= 0 {
+		historySize = defaultHistorySize
+	}
+	return &Bus{
+		size: historySize,
+		subs: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish records the event and delivers it. A nil bus drops events, which
+// keeps managers usable without any consumer attached.
+func (b *Bus) Publish(eventType string, data interface{}) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	b.nextID++
+	e := Event{
+		ID:   b.nextID,
+		Type: eventType,
+		Time: time.Now().UnixMilli(),
+		Data: data,
+	}
+	if len(b.history) >= b.size {
+		copy(b.history, b.history[1:])
+		b.history = b.history[:len(b.history)-1]
+	}
+	b.history = append(b.history, e)
+	listeners := b.listeners
+	for sub := range b.subs {
+		if !sub.accepts(eventType) {
+			continue
+		}
+		select {
+		case sub.C <- e:
+		default:
+			// Slow consumer: drop rather than block the publisher. The
+			// client can resync through replay using the last seen ID.
+		}
+	}
+	b.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(e)
+	}
+}
+
+// Listen registers a callback invoked synchronously for every event, in
+// publish order.
+func (b *Bus) Listen(fn func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listeners = append(b.listeners, fn)
+}
+
+// Subscribe returns a buffered subscription limited to the given event types
+// (all types when empty) together with the retained events newer than
+// afterID. When afterID is 0, up to replay of the latest events are returned.
+func (b *Bus) Subscribe(types []string, afterID uint64, replay int) (*Subscription, []Event) {
+	sub := &Subscription{
+		C:   make(chan Event, subscriberBuffer),
+		bus: b,
+	}
+	if len(types) > 0 {
+		sub.types = make(map[string]struct{}, len(types))
+		for _, t := range types {
+			sub.types[t] = struct{}{}
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	backlog := make([]Event, 0)
+	for _, e := range b.history {
+		if !sub.accepts(e.Type) {
+			continue
+		}
+		if afterID > 0 && e.ID <= afterID {
+			continue
+		}
+		backlog = append(backlog, e)
+	}
+	if afterID == 0 {
+		if replay <= 0 {
+			backlog = backlog[:0]
+		} else if len(backlog) > replay {
+			backlog = backlog[len(backlog)-replay:]
+		}
+	}
+
+	b.subs[sub] = struct{}{}
+	return sub, backlog
+}
+
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	delete(s.bus.subs, s)
+	close(s.C)
+}
+
+func (s *Subscription) accepts(eventType string) bool {
+	if s.types == nil {
+		return true
+	}
+	_, ok := s.types[eventType]
+	return ok
+}
@@ -0,0 +1,15 @@
+package events
+
+import (
+	"context"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ForwardToWails re-emits every bus event to the embedded webview under its
+// original event name, so the frontend keeps receiving the same payloads.
+func ForwardToWails(ctx context.Context, b *Bus) {
+	b.Listen(func(e Event) {
+		wailsRuntime.EventsEmit(ctx, e.Type, e.Data)
+	})
+}
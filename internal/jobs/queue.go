@@ -0,0 +1,381 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"Kairo/internal/config"
+	"Kairo/internal/db/dal"
+	"Kairo/internal/db/schema"
+	"Kairo/internal/events"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultMaxAttempts = 3
+	pollInterval       = 5 * time.Second
+	baseBackoff        = 15 * time.Second
+	maxBackoff         = 10 * time.Minute
+	progressInterval   = time.Second
+)
+
+// Handler executes one job. progress accepts a percentage in [0, 100].
+// Returning an error schedules a retry unless it is wrapped with Permanent
+// or the job has used all of its attempts.
+type Handler func(ctx context.Context, job *schema.Job, progress func(float64)) error
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error as not worth retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Queue is a DB-backed background job queue with a worker pool per job type.
+type Queue struct {
+	ctx       context.Context
+	jobDAL    *dal.JobDAL
+	bus       *events.Bus
+	mu        sync.Mutex
+	wake      map[string]chan struct{}
+	running   map[string]context.CancelFunc
+	cancelled map[string]struct{}
+}
+
+func NewQueue(ctx context.Context, db *gorm.DB, bus *events.Bus) *Queue {
+	q := &Queue{
+		ctx:       ctx,
+		bus:       bus,
+		wake:      make(map[string]chan struct{}),
+		running:   make(map[string]context.CancelFunc),
+		cancelled: make(map[string]struct{}),
+	}
+	if db != nil {
+		q.jobDAL = dal.NewJobDAL(db)
+		// Jobs that were running when the app exited start over
+		if err := q.jobDAL.ResetRunning(ctx); err != nil {
+			log.Printf("[JobQueue] failed to reset running jobs: %v", err)
+		}
+	}
+	return q
+}
+
+// Register starts the workers for a job type. The worker count comes from
+// the jobWorkers setting when present, otherwise from workers.
+func (q *Queue) Register(jobType string, workers int, handler Handler) {
+	if q.jobDAL == nil {
+		return
+	}
+	if n := config.GetJobWorkers(jobType); n > 0 {
+		workers = n
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+
+	q.mu.Lock()
+	wake, ok := q.wake[jobType]
+	if !ok {
+		wake = make(chan struct{}, 1)
+		q.wake[jobType] = wake
+	}
+	q.mu.Unlock()
+
+	for i := 0; i < workers; i++ {
+		go q.work(jobType, wake, handler)
+	}
+}
+
+func (q *Queue) work(jobType string, wake chan struct{}, handler Handler) {
+	for {
+		job, err := q.jobDAL.Claim(q.ctx, jobType)
+		if err != nil {
+			log.Printf("[JobQueue] failed to claim %s job: %v", jobType, err)
+		}
+		if job != nil {
+			q.run(job, handler)
+			continue
+		}
+
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-wake:
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (q *Queue) run(job *schema.Job, handler Handler) {
+	ctx, cancel := context.WithCancel(q.ctx)
+	q.mu.Lock()
+	q.running[job.ID] = cancel
+	if _, ok := q.cancelled[job.ID]; ok {
+		cancel()
+	}
+	q.mu.Unlock()
+	q.emit(job)
+
+	lastProgress := time.Now()
+	progress := func(p float64) {
+		if p < 0 {
+			p = 0
+		} else if p > 100 {
+			p = 100
+		}
+		job.Progress = p
+		if time.Since(lastProgress) < progressInterval && p < 100 {
+			return
+		}
+		lastProgress = time.Now()
+		_ = q.jobDAL.UpdateProgress(q.ctx, job.ID, p)
+		q.emit(job)
+	}
+
+	err := q.invoke(ctx, job, handler, progress)
+
+	// The job stays in running until its outcome is saved, Cancel relies
+	// on that to tell a claimed job from a finished one
+	q.mu.Lock()
+	_, wasCancelled := q.cancelled[job.ID]
+	q.mu.Unlock()
+	cancel()
+
+	now := time.Now().UnixMilli()
+	job.Attempts++
+	var permanent *permanentError
+	switch {
+	case wasCancelled:
+		job.Status = schema.JobStatusCancelled
+		job.Error = "cancelled"
+		job.FinishedAt = now
+	case err == nil:
+		job.Status = schema.JobStatusCompleted
+		job.Progress = 100
+		job.Error = ""
+		job.FinishedAt = now
+	case errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts:
+		job.Status = schema.JobStatusFailed
+		job.Error = err.Error()
+		job.FinishedAt = now
+	default:
+		job.Status = schema.JobStatusPending
+		job.Error = err.Error()
+		job.RunAt = now + backoff(job.Attempts).Milliseconds()
+	}
+	if err != nil && !wasCancelled {
+		log.Printf("[JobQueue] %s job %s attempt %d failed: %v", job.Type, job.ID, job.Attempts, err)
+	}
+
+	if saveErr := q.jobDAL.Save(q.ctx, job); saveErr != nil {
+		log.Printf("[JobQueue] failed to save job %s: %v", job.ID, saveErr)
+	}
+	q.mu.Lock()
+	delete(q.running, job.ID)
+	delete(q.cancelled, job.ID)
+	q.mu.Unlock()
+	q.emit(job)
+}
+
+func (q *Queue) invoke(ctx context.Context, job *schema.Job, handler Handler, progress func(float64)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, job, progress)
+}
+
+func backoff(attempts int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
+
+// Enqueue adds a job. When a pending or running job already exists for the
+// same type and refID, that job is returned instead of creating a duplicate.
+func (q *Queue) Enqueue(jobType, refID string, payload interface{}) (*schema.Job, error) {
+	if q.jobDAL == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	if refID != "" {
+		if existing, err := q.jobDAL.FindActive(q.ctx, jobType, refID); err == nil {
+			return existing, nil
+		}
+	}
+
+	var data string
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		data = string(b)
+	}
+
+	now := time.Now().UnixMilli()
+	job := &schema.Job{
+		ID:          uuid.New().String(),
+		Type:        jobType,
+		RefID:       refID,
+		Payload:     data,
+		Status:      schema.JobStatusPending,
+		MaxAttempts: defaultMaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := q.jobDAL.Create(q.ctx, job); err != nil {
+		return nil, err
+	}
+	q.emit(job)
+	q.notify(jobType)
+	return job, nil
+}
+
+// Cancel stops a pending or running job. Running handlers see their context
+// cancelled; the job is recorded as cancelled once the handler returns.
+func (q *Queue) Cancel(id string) error {
+	if q.jobDAL == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	job, err := q.jobDAL.GetByID(q.ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if job.Status == schema.JobStatusPending {
+		ok, err := q.jobDAL.CancelPending(q.ctx, id)
+		if err != nil {
+			return err
+		}
+		if ok {
+			job.Status = schema.JobStatusCancelled
+			job.Error = "cancelled"
+			job.FinishedAt = time.Now().UnixMilli()
+			q.emit(job)
+			return nil
+		}
+		// A worker claimed it first, cancel the run instead
+		if job, err = q.jobDAL.GetByID(q.ctx, id); err != nil {
+			return err
+		}
+	}
+	if job.Status != schema.JobStatusRunning {
+		return fmt.Errorf("job is not active")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if cancel, ok := q.running[id]; ok {
+		q.cancelled[id] = struct{}{}
+		cancel()
+		return nil
+	}
+	// Not registered by run, either claimed and about to start or already
+	// finished. Run keeps a job registered until its outcome is saved, so
+	// the stored status tells which.
+	if job, err = q.jobDAL.GetByID(q.ctx, id); err != nil {
+		return err
+	}
+	if job.Status != schema.JobStatusRunning {
+		return fmt.Errorf("job is not active")
+	}
+	// Cancelled as soon as run registers it
+	q.cancelled[id] = struct{}{}
+	return nil
+}
+
+// Rerun resets a finished job so it runs again with a fresh attempt budget.
+func (q *Queue) Rerun(id string) error {
+	if q.jobDAL == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	job, err := q.jobDAL.GetByID(q.ctx, id)
+	if err != nil {
+		return err
+	}
+	if job.Status == schema.JobStatusPending || job.Status == schema.JobStatusRunning {
+		return fmt.Errorf("job is still active")
+	}
+	q.mu.Lock()
+	delete(q.cancelled, id)
+	q.mu.Unlock()
+
+	job.Status = schema.JobStatusPending
+	job.Attempts = 0
+	job.Progress = 0
+	job.Error = ""
+	job.RunAt = time.Now().UnixMilli()
+	job.StartedAt = 0
+	job.FinishedAt = 0
+	if err := q.jobDAL.Save(q.ctx, job); err != nil {
+		return err
+	}
+	q.emit(job)
+	q.notify(job.Type)
+	return nil
+}
+
+func (q *Queue) GetJob(id string) (*schema.Job, error) {
+	if q.jobDAL == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return q.jobDAL.GetByID(q.ctx, id)
+}
+
+func (q *Queue) ListJobs(filter schema.JobFilter) (*schema.JobListResponse, error) {
+	if q.jobDAL == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	jobs, total, err := q.jobDAL.List(q.ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &schema.JobListResponse{Total: total, Data: jobs}, nil
+}
+
+func (q *Queue) notify(jobType string) {
+	q.mu.Lock()
+	wake := q.wake[jobType]
+	q.mu.Unlock()
+	if wake == nil {
+		return
+	}
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Queue) emit(job *schema.Job) {
+	q.bus.Publish(events.JobUpdate, *job)
+}
+
+// DecodePayload unmarshals the job payload into v.
+func DecodePayload(job *schema.Job, v interface{}) error {
+	if job.Payload == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(job.Payload), v)
+}
@@ -1,6 +1,8 @@
 package publish
 
 import (
+	"fmt"
+
 	"Kairo/internal/db/schema"
 
 	"github.com/google/uuid"
@@ -54,3 +56,45 @@ func (p *PublishManager) DeleteAutomation(id string) error {
 	}
 	return err
 }
+
+// QueueHighlights schedules the highlights for automatic publishing on the
+// account, spaced by its publish interval. Highlights already queued for the
+// account are skipped. It returns the number of tasks created.
+func (p *PublishManager) QueueHighlights(accountID string, highlights []schema.VideoHighlight, tags string) (int, error) {
+	account, err := p.publishAccountDAL.GetAccountById(p.ctx, accountID)
+	if err != nil {
+		return 0, fmt.Errorf("account not found: %v", err)
+	}
+	if !account.IsEnabled {
+		return 0, fmt.Errorf("account is disabled")
+	}
+
+	interval := publishInterval(account)
+	baseTime, err := p.scheduleBase(accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, highlight := range highlights {
+		exists, err := p.publishTaskDAL.ExistsByHighlightAndAccount(p.ctx, highlight.ID, accountID)
+		if err != nil {
+			return created, err
+		}
+		if exists {
+			continue
+		}
+		baseTime = baseTime.Add(interval)
+		if _, err := p.CreateTask(schema.CreatePublishTaskRequest{
+			HighlightID: highlight.ID,
+			AccountID:   accountID,
+			PublishType: schema.PublishTypeAuto,
+			ScheduledAt: baseTime.UnixMilli(),
+			Tags:        tags,
+		}); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
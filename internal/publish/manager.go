@@ -140,29 +140,15 @@ func (p *PublishManager) runAutomationDetection(automationID string) {
 		return
 	}
 
-	interval, err := time.ParseDuration(account.PublishInterval)
-	if err != nil {
-		log.Printf("Failed to parse interval for automation %s: %v, defaulting to 1 hour\n", automationID, err)
-		interval = time.Hour
-	}
+	interval := publishInterval(account)
 
 	// Calculate base time for scheduling
-	latestTask, err := p.publishTaskDAL.GetLatestScheduledTask(p.ctx, auto.AccountID)
+	baseTime, err := p.scheduleBase(auto.AccountID)
 	if err != nil {
 		fmt.Printf("Failed to load latest task for account %s for automation %s: %v\n", auto.AccountID, automationID, err)
 		return
 	}
 
-	var baseTime time.Time
-	if latestTask != nil {
-		baseTime = time.UnixMilli(latestTask.ScheduledAt)
-	} else {
-		baseTime = time.Now()
-	}
-	if baseTime.Before(time.Now()) {
-		baseTime = time.Now()
-	}
-
 	highlights, err := p.videoHighlightDAL.ListByCategoryIDExcludingPublished(p.ctx, auto.CategoryID)
 	if err != nil {
 		fmt.Printf("Failed to load highlights for category %s for automation %s: %v\n", auto.CategoryID, automationID, err)
@@ -190,6 +176,30 @@ func (p *PublishManager) runAutomationDetection(automationID string) {
 	}
 }
 
+func publishInterval(account *schema.PublishAccount) time.Duration {
+	interval, err := time.ParseDuration(account.PublishInterval)
+	if err != nil {
+		log.Printf("Failed to parse publish interval for account %s: %v, defaulting to 1 hour\n", account.ID, err)
+		interval = time.Hour
+	}
+	return interval
+}
+
+// scheduleBase returns the time after which the next task of the account can
+// be scheduled: its latest scheduled task, or now.
+func (p *PublishManager) scheduleBase(accountID string) (time.Time, error) {
+	latestTask, err := p.publishTaskDAL.GetLatestScheduledTask(p.ctx, accountID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	baseTime := time.Now()
+	if latestTask != nil && time.UnixMilli(latestTask.ScheduledAt).After(baseTime) {
+		baseTime = time.UnixMilli(latestTask.ScheduledAt)
+	}
+	return baseTime, nil
+}
+
 func replaceTemplate(template string, highlight *schema.VideoHighlight) string {
 	s := template
 	s = strings.ReplaceAll(s, "{{title}}", highlight.Title)
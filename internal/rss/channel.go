@@ -0,0 +1,84 @@
+package rss
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"Kairo/internal/db/schema"
+	"Kairo/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// channelFetchLimit caps the uploads listed per refresh, channels can hold
+// thousands of videos and only the latest ones are new.
+const channelFetchLimit = 30
+
+// fetchChannel lists the latest uploads of a channel or uploader page
+// through yt-dlp and maps them onto feed items.
+func (m *Manager) fetchChannel(feedURL, feedID string) (*feedContent, error) {
+	if m.deps == nil {
+		return nil, fmt.Errorf("yt-dlp not available")
+	}
+	info, err := m.deps.GetChannelEntries(feedURL, channelFetchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	content := &feedContent{
+		Title:     info.Title,
+		Thumbnail: utils.EnsureHTTPS(info.Thumbnail),
+		Items:     make([]schema.FeedItem, 0, len(info.PlaylistItems)),
+	}
+	now := time.Now().Unix()
+	for i, entry := range info.PlaylistItems {
+		if entry.URL == "" {
+			continue
+		}
+		// Listings are newest first, keep that order for undated entries
+		pubDate := entry.PublishedAt
+		if pubDate == 0 {
+			pubDate = now - int64(i)
+		}
+		content.Items = append(content.Items, schema.FeedItem{
+			ID:          uuid.New().String(),
+			FeedID:      feedID,
+			Title:       entry.Title,
+			Link:        entry.URL,
+			Description: entry.Description,
+			PubDate:     pubDate,
+			Status:      schema.RSSItemStatusNew,
+			Thumbnail:   utils.EnsureHTTPS(entry.Thumbnail),
+			Duration:    entry.Duration,
+			CreatedAt:   pubDate,
+			UpdatedAt:   pubDate,
+		})
+	}
+	return content, nil
+}
+
+// channelURL points a YouTube channel without a tab at its videos tab, the
+// channel root lists the tabs instead of the uploads.
+func channelURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	if host != "youtube.com" && host != "m.youtube.com" {
+		return raw
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case len(parts) == 1 && strings.HasPrefix(parts[0], "@"):
+	case len(parts) == 2 && (parts[0] == "channel" || parts[0] == "c" || parts[0] == "user"):
+	default:
+		return raw
+	}
+	u.Path = "/" + strings.Join(append(parts, "videos"), "/")
+	return u.String()
+}
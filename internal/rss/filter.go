@@ -0,0 +1,263 @@
+package rss
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"Kairo/internal/db/schema"
+)
+
+// Filter is the parsed form of Feed.Filters. Rules are separated by commas
+// or newlines, commas inside a /regex/ belong to the regex:
+//
+//	tutorial            include items whose title or description contains "tutorial"
+//	-shorts, !trailer   exclude items containing the keyword
+//	title:live          restrict a keyword to the title (desc: for the description)
+//	/ep\.?\s*\d+/       case-insensitive regular expression, also usable with - and title:
+//	age<7d, age>2h      publish age bounds (units s, m, h, d, w; bare numbers are seconds)
+//	duration>60         duration bounds resolved through yt-dlp, e.g. duration<20m
+//
+// An item passes when it matches no exclude rule, at least one include rule
+// (if any exist) and every age and duration bound.
+type Filter struct {
+	includes    []filterTerm
+	excludes    []filterTerm
+	minAge      time.Duration
+	maxAge      time.Duration
+	minDuration time.Duration
+	maxDuration time.Duration
+}
+
+type filterTerm struct {
+	raw     string
+	scope   string
+	keyword string
+	re      *regexp.Regexp
+}
+
+var boundPattern = regexp.MustCompile(`^(age|duration)\s*(<=|>=|<|>)\s*(\d+(?:\.\d+)?)\s*([smhdw]?)$`)
+
+func ParseFilter(expr string) (*Filter, error) {
+	f := &Filter{}
+	for _, rule := range splitRules(expr) {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		if m := boundPattern.FindStringSubmatch(strings.ToLower(rule)); m != nil {
+			value, _ := strconv.ParseFloat(m[3], 64)
+			d := time.Duration(value * float64(unitDuration(m[4])))
+			isMin := strings.HasPrefix(m[2], ">")
+			switch {
+			case m[1] == "age" && isMin:
+				f.minAge = d
+			case m[1] == "age":
+				f.maxAge = d
+			case isMin:
+				f.minDuration = d
+			default:
+				f.maxDuration = d
+			}
+			continue
+		}
+
+		exclude := false
+		if strings.HasPrefix(rule, "-") || strings.HasPrefix(rule, "!") {
+			exclude = true
+			rule = strings.TrimSpace(rule[1:])
+		}
+
+		term := filterTerm{raw: rule}
+		lower := strings.ToLower(rule)
+		for _, scope := range []string{"title:", "desc:"} {
+			if strings.HasPrefix(lower, scope) {
+				term.scope = strings.TrimSuffix(scope, ":")
+				rule = strings.TrimSpace(rule[len(scope):])
+				break
+			}
+		}
+		if rule == "" {
+			return nil, fmt.Errorf("empty filter rule %q", term.raw)
+		}
+
+		if len(rule) >= 2 && strings.HasPrefix(rule, "/") && strings.HasSuffix(rule, "/") {
+			re, err := regexp.Compile("(?i)" + rule[1:len(rule)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter regex %q: %v", rule, err)
+			}
+			term.re = re
+		} else {
+			term.keyword = strings.ToLower(rule)
+		}
+
+		if exclude {
+			f.excludes = append(f.excludes, term)
+		} else {
+			f.includes = append(f.includes, term)
+		}
+	}
+
+	if f.minAge > 0 && f.maxAge > 0 && f.minAge > f.maxAge {
+		return nil, fmt.Errorf("filter age range is empty")
+	}
+	if f.minDuration > 0 && f.maxDuration > 0 && f.minDuration > f.maxDuration {
+		return nil, fmt.Errorf("filter duration range is empty")
+	}
+	return f, nil
+}
+
+// splitRules splits a filter expression on commas and newlines. A comma
+// inside a /regex/ such as /ep\d{1,3}/ does not end the rule.
+func splitRules(expr string) []string {
+	var rules []string
+	var b strings.Builder
+	inRegex, escaped := false, false
+	for _, r := range expr {
+		switch {
+		case r == '\n' || r == '\r':
+			inRegex, escaped = false, false
+			rules = append(rules, b.String())
+			b.Reset()
+			continue
+		case inRegex && escaped:
+			escaped = false
+		case inRegex && r == '\\':
+			escaped = true
+		case inRegex && r == '/':
+			inRegex = false
+		case r == '/' && isRulePrefix(b.String()):
+			inRegex = true
+		case !inRegex && r == ',':
+			rules = append(rules, b.String())
+			b.Reset()
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return append(rules, b.String())
+}
+
+// isRulePrefix reports whether a slash after prefix opens a regex.
+func isRulePrefix(prefix string) bool {
+	prefix = strings.TrimSpace(prefix)
+	prefix = strings.TrimSpace(strings.TrimLeft(prefix, "-!"))
+	lower := strings.ToLower(prefix)
+	return lower == "" || lower == "title:" || lower == "desc:"
+}
+
+func unitDuration(unit string) time.Duration {
+	switch unit {
+	case "m":
+		return time.Minute
+	case "h":
+		return time.Hour
+	case "d":
+		return 24 * time.Hour
+	case "w":
+		return 7 * 24 * time.Hour
+	default:
+		return time.Second
+	}
+}
+
+// NeedsDuration reports whether evaluating the filter requires the item
+// duration, which may cost a yt-dlp metadata lookup.
+func (f *Filter) NeedsDuration() bool {
+	return f.minDuration > 0 || f.maxDuration > 0
+}
+
+// Match evaluates the item and returns a short reason when it is rejected.
+// Item.Duration of 0 is treated as unknown.
+func (f *Filter) Match(item schema.FeedItem, now time.Time) (bool, string) {
+	if ok, reason := f.MatchRules(item, now); !ok {
+		return false, reason
+	}
+	if f.NeedsDuration() {
+		if item.Duration <= 0 {
+			return false, "duration unknown"
+		}
+		d := time.Duration(item.Duration * float64(time.Second))
+		if f.minDuration > 0 && d < f.minDuration {
+			return false, fmt.Sprintf("shorter than %s", f.minDuration)
+		}
+		if f.maxDuration > 0 && d > f.maxDuration {
+			return false, fmt.Sprintf("longer than %s", f.maxDuration)
+		}
+	}
+	return true, ""
+}
+
+// MatchRules evaluates the keyword and age rules only. They cost nothing,
+// so callers check them before looking up a missing duration.
+func (f *Filter) MatchRules(item schema.FeedItem, now time.Time) (bool, string) {
+	for _, t := range f.excludes {
+		if t.matches(item) {
+			return false, fmt.Sprintf("excluded by %q", t.raw)
+		}
+	}
+	if len(f.includes) > 0 {
+		matched := false
+		for _, t := range f.includes {
+			if t.matches(item) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, "no include rule matched"
+		}
+	}
+
+	age := now.Sub(time.Unix(item.PubDate, 0))
+	if f.minAge > 0 && age < f.minAge {
+		return false, fmt.Sprintf("published less than %s ago", f.minAge)
+	}
+	if f.maxAge > 0 && age > f.maxAge {
+		return false, fmt.Sprintf("published more than %s ago", f.maxAge)
+	}
+	return true, ""
+}
+
+func (t filterTerm) matches(item schema.FeedItem) bool {
+	var fields []string
+	switch t.scope {
+	case "title":
+		fields = []string{item.Title}
+	case "desc":
+		fields = []string{item.Description}
+	default:
+		fields = []string{item.Title, item.Description}
+	}
+	for _, field := range fields {
+		if t.re != nil {
+			if t.re.MatchString(field) {
+				return true
+			}
+		} else if strings.Contains(strings.ToLower(field), t.keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseITunesDuration accepts the "HH:MM:SS", "MM:SS" and plain seconds forms
+// used by <itunes:duration>.
+func parseITunesDuration(s string) float64 {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	total := 0.0
+	for _, part := range strings.Split(s, ":") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return 0
+		}
+		total = total*60 + v
+	}
+	return total
+}
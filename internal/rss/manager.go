@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"Kairo/internal/config"
 	"Kairo/internal/db/dal"
 	"Kairo/internal/db/schema"
+	"Kairo/internal/deps"
 	"Kairo/internal/utils"
 
 	"github.com/google/uuid"
@@ -18,28 +21,41 @@ import (
 	"gorm.io/gorm"
 )
 
+// lookupRetryAfter is how long a failed item lookup is not retried.
+const lookupRetryAfter = 24 * time.Hour
+
 type Manager struct {
 	ctx            context.Context
 	db             *gorm.DB
+	deps           *deps.Manager
 	rssDAL         *dal.RSSDAL
+	categoryDAL    *dal.CategoryDAL
 	mu             sync.Mutex
+	refreshing     map[string]struct{}      // Feeds picked up by the scheduler
+	hostSlots      map[string]chan struct{} // Per host refresh limits
+	workers        chan struct{}            // Bounds the refreshes running at once
+	lookupMisses   map[string]time.Time     // Items whose yt-dlp lookup failed
 	OnAutoDownload func(item schema.FeedItem, feed schema.Feed)
 }
 
-func NewManager(ctx context.Context, db *gorm.DB) *Manager {
+func NewManager(ctx context.Context, db *gorm.DB, d *deps.Manager) *Manager {
 	m := &Manager{
-		ctx: ctx,
+		ctx:          ctx,
+		deps:         d,
+		refreshing:   make(map[string]struct{}),
+		hostSlots:    make(map[string]chan struct{}),
+		workers:      make(chan struct{}, feedRefreshWorkers),
+		lookupMisses: make(map[string]time.Time),
 	}
 	m.db = db
 	if db != nil {
 		m.rssDAL = dal.NewRSSDAL(db)
+		m.categoryDAL = dal.NewCategoryDAL(db)
 	}
 	return m
 }
 
-func (m *Manager) getParser() *gofeed.Parser {
-	fp := gofeed.NewParser()
-	fp.UserAgent = "Kairo/1.0"
+func (m *Manager) getHTTPClient() *http.Client {
 	// Set client with proxy if needed
 	client := &http.Client{
 		Timeout: 30 * time.Second,
@@ -51,44 +67,164 @@ func (m *Manager) getParser() *gofeed.Parser {
 			}
 		}
 	}
-	fp.Client = client
-	return fp
+	return client
 }
 
 func (m *Manager) AddFeed(input schema.AddRSSFeedInput) (*schema.Feed, error) {
+	feed, err := m.createFeed(input, true)
+	if err != nil {
+		return nil, err
+	}
+	go m.processAutoDownload(feed.ID)
+	return feed, nil
+}
+
+// createFeed fetches a feed and stores it with its items, without starting
+// any download.
+func (m *Manager) createFeed(input schema.AddRSSFeedInput, enabled bool) (*schema.Feed, error) {
 	if m.db == nil || m.rssDAL == nil {
 		return nil, fmt.Errorf("database not initialized")
 	}
-	fp := m.getParser()
-	feed, err := fp.ParseURL(input.URL)
-	if err != nil {
+	if _, err := ParseFilter(input.Filters); err != nil {
+		return nil, err
+	}
+	if err := utils.ValidateOutputTemplate(input.FilenameTemplate); err != nil {
 		return nil, err
 	}
+	if err := validateSchedule(input.RefreshInterval, input.RefreshCron); err != nil {
+		return nil, err
+	}
+	feedURL := input.URL
+	if input.SourceType == schema.FeedSourceChannel {
+		feedURL = channelURL(feedURL)
+	}
 
-	now := time.Now().Unix()
 	rssFeed := &schema.Feed{
 		ID:               uuid.New().String(),
-		URL:              input.URL,
-		Title:            feed.Title,
-		Description:      feed.Description,
-		LastUpdated:      now,
-		UnreadCount:      len(feed.Items),
+		URL:              feedURL,
+		SourceType:       input.SourceType,
+		Podcast:          input.Podcast,
 		CustomDir:        input.CustomDir,
 		DownloadLatest:   input.DownloadLatest,
 		Filters:          input.Filters,
 		Tags:             input.Tags,
 		FilenameTemplate: input.FilenameTemplate,
 		CategoryID:       input.CategoryID,
-		Enabled:          true,
-		CreatedAt:        now,
-		UpdatedAt:        now,
+		RefreshInterval:  input.RefreshInterval,
+		RefreshCron:      strings.TrimSpace(input.RefreshCron),
+		RetentionItems:   input.RetentionItems,
+		RetentionDays:    input.RetentionDays,
+		Enabled:          enabled,
+	}
+	content, err := m.fetchFeed(rssFeed)
+	if err != nil {
+		return nil, err
 	}
 
+	now := time.Now()
+	rssFeed.Title = content.Title
+	rssFeed.Description = content.Description
+	rssFeed.Thumbnail = content.Thumbnail
+	rssFeed.UnreadCount = len(content.Items)
+	rssFeed.ETag = content.ETag
+	rssFeed.LastModified = content.LastModified
+	rssFeed.LastUpdated = now.Unix()
+	rssFeed.LastSuccessAt = now.Unix()
+	rssFeed.NextRefreshAt = nextRefresh(rssFeed, now).Unix()
+	rssFeed.CreatedAt = now.Unix()
+	rssFeed.UpdatedAt = now.Unix()
+
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		txDal := dal.NewRSSDAL(tx)
+		if err := txDal.CreateFeed(m.ctx, rssFeed); err != nil {
+			return err
+		}
+		return txDal.CreateFeedItems(m.ctx, content.Items)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rssFeed, nil
+}
+
+// feedContent is the feed metadata and the entries of one fetch, whatever
+// the source type. NotModified is set when the server answered a
+// conditional request with 304, nothing else is filled then.
+type feedContent struct {
+	Title        string
+	Description  string
+	Thumbnail    string
+	Items        []schema.FeedItem
+	ETag         string
+	LastModified string
+	NotModified  bool
+}
+
+func (m *Manager) fetchFeed(feed *schema.Feed) (*feedContent, error) {
+	switch feed.SourceType {
+	case schema.FeedSourceRSS:
+		return m.fetchRSS(feed)
+	case schema.FeedSourceChannel:
+		return m.fetchChannel(feed.URL, feed.ID)
+	default:
+		return nil, fmt.Errorf("unknown feed source type %d", feed.SourceType)
+	}
+}
+
+// fetchRSS downloads and parses the feed document, sending the validators
+// of the last fetch so unchanged feeds cost a 304 only.
+func (m *Manager) fetchRSS(rssFeed *schema.Feed) (*feedContent, error) {
+	req, err := http.NewRequestWithContext(m.ctx, http.MethodGet, rssFeed.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Kairo/1.0")
+	if rssFeed.ETag != "" {
+		req.Header.Set("If-None-Match", rssFeed.ETag)
+	}
+	if rssFeed.LastModified != "" {
+		req.Header.Set("If-Modified-Since", rssFeed.LastModified)
+	}
+	resp, err := m.getHTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return &feedContent{
+			ETag:         rssFeed.ETag,
+			LastModified: rssFeed.LastModified,
+			NotModified:  true,
+		}, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("http error: %s", resp.Status)
+	}
+	feed, err := gofeed.NewParser().Parse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	feedID := rssFeed.ID
+	content := &feedContent{
+		Title:        feed.Title,
+		Description:  feed.Description,
+		Items:        make([]schema.FeedItem, 0, len(feed.Items)),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
 	if feed.Image != nil {
-		rssFeed.Thumbnail = utils.EnsureHTTPS(feed.Image.URL)
+		content.Thumbnail = utils.EnsureHTTPS(feed.Image.URL)
+	} else if feed.ITunesExt != nil && feed.ITunesExt.Image != "" {
+		content.Thumbnail = utils.EnsureHTTPS(feed.ITunesExt.Image)
 	}
-	feedItems := make([]schema.FeedItem, 0, len(feed.Items))
 	for _, item := range feed.Items {
+		enclosure := mediaEnclosure(item)
+		// Podcast episodes often have no page of their own
+		if item.Link == "" && enclosure != nil {
+			item.Link = enclosure.URL
+		}
 		// Filter out items without title or link
 		if item.Title == "" || item.Link == "" {
 			continue
@@ -98,43 +234,43 @@ func (m *Manager) AddFeed(input schema.AddRSSFeedInput) (*schema.Feed, error) {
 		if item.PublishedParsed != nil {
 			pubDate = item.PublishedParsed.Unix()
 		}
-		itemID := uuid.New().String()
 
-		thumbnail := ""
+		var thumbnail string
 		if len(item.Enclosures) > 0 && (item.Enclosures[0].Type == "image/jpeg" || item.Enclosures[0].Type == "image/png") {
 			thumbnail = utils.EnsureHTTPS(item.Enclosures[0].URL)
 		} else if item.Image != nil {
 			thumbnail = utils.EnsureHTTPS(item.Image.URL)
+		} else if item.ITunesExt != nil && item.ITunesExt.Image != "" {
+			thumbnail = utils.EnsureHTTPS(item.ITunesExt.Image)
 		}
 
-		feedItems = append(feedItems, schema.FeedItem{
-			ID:          itemID,
-			FeedID:      rssFeed.ID,
+		var duration float64
+		if item.ITunesExt != nil {
+			duration = parseITunesDuration(item.ITunesExt.Duration)
+		}
+
+		feedItem := schema.FeedItem{
+			ID:          uuid.New().String(),
+			FeedID:      feedID,
 			Title:       item.Title,
 			Link:        item.Link,
 			Description: item.Description,
 			PubDate:     pubDate,
 			Status:      schema.RSSItemStatusNew,
 			Thumbnail:   thumbnail,
+			Duration:    duration,
+			Author:      itemAuthor(item),
 			CreatedAt:   pubDate,
 			UpdatedAt:   pubDate,
-		})
-	}
-
-	err = m.db.Transaction(func(tx *gorm.DB) error {
-		txDal := dal.NewRSSDAL(tx)
-		if err := txDal.CreateFeed(m.ctx, rssFeed); err != nil {
-			return err
 		}
-		return txDal.CreateFeedItems(m.ctx, feedItems)
-	})
-	if err != nil {
-		return nil, err
+		if enclosure != nil {
+			feedItem.EnclosureURL = enclosure.URL
+			feedItem.EnclosureType = enclosure.Type
+			feedItem.EnclosureLength, _ = strconv.ParseInt(enclosure.Length, 10, 64)
+		}
+		content.Items = append(content.Items, feedItem)
 	}
-
-	go m.processAutoDownload(rssFeed.ID)
-
-	return rssFeed, nil
+	return content, nil
 }
 
 func (m *Manager) GetFeeds() ([]schema.Feed, error) {
@@ -180,15 +316,40 @@ func (m *Manager) UpdateFeed(feed schema.Feed) error {
 	if m.rssDAL == nil {
 		return fmt.Errorf("database not initialized")
 	}
-	return m.rssDAL.UpdateFeed(m.ctx, feed.ID, map[string]interface{}{
+	if _, err := ParseFilter(feed.Filters); err != nil {
+		return err
+	}
+	if err := utils.ValidateOutputTemplate(feed.FilenameTemplate); err != nil {
+		return err
+	}
+	if err := validateSchedule(feed.RefreshInterval, feed.RefreshCron); err != nil {
+		return err
+	}
+	current, err := m.rssDAL.GetFeedByID(m.ctx, feed.ID)
+	if err != nil {
+		return err
+	}
+	feed.RefreshCron = strings.TrimSpace(feed.RefreshCron)
+	updates := map[string]interface{}{
+		"podcast":           feed.Podcast,
 		"custom_dir":        feed.CustomDir,
 		"download_latest":   feed.DownloadLatest,
 		"filters":           feed.Filters,
 		"tags":              feed.Tags,
 		"filename_template": feed.FilenameTemplate,
 		"category_id":       feed.CategoryID,
+		"refresh_interval":  feed.RefreshInterval,
+		"refresh_cron":      feed.RefreshCron,
+		"retention_items":   feed.RetentionItems,
+		"retention_days":    feed.RetentionDays,
 		"updated_at":        time.Now().Unix(),
-	})
+	}
+	// Reschedule healthy feeds right away, broken ones keep their backoff
+	if current.FailureCount == 0 &&
+		(current.RefreshInterval != feed.RefreshInterval || current.RefreshCron != feed.RefreshCron) {
+		updates["next_refresh_at"] = nextRefresh(&feed, time.Now()).Unix()
+	}
+	return m.rssDAL.UpdateFeed(m.ctx, feed.ID, updates)
 }
 
 func (m *Manager) updateUnreadCount(feedID string) error {
@@ -254,69 +415,56 @@ func (m *Manager) SetItemFailedByLink(link string) error {
 	return nil
 }
 
+// RefreshFeed fetches a feed now and records the outcome in its health
+// fields, which also decides when the scheduler refreshes it next.
 func (m *Manager) RefreshFeed(feedID string) error {
 	if m.db == nil || m.rssDAL == nil {
 		return fmt.Errorf("database not initialized")
 	}
-	url, err := m.rssDAL.GetFeedURLByID(m.ctx, feedID)
+	feed, err := m.rssDAL.GetFeedByID(m.ctx, feedID)
 	if err != nil {
 		return err
 	}
 
-	fp := m.getParser()
-	feed, err := fp.ParseURL(url)
+	content, err := m.fetchFeed(feed)
 	if err != nil {
+		m.recordFailure(feed, err)
 		return err
 	}
 
-	now := time.Now().Unix()
-	newItems := make([]schema.FeedItem, 0, len(feed.Items))
-	for _, item := range feed.Items {
-		// Filter out items without title or link
-		if item.Title == "" || item.Link == "" {
-			continue
-		}
-
-		pubDate := time.Now().Unix()
-		if item.PublishedParsed != nil {
-			pubDate = item.PublishedParsed.Unix()
-		}
-		itemID := uuid.New().String()
-
-		var thumbnail string
-		if len(item.Enclosures) > 0 && (item.Enclosures[0].Type == "image/jpeg" || item.Enclosures[0].Type == "image/png") {
-			thumbnail = utils.EnsureHTTPS(item.Enclosures[0].URL)
-		} else if item.Image != nil {
-			thumbnail = utils.EnsureHTTPS(item.Image.URL)
+	now := time.Now()
+	updates := map[string]interface{}{
+		"etag":            content.ETag,
+		"last_modified":   content.LastModified,
+		"last_updated":    now.Unix(),
+		"last_success_at": now.Unix(),
+		"last_error":      "",
+		"failure_count":   0,
+		"next_refresh_at": nextRefresh(feed, now).Unix(),
+		"updated_at":      now.Unix(),
+	}
+	if content.NotModified {
+		// The feed still lists the pruned links, keep their tombstones
+		if err := m.rssDAL.TouchTombstones(m.ctx, feedID, nil, now.Unix()); err != nil {
+			return err
 		}
-		newItems = append(newItems, schema.FeedItem{
-			ID:          itemID,
-			FeedID:      feedID,
-			Title:       item.Title,
-			Link:        item.Link,
-			Description: item.Description,
-			PubDate:     pubDate,
-			Status:      schema.RSSItemStatusNew,
-			Thumbnail:   thumbnail,
-			CreatedAt:   pubDate,
-			UpdatedAt:   pubDate,
-		})
+		return m.rssDAL.UpdateFeed(m.ctx, feedID, updates)
 	}
-	updates := map[string]interface{}{
-		"title":        feed.Title,
-		"description":  feed.Description,
-		"last_updated": now,
-		"updated_at":   now,
+	if content.Items, err = m.dropTombstoned(feedID, content.Items); err != nil {
+		return err
 	}
-	if feed.Image != nil {
-		updates["thumbnail"] = utils.EnsureHTTPS(feed.Image.URL)
+
+	updates["title"] = content.Title
+	updates["description"] = content.Description
+	if content.Thumbnail != "" {
+		updates["thumbnail"] = content.Thumbnail
 	}
 	err = m.db.Transaction(func(tx *gorm.DB) error {
 		txDal := dal.NewRSSDAL(tx)
 		if err := txDal.UpdateFeed(m.ctx, feedID, updates); err != nil {
 			return err
 		}
-		if err := txDal.CreateFeedItems(m.ctx, newItems); err != nil {
+		if err := txDal.CreateFeedItems(m.ctx, content.Items); err != nil {
 			return err
 		}
 		count, err := txDal.CountUnread(m.ctx, feedID, int(schema.RSSItemStatusNew))
@@ -337,40 +485,6 @@ func (m *Manager) RefreshFeed(feedID string) error {
 	return nil
 }
 
-func (m *Manager) Start() {
-	go func() {
-		// Initial check after 1 minute (give app time to start up)
-		time.Sleep(1 * time.Minute)
-		m.checkFeeds()
-
-		for {
-			interval := config.GetSettings().RSSCheckInterval
-			if interval <= 0 {
-				interval = 60
-			}
-			time.Sleep(time.Duration(interval) * time.Minute)
-			m.checkFeeds()
-		}
-	}()
-}
-
-func (m *Manager) checkFeeds() {
-	feeds, err := m.GetFeeds()
-	if err != nil {
-		fmt.Printf("Failed to get feeds for auto-refresh: %v\n", err)
-		return
-	}
-
-	for _, feed := range feeds {
-		if feed.Enabled {
-			// Refresh sequentially to avoid network spikes
-			if err := m.RefreshFeed(feed.ID); err != nil {
-				fmt.Printf("Failed to auto-refresh feed %s: %v\n", feed.Title, err)
-			}
-		}
-	}
-}
-
 func (m *Manager) MarkItemRead(itemID string) error {
 	if m.rssDAL == nil {
 		return fmt.Errorf("database not initialized")
@@ -397,17 +511,127 @@ func (m *Manager) processAutoDownload(feedID string) {
 		return
 	}
 
+	if feed.SourceType == schema.FeedSourceChannel {
+		m.resolveChannelTitles(feedID)
+	}
 	if !feed.DownloadLatest {
 		return
 	}
 
+	filter, err := ParseFilter(feed.Filters)
+	if err != nil {
+		fmt.Printf("Skip auto download for feed %s, invalid filters: %v\n", feed.Title, err)
+		return
+	}
+
 	items, err := m.rssDAL.ListFeedItemsByStatuses(m.ctx, feedID, []int{int(schema.RSSItemStatusNew), int(schema.RSSItemStatusRead)})
 	if err != nil {
 		return
 	}
+	now := time.Now()
 	for _, item := range items {
+		if ok, _ := filter.MatchRules(item, now); !ok {
+			continue
+		}
+		if filter.NeedsDuration() {
+			m.resolveItemDetails(&item, true)
+		}
+		if ok, _ := filter.Match(item, now); !ok {
+			continue
+		}
 		m.OnAutoDownload(item, *feed)
 		_ = m.rssDAL.UpdateFeedItemStatusByID(m.ctx, item.ID, int(schema.RSSItemStatusQueued))
 	}
 	_ = m.updateUnreadCount(feedID)
 }
+
+// PreviewFilters evaluates the given filter expression against the stored
+// items of a feed without changing anything but cached durations.
+func (m *Manager) PreviewFilters(feedID string, filters string) ([]schema.RSSFilterPreview, error) {
+	if m.rssDAL == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	filter, err := ParseFilter(filters)
+	if err != nil {
+		return nil, err
+	}
+	items, err := m.rssDAL.ListFeedItems(m.ctx, feedID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]schema.RSSFilterPreview, 0, len(items))
+	for _, item := range items {
+		if ok, _ := filter.MatchRules(item, now); ok && filter.NeedsDuration() {
+			m.resolveItemDetails(&item, true)
+		}
+		matched, reason := filter.Match(item, now)
+		result = append(result, schema.RSSFilterPreview{
+			Item:    item,
+			Matched: matched,
+			Reason:  reason,
+		})
+	}
+	return result, nil
+}
+
+// titleMissing reports whether an item only carries its link as title, flat
+// channel listings leave titles out on some sites.
+func titleMissing(item *schema.FeedItem) bool {
+	return item.Title == "" || item.Title == item.Link
+}
+
+// resolveChannelTitles looks up the missing titles of unhandled channel items.
+func (m *Manager) resolveChannelTitles(feedID string) {
+	items, err := m.rssDAL.ListFeedItemsByStatuses(m.ctx, feedID, []int{int(schema.RSSItemStatusNew), int(schema.RSSItemStatusRead)})
+	if err != nil {
+		return
+	}
+	for i := range items {
+		if titleMissing(&items[i]) {
+			m.resolveItemDetails(&items[i], false)
+		}
+	}
+}
+
+// resolveItemDetails looks up a missing title and, with needDuration, a
+// missing duration through yt-dlp and caches them on the item row. A failed
+// lookup is not repeated for a while.
+func (m *Manager) resolveItemDetails(item *schema.FeedItem, needDuration bool) {
+	if m.deps == nil || (!titleMissing(item) && (!needDuration || item.Duration > 0)) {
+		return
+	}
+	m.mu.Lock()
+	failedAt, failed := m.lookupMisses[item.ID]
+	m.mu.Unlock()
+	if failed && time.Since(failedAt) < lookupRetryAfter {
+		return
+	}
+	info, err := m.deps.GetVideoDetails(item.Link)
+	if err != nil || (needDuration && info.Duration <= 0) {
+		fmt.Printf("Failed to resolve details for %s: %v\n", item.Link, err)
+		m.mu.Lock()
+		m.lookupMisses[item.ID] = time.Now()
+		m.mu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+	updates := map[string]interface{}{}
+	if titleMissing(item) && info.Title != "" {
+		item.Title = info.Title
+		updates["title"] = info.Title
+	}
+	if item.Duration <= 0 && info.Duration > 0 {
+		item.Duration = info.Duration
+		updates["duration"] = info.Duration
+	}
+	if item.Thumbnail == "" && info.Thumbnail != "" {
+		item.Thumbnail = utils.EnsureHTTPS(info.Thumbnail)
+		updates["thumbnail"] = item.Thumbnail
+	}
+	if len(updates) > 0 {
+		_ = m.rssDAL.UpdateFeedItem(m.ctx, item.ID, updates)
+	}
+}
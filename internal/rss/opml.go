@@ -0,0 +1,365 @@
+package rss
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"Kairo/internal/db/schema"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// kairoNamespace holds the feed settings other readers do not know about.
+const kairoNamespace = "urn:kairo:opml"
+
+// opmlImportWorkers bounds the feeds fetched at once while importing.
+const opmlImportWorkers = 4
+
+type opmlDocument struct {
+	XMLName xml.Name   `xml:"opml"`
+	Version string     `xml:"version,attr"`
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Head    opmlHead   `xml:"head"`
+	Body    opmlBody   `xml:"body"`
+}
+
+type opmlHead struct {
+	Title       string `xml:"title"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type opmlBody struct {
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Attrs    []xml.Attr    `xml:",any,attr"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// kairoAttr looks up a Kairo setting, files written without the namespace
+// declaration keep the bare prefix as namespace.
+func (o *opmlOutline) kairoAttr(name string) (string, bool) {
+	for _, attr := range o.Attrs {
+		if attr.Name.Local == name && (attr.Name.Space == kairoNamespace || attr.Name.Space == "kairo") {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+func (o *opmlOutline) setKairoAttr(name, value string) {
+	o.Attrs = append(o.Attrs, xml.Attr{Name: xml.Name{Local: "kairo:" + name}, Value: value})
+}
+
+// opmlFeed is a feed outline together with the folders it was found in.
+type opmlFeed struct {
+	outline opmlOutline
+	folders []string
+}
+
+func collectOPMLFeeds(outlines []opmlOutline, folders []string, feeds []opmlFeed) []opmlFeed {
+	for _, o := range outlines {
+		if strings.TrimSpace(o.XMLURL) != "" {
+			feeds = append(feeds, opmlFeed{outline: o, folders: folders})
+			continue
+		}
+		name := strings.TrimSpace(o.Text)
+		if name == "" {
+			name = strings.TrimSpace(o.Title)
+		}
+		inner := folders
+		if name != "" {
+			inner = append(append([]string{}, folders...), name)
+		}
+		feeds = collectOPMLFeeds(o.Outlines, inner, feeds)
+	}
+	return feeds
+}
+
+// ImportOPML subscribes to every feed of an OPML file that is not
+// subscribed yet, without auto downloading during the import. Failing feeds
+// are reported and do not stop the import.
+func (m *Manager) ImportOPML(data []byte, mode schema.OPMLFolderMode) (*schema.OPMLImportResult, error) {
+	if m.rssDAL == nil || m.categoryDAL == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	if mode != schema.OPMLFolderCategory && mode != schema.OPMLFolderTag {
+		return nil, fmt.Errorf("unknown folder mode %q", mode)
+	}
+	var doc opmlDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid OPML: %w", err)
+	}
+
+	existing, err := m.rssDAL.ListFeeds(m.ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]struct{}, len(existing))
+	for _, feed := range existing {
+		seen[feed.URL] = struct{}{}
+	}
+
+	result := &schema.OPMLImportResult{Failed: []schema.OPMLImportFailure{}}
+	var inputs []schema.AddRSSFeedInput
+	var titles []string
+	var enabled []bool
+	for _, feed := range collectOPMLFeeds(doc.Body.Outlines, nil, nil) {
+		input, on, err := m.opmlFeedInput(feed, mode)
+		title := feed.outline.Title
+		if title == "" {
+			title = feed.outline.Text
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, schema.OPMLImportFailure{
+				URL:   feed.outline.XMLURL,
+				Title: title,
+				Error: err.Error(),
+			})
+			continue
+		}
+		if _, ok := seen[input.URL]; ok {
+			result.Skipped++
+			continue
+		}
+		seen[input.URL] = struct{}{}
+		inputs = append(inputs, input)
+		titles = append(titles, title)
+		enabled = append(enabled, on)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opmlImportWorkers)
+	for i, input := range inputs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			// Imported feeds only auto download after a later refresh
+			// instead of all at once
+			_, err := m.createFeed(input, enabled[i])
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Failed = append(result.Failed, schema.OPMLImportFailure{
+					URL:   input.URL,
+					Title: titles[i],
+					Error: err.Error(),
+				})
+				return
+			}
+			result.Added++
+		}()
+	}
+	wg.Wait()
+
+	return result, nil
+}
+
+// opmlFeedInput maps a feed outline onto the input of AddFeed and reports
+// whether the feed should stay enabled.
+func (m *Manager) opmlFeedInput(feed opmlFeed, mode schema.OPMLFolderMode) (schema.AddRSSFeedInput, bool, error) {
+	o := feed.outline
+	input := schema.AddRSSFeedInput{URL: strings.TrimSpace(o.XMLURL)}
+	if v, ok := o.kairoAttr("sourceType"); ok && v == "channel" {
+		input.SourceType = schema.FeedSourceChannel
+		input.URL = channelURL(input.URL)
+	}
+	if v, ok := o.kairoAttr("podcast"); ok {
+		input.Podcast, _ = strconv.ParseBool(v)
+	}
+	input.CustomDir, _ = o.kairoAttr("customDir")
+	input.Filters, _ = o.kairoAttr("filters")
+	input.Tags, _ = o.kairoAttr("tags")
+	input.FilenameTemplate, _ = o.kairoAttr("filenameTemplate")
+	input.RefreshCron, _ = o.kairoAttr("refreshCron")
+	if v, ok := o.kairoAttr("downloadLatest"); ok {
+		input.DownloadLatest, _ = strconv.ParseBool(v)
+	}
+	if v, ok := o.kairoAttr("refreshInterval"); ok {
+		input.RefreshInterval, _ = strconv.Atoi(v)
+	}
+	if v, ok := o.kairoAttr("retentionItems"); ok {
+		input.RetentionItems, _ = strconv.Atoi(v)
+	}
+	if v, ok := o.kairoAttr("retentionDays"); ok {
+		input.RetentionDays, _ = strconv.Atoi(v)
+	}
+	enabled := true
+	if v, ok := o.kairoAttr("enabled"); ok {
+		if b, err := strconv.ParseBool(v); err == nil {
+			enabled = b
+		}
+	}
+
+	categoryName, _ := o.kairoAttr("category")
+	switch mode {
+	case schema.OPMLFolderCategory:
+		if categoryName == "" && len(feed.folders) > 0 {
+			categoryName = feed.folders[len(feed.folders)-1]
+		}
+	case schema.OPMLFolderTag:
+		input.Tags = mergeTags(input.Tags, feed.folders)
+	}
+	if categoryName != "" {
+		id, err := m.ensureCategory(categoryName)
+		if err != nil {
+			return input, enabled, err
+		}
+		input.CategoryID = id
+	}
+	return input, enabled, nil
+}
+
+// ensureCategory returns the category with the given name, creating it
+// when missing.
+func (m *Manager) ensureCategory(name string) (string, error) {
+	category, err := m.categoryDAL.GetByName(m.ctx, name)
+	if err == nil {
+		return category.ID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	now := time.Now().Unix()
+	category = &schema.Category{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Source:    schema.CategorySourceCustom,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := m.categoryDAL.Create(m.ctx, category); err != nil {
+		return "", err
+	}
+	return category.ID, nil
+}
+
+func mergeTags(tags string, extra []string) string {
+	var merged []string
+	seen := make(map[string]struct{})
+	for _, tag := range append(strings.Split(tags, ","), extra...) {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		merged = append(merged, tag)
+	}
+	return strings.Join(merged, ", ")
+}
+
+// ExportOPML writes every subscription as OPML, grouped in one folder per
+// category. Kairo settings are kept in namespaced attributes so importing
+// the file again restores them.
+func (m *Manager) ExportOPML() ([]byte, error) {
+	if m.rssDAL == nil || m.categoryDAL == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	feeds, err := m.rssDAL.ListFeeds(m.ctx)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := m.categoryDAL.List(m.ctx)
+	if err != nil {
+		return nil, err
+	}
+	categoryNames := make(map[string]string, len(categories))
+	for _, c := range categories {
+		categoryNames[c.ID] = c.Name
+	}
+
+	doc := opmlDocument{
+		Version: "2.0",
+		Attrs:   []xml.Attr{{Name: xml.Name{Local: "xmlns:kairo"}, Value: kairoNamespace}},
+		Head: opmlHead{
+			Title:       "Kairo subscriptions",
+			DateCreated: time.Now().Format(time.RFC1123Z),
+		},
+	}
+	folders := make(map[string]*opmlOutline)
+	var folderNames []string
+	for _, feed := range feeds {
+		categoryName := categoryNames[feed.CategoryID]
+		outline := feedOutline(feed, categoryName)
+		if categoryName == "" {
+			doc.Body.Outlines = append(doc.Body.Outlines, outline)
+			continue
+		}
+		folder, ok := folders[categoryName]
+		if !ok {
+			folder = &opmlOutline{Text: categoryName, Title: categoryName}
+			folders[categoryName] = folder
+			folderNames = append(folderNames, categoryName)
+		}
+		folder.Outlines = append(folder.Outlines, outline)
+	}
+	sort.Strings(folderNames)
+	for _, name := range folderNames {
+		doc.Body.Outlines = append(doc.Body.Outlines, *folders[name])
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+func feedOutline(feed schema.Feed, categoryName string) opmlOutline {
+	o := opmlOutline{
+		Text:   feed.Title,
+		Title:  feed.Title,
+		Type:   "rss",
+		XMLURL: feed.URL,
+	}
+	if feed.SourceType == schema.FeedSourceChannel {
+		o.setKairoAttr("sourceType", "channel")
+	}
+	if feed.Podcast {
+		o.setKairoAttr("podcast", "true")
+	}
+	o.setKairoAttr("enabled", strconv.FormatBool(feed.Enabled))
+	o.setKairoAttr("downloadLatest", strconv.FormatBool(feed.DownloadLatest))
+	if feed.RefreshInterval > 0 {
+		o.setKairoAttr("refreshInterval", strconv.Itoa(feed.RefreshInterval))
+	}
+	if feed.RetentionItems != 0 {
+		o.setKairoAttr("retentionItems", strconv.Itoa(feed.RetentionItems))
+	}
+	if feed.RetentionDays != 0 {
+		o.setKairoAttr("retentionDays", strconv.Itoa(feed.RetentionDays))
+	}
+	for _, attr := range []struct{ name, value string }{
+		{"customDir", feed.CustomDir},
+		{"filters", feed.Filters},
+		{"tags", feed.Tags},
+		{"filenameTemplate", feed.FilenameTemplate},
+		{"refreshCron", feed.RefreshCron},
+		{"category", categoryName},
+	} {
+		if attr.value != "" {
+			o.setKairoAttr(attr.name, attr.value)
+		}
+	}
+	return o
+}
@@ -0,0 +1,47 @@
+package rss
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// mediaExtensions identify audio and video enclosures published without a
+// MIME type.
+var mediaExtensions = map[string]struct{}{
+	".mp3": {}, ".m4a": {}, ".aac": {}, ".ogg": {}, ".oga": {}, ".opus": {},
+	".flac": {}, ".wav": {}, ".mp4": {}, ".m4v": {}, ".mov": {}, ".webm": {},
+}
+
+// mediaEnclosure returns the first audio or video enclosure of an item.
+func mediaEnclosure(item *gofeed.Item) *gofeed.Enclosure {
+	for _, enclosure := range item.Enclosures {
+		if enclosure == nil || enclosure.URL == "" {
+			continue
+		}
+		mimeType := strings.ToLower(enclosure.Type)
+		if strings.HasPrefix(mimeType, "audio/") || strings.HasPrefix(mimeType, "video/") {
+			return enclosure
+		}
+		if mimeType == "" {
+			if u, err := url.Parse(enclosure.URL); err == nil {
+				if _, ok := mediaExtensions[strings.ToLower(path.Ext(u.Path))]; ok {
+					return enclosure
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func itemAuthor(item *gofeed.Item) string {
+	if item.ITunesExt != nil && item.ITunesExt.Author != "" {
+		return item.ITunesExt.Author
+	}
+	if item.Author != nil {
+		return item.Author.Name
+	}
+	return ""
+}
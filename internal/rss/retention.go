@@ -0,0 +1,146 @@
+package rss
+
+import (
+	"fmt"
+	"time"
+
+	"Kairo/internal/config"
+	"Kairo/internal/db/schema"
+)
+
+const (
+	retentionStartDelay = 5 * time.Minute
+	tombstoneTTL        = 90 * 24 * time.Hour // Tombstones unlisted for this long are dropped
+)
+
+// startCleanup prunes feed items on the retention check interval until the
+// app shuts down.
+func (m *Manager) startCleanup() {
+	go func() {
+		timer := time.NewTimer(retentionStartDelay)
+		defer timer.Stop()
+		for {
+			select {
+			case <-m.ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			retention := config.GetRSSRetention()
+			if retention.Enabled {
+				if result, err := m.PruneItems(); err != nil {
+					fmt.Printf("Failed to prune feed items: %v\n", err)
+				} else if result.Items > 0 || result.Tombstones > 0 {
+					fmt.Printf("Pruned %d items from %d feeds, dropped %d tombstones\n", result.Items, result.Feeds, result.Tombstones)
+				}
+			}
+			interval := retention.CheckInterval
+			if interval <= 0 {
+				interval = 24
+			}
+			timer.Reset(time.Duration(interval) * time.Hour)
+		}
+	}()
+}
+
+// PruneItems applies the retention limits to every feed. Pruned links are
+// tombstoned so later refreshes skip them.
+func (m *Manager) PruneItems() (*schema.RSSPruneResult, error) {
+	if m.rssDAL == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	feeds, err := m.rssDAL.ListFeeds(m.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	retention := config.GetRSSRetention()
+	now := time.Now()
+	result := &schema.RSSPruneResult{}
+	for _, feed := range feeds {
+		maxItems := retentionLimit(feed.RetentionItems, retention.MaxItems)
+		readDays := retentionLimit(feed.RetentionDays, retention.ReadDays)
+		if maxItems == 0 && readDays == 0 {
+			continue
+		}
+		items, err := m.rssDAL.ListFeedItems(m.ctx, feed.ID)
+		if err != nil {
+			return result, err
+		}
+		pruned := prunableItems(items, maxItems, readDays, now)
+		if len(pruned) == 0 {
+			continue
+		}
+		if err := m.rssDAL.PruneFeedItems(m.ctx, feed.ID, pruned, now.Unix()); err != nil {
+			return result, err
+		}
+		_ = m.updateUnreadCount(feed.ID)
+		result.Feeds++
+		result.Items += int64(len(pruned))
+	}
+
+	result.Tombstones, err = m.rssDAL.DeleteStaleTombstones(m.ctx, now.Add(-tombstoneTTL).Unix())
+	return result, err
+}
+
+// retentionLimit resolves a per feed limit, 0 falls back to the global one
+// and a negative value disables it.
+func retentionLimit(feedLimit, globalLimit int) int {
+	if feedLimit < 0 {
+		return 0
+	}
+	if feedLimit > 0 {
+		return feedLimit
+	}
+	return max(globalLimit, 0)
+}
+
+// prunableItems picks the items beyond the newest maxItems and the read
+// items older than readDays. items must be sorted newest first, downloaded
+// and queued items are always kept.
+func prunableItems(items []schema.FeedItem, maxItems, readDays int, now time.Time) []schema.FeedItem {
+	cutoff := now.AddDate(0, 0, -readDays).Unix()
+	var pruned []schema.FeedItem
+	for i, item := range items {
+		if item.Status == schema.RSSItemStatusDownloaded || item.Status == schema.RSSItemStatusQueued {
+			continue
+		}
+		published := item.PubDate
+		if published == 0 {
+			published = item.CreatedAt
+		}
+		if (maxItems > 0 && i >= maxItems) ||
+			(readDays > 0 && item.Status == schema.RSSItemStatusRead && published < cutoff) {
+			pruned = append(pruned, item)
+		}
+	}
+	return pruned
+}
+
+// dropTombstoned removes the fetched items that were pruned before and
+// keeps their tombstones alive.
+func (m *Manager) dropTombstoned(feedID string, items []schema.FeedItem) ([]schema.FeedItem, error) {
+	links := make([]string, 0, len(items))
+	for _, item := range items {
+		links = append(links, item.Link)
+	}
+	found, err := m.rssDAL.ListTombstonedLinks(m.ctx, feedID, links)
+	if err != nil || len(found) == 0 {
+		return items, err
+	}
+	if err := m.rssDAL.TouchTombstones(m.ctx, feedID, found, time.Now().Unix()); err != nil {
+		return items, err
+	}
+
+	tombstoned := make(map[string]struct{}, len(found))
+	for _, link := range found {
+		tombstoned[link] = struct{}{}
+	}
+	kept := items[:0]
+	for _, item := range items {
+		if _, ok := tombstoned[item.Link]; !ok {
+			kept = append(kept, item)
+		}
+	}
+	return kept, nil
+}
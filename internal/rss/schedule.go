@@ -0,0 +1,155 @@
+package rss
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"Kairo/internal/config"
+	"Kairo/internal/db/schema"
+	"Kairo/internal/utils"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	feedRefreshWorkers = 4 // Feeds refreshed at once
+	feedHostLimit      = 2 // Feeds of one host refreshed at once
+	feedBackoffBase    = 5 * time.Minute
+	feedBackoffMax     = 24 * time.Hour
+)
+
+// Start refreshes due feeds once a minute, each feed follows its own
+// interval or cron schedule. Item retention runs alongside.
+func (m *Manager) Start() {
+	m.startCleanup()
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.ctx.Done():
+				return
+			case <-ticker.C:
+				m.checkFeeds()
+			}
+		}
+	}()
+}
+
+func (m *Manager) checkFeeds() {
+	if m.rssDAL == nil {
+		return
+	}
+	feeds, err := m.rssDAL.ListDueFeeds(m.ctx, time.Now().Unix())
+	if err != nil {
+		fmt.Printf("Failed to get feeds for auto-refresh: %v\n", err)
+		return
+	}
+
+	for _, feed := range feeds {
+		m.mu.Lock()
+		if _, ok := m.refreshing[feed.ID]; ok {
+			m.mu.Unlock()
+			continue
+		}
+		m.refreshing[feed.ID] = struct{}{}
+		slot := m.hostSlot(feed.URL)
+		m.mu.Unlock()
+
+		go func() {
+			// Take the host slot first so feeds waiting on a busy host do
+			// not hold workers other hosts could use
+			slot <- struct{}{}
+			m.workers <- struct{}{}
+			defer func() {
+				<-m.workers
+				<-slot
+				m.mu.Lock()
+				delete(m.refreshing, feed.ID)
+				m.mu.Unlock()
+			}()
+
+			if err := m.RefreshFeed(feed.ID); err != nil {
+				fmt.Printf("Failed to auto-refresh feed %s: %v\n", feed.Title, err)
+			}
+		}()
+	}
+}
+
+// hostSlot returns the semaphore of the host of feedURL, m.mu must be held.
+func (m *Manager) hostSlot(feedURL string) chan struct{} {
+	host := utils.GetSiteKey(feedURL)
+	slot, ok := m.hostSlots[host]
+	if !ok {
+		slot = make(chan struct{}, feedHostLimit)
+		m.hostSlots[host] = slot
+	}
+	return slot
+}
+
+// recordFailure stores a failed refresh and backs the feed off.
+func (m *Manager) recordFailure(feed *schema.Feed, refreshErr error) {
+	now := time.Now()
+	feed.FailureCount++
+	err := m.rssDAL.UpdateFeed(m.ctx, feed.ID, map[string]interface{}{
+		"last_error":      refreshErr.Error(),
+		"last_error_at":   now.Unix(),
+		"failure_count":   feed.FailureCount,
+		"next_refresh_at": backoffRefresh(feed, now).Unix(),
+	})
+	if err != nil {
+		fmt.Printf("Failed to record refresh failure of feed %s: %v\n", feed.Title, err)
+	}
+}
+
+// GetUnhealthyFeeds returns the feeds whose last refresh failed.
+func (m *Manager) GetUnhealthyFeeds() ([]schema.Feed, error) {
+	if m.rssDAL == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return m.rssDAL.ListUnhealthyFeeds(m.ctx)
+}
+
+func validateSchedule(interval int, spec string) error {
+	if interval < 0 {
+		return fmt.Errorf("refresh interval must not be negative")
+	}
+	if spec = strings.TrimSpace(spec); spec != "" {
+		if _, err := cron.ParseStandard(spec); err != nil {
+			return fmt.Errorf("invalid refresh cron %q: %w", spec, err)
+		}
+	}
+	return nil
+}
+
+// nextRefresh returns when a healthy feed is refreshed after now.
+func nextRefresh(feed *schema.Feed, now time.Time) time.Time {
+	if spec := strings.TrimSpace(feed.RefreshCron); spec != "" {
+		if schedule, err := cron.ParseStandard(spec); err == nil {
+			return schedule.Next(now)
+		}
+	}
+	interval := feed.RefreshInterval
+	if interval <= 0 {
+		interval = config.GetSettings().RSSCheckInterval
+	}
+	if interval <= 0 {
+		interval = 60
+	}
+	return now.Add(time.Duration(interval) * time.Minute)
+}
+
+// backoffRefresh doubles the wait after every consecutive failure, but
+// never refreshes a broken feed sooner than its regular schedule.
+func backoffRefresh(feed *schema.Feed, now time.Time) time.Time {
+	backoff := feedBackoffMax
+	if shift := feed.FailureCount - 1; shift < 10 {
+		backoff = min(feedBackoffBase<<max(shift, 0), feedBackoffMax)
+	}
+	next := now.Add(backoff)
+	if regular := nextRefresh(feed, now); regular.After(next) {
+		return regular
+	}
+	return next
+}
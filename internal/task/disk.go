@@ -0,0 +1,131 @@
+package task
+
+import (
+	"fmt"
+	"time"
+
+	"Kairo/internal/config"
+	"Kairo/internal/db/schema"
+	"Kairo/internal/events"
+	"Kairo/internal/utils"
+)
+
+// diskCheck tracks the free space of the download volumes during one
+// scheduling pass, including the space claimed by running downloads.
+// Directories on the same volume share one budget.
+type diskCheck struct {
+	guard    config.DiskGuardConfig
+	volumes  map[string]string // dir -> volume
+	free     map[string]int64  // per volume, -1 when unknown
+	reserved map[string]int64  // per volume
+	blocked  *schema.QueueStatus
+	checked  bool // Whether the pass evaluated any task
+}
+
+func newDiskCheck(running []schema.Task) *diskCheck {
+	c := &diskCheck{
+		guard:    config.GetDiskGuard(),
+		volumes:  make(map[string]string),
+		free:     make(map[string]int64),
+		reserved: make(map[string]int64),
+	}
+	if !c.guard.Enabled {
+		return c
+	}
+	for i := range running {
+		c.reserved[c.volume(running[i].Dir)] += expectedBytes(&running[i], c.guard.SafetyFactor)
+	}
+	return c
+}
+
+// volume maps dir to its volume, falling back to the directory itself when
+// the volume cannot be determined.
+func (c *diskCheck) volume(dir string) string {
+	if v, ok := c.volumes[dir]; ok {
+		return v
+	}
+	v, err := utils.DiskVolume(dir)
+	if err != nil {
+		v = dir
+	}
+	c.volumes[dir] = v
+	return v
+}
+
+// expectedBytes is the space a task still needs, its size scaled by the
+// safety factor minus what is already on disk.
+func expectedBytes(task *schema.Task, factor float64) int64 {
+	factor = max(factor, 1)
+	return max(int64(float64(task.TotalBytes)*factor)-task.DownloadedBytes, 0)
+}
+
+// fits reports whether task can start without undercutting the reserve and
+// claims its space if so. Tasks of unknown size only need the reserve.
+func (c *diskCheck) fits(task *schema.Task) bool {
+	c.checked = true
+	if !c.guard.Enabled {
+		return true
+	}
+	vol := c.volume(task.Dir)
+	free, ok := c.free[vol]
+	if !ok {
+		var err error
+		if free, err = utils.DiskFree(task.Dir); err != nil {
+			free = -1
+		}
+		c.free[vol] = free
+	}
+	if free < 0 {
+		return true
+	}
+
+	need := expectedBytes(task, c.guard.SafetyFactor)
+	required := c.reserved[vol] + need + c.guard.MinFreeMB<<20
+	if free < required {
+		if c.blocked == nil {
+			c.blocked = &schema.QueueStatus{
+				Paused:        true,
+				Reason:        schema.QueuePausedDiskSpace,
+				Dir:           task.Dir,
+				FreeBytes:     free,
+				RequiredBytes: required,
+			}
+		}
+		return false
+	}
+	c.reserved[vol] += need
+	return true
+}
+
+// finishDiskCheck publishes the queue status of the pass and, while the queue is
+// held back, checks the disk again after the configured interval. A pass
+// that evaluated no task leaves the previous status as is.
+// Callers must hold m.mu.
+func (m *Manager) finishDiskCheck(c *diskCheck) {
+	if !c.checked {
+		return
+	}
+	status := schema.QueueStatus{}
+	if c.blocked != nil {
+		status = *c.blocked
+		interval := time.Duration(max(c.guard.CheckInterval, 5)) * time.Second
+		m.wakeAt(time.Now().Add(interval))
+	}
+	if status == m.queueStatus {
+		return
+	}
+	if status.Paused && !m.queueStatus.Paused {
+		fmt.Printf("Download queue paused, %s free in %s, %s required\n",
+			utils.FormatBytes(status.FreeBytes), status.Dir, utils.FormatBytes(status.RequiredBytes))
+	} else if !status.Paused {
+		fmt.Println("Download queue resumed")
+	}
+	m.queueStatus = status
+	m.bus.Publish(events.QueueStatus, status)
+}
+
+func (m *Manager) GetQueueStatus() schema.QueueStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.queueStatus
+}
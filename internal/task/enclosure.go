@@ -0,0 +1,422 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"Kairo/internal/config"
+	"Kairo/internal/db/schema"
+	"Kairo/internal/utils"
+)
+
+const enclosureUserAgent = "Kairo/1.0"
+
+// enclosureStatusError is a download answered with an unexpected HTTP status.
+type enclosureStatusError struct {
+	code   int
+	status string
+}
+
+func (e *enclosureStatusError) Error() string {
+	return "http error: " + e.status
+}
+
+// processEnclosure downloads the media of a podcast episode over plain HTTP
+// instead of yt-dlp. A partial file left by a paused or failed attempt is
+// resumed, the finished file is tagged from the item metadata.
+func (m *Manager) processEnclosure(ctx context.Context, task *schema.Task, stopped func() bool) {
+	task.Status = schema.TaskStatusStarting
+	task.SpeedBps = 0
+	task.EtaSeconds = 0
+	m.emitTaskLog(task.ID, "正在下载节目附件: "+task.EnclosureURL, false)
+	m.emitTaskUpdate(task)
+	m.saveTask(task)
+
+	filePath := enclosurePath(task)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		m.emitTaskLog(task.ID, "Failed to create directory: "+err.Error(), false)
+		m.failTask(task, schema.TaskErrorInternal, err.Error())
+		return
+	}
+
+	task.Status = schema.TaskStatusDownloading
+	m.emitTaskUpdate(task)
+	m.saveTask(task)
+
+	if err := m.downloadEnclosure(ctx, task, filePath); err != nil {
+		m.mu.Lock()
+		_, isDeleted := m.deletedTasks[task.ID]
+		m.mu.Unlock()
+		if stopped() || isDeleted {
+			return
+		}
+		m.emitTaskLog(task.ID, "Download Error: "+err.Error(), false)
+		m.failTask(task, enclosureErrorKind(err), err.Error())
+		return
+	}
+
+	task.Progress = 100
+	task.SpeedBps = 0
+	task.EtaSeconds = 0
+	task.FilePath = filePath
+	task.FileExists = true
+	task.ErrorKind = ""
+	task.ErrorMessage = ""
+	task.NextRetryAt = 0
+	if info, err := os.Stat(filePath); err == nil {
+		task.TotalBytes = info.Size()
+		task.DownloadedBytes = info.Size()
+	}
+
+	coverPath, err := m.fetchArtwork(ctx, task, filePath)
+	if err != nil {
+		m.emitTaskLog(task.ID, "封面下载失败: "+err.Error(), false)
+	} else if coverPath != "" {
+		task.Thumbnail = coverPath
+	}
+	if err := m.tagEnclosure(ctx, task, filePath, coverPath); err != nil {
+		m.emitTaskLog(task.ID, "写入标签失败: "+err.Error(), false)
+	} else {
+		m.emitTaskLog(task.ID, "已写入节目标签", false)
+	}
+
+	m.handleTrimming(ctx, task)
+}
+
+// downloadEnclosure fetches the enclosure into a .part file next to
+// filePath, continuing an existing one with a range request.
+func (m *Manager) downloadEnclosure(ctx context.Context, task *schema.Task, filePath string) error {
+	partPath := filePath + ".part"
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, task.EnclosureURL, nil)
+	if err != nil {
+		return err
+	}
+	netOpts := config.ResolveNetworkOptions(task.EnclosureURL)
+	if netOpts.UserAgent != "" {
+		req.Header.Set("User-Agent", netOpts.UserAgent)
+	} else {
+		req.Header.Set("User-Agent", enclosureUserAgent)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := enclosureClient(task, netOpts.Proxy).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		flags |= os.O_APPEND
+		m.emitTaskLog(task.ID, fmt.Sprintf("从 %s 处继续下载", utils.FormatBytes(offset)), false)
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// The part file already holds the whole enclosure
+		return os.Rename(partPath, filePath)
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		offset = 0
+		flags |= os.O_TRUNC
+	default:
+		return &enclosureStatusError{code: resp.StatusCode, status: resp.Status}
+	}
+
+	total := task.TotalBytes
+	if resp.ContentLength > 0 {
+		total = offset + resp.ContentLength
+	}
+	task.TotalBytes = total
+
+	file, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return err
+	}
+
+	downloaded := offset
+	lastEmit := time.Now()
+	lastSave := time.Now()
+	lastBytes := downloaded
+	buf := make([]byte, 256*1024)
+	for {
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := file.Write(buf[:n]); err != nil {
+				file.Close()
+				return err
+			}
+			downloaded += int64(n)
+		}
+		if elapsed := time.Since(lastEmit); elapsed >= 500*time.Millisecond || readErr != nil {
+			task.DownloadedBytes = downloaded
+			task.SpeedBps = float64(downloaded-lastBytes) / elapsed.Seconds()
+			task.EtaSeconds = 0
+			if total > 0 {
+				task.Progress = min(float64(downloaded)/float64(total)*100, 100)
+				if task.SpeedBps > 0 {
+					task.EtaSeconds = int64(float64(total-downloaded) / task.SpeedBps)
+				}
+			}
+			m.emitTaskUpdate(task)
+			lastEmit = time.Now()
+			lastBytes = downloaded
+			if time.Since(lastSave) > time.Second {
+				m.saveProgress(task)
+				lastSave = time.Now()
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			file.Close()
+			return readErr
+		}
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if total > 0 && downloaded < total {
+		return fmt.Errorf("download ended at %d of %d bytes: %w", downloaded, total, io.ErrUnexpectedEOF)
+	}
+	return os.Rename(partPath, filePath)
+}
+
+func enclosureClient(task *schema.Task, proxy string) *http.Client {
+	if task.FallbackProxy {
+		if fallback := config.GetFallbackProxy(); fallback != "" {
+			proxy = fallback
+		}
+	}
+	// No overall timeout, episodes can take long on slow hosts
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		ResponseHeaderTimeout: 30 * time.Second,
+	}
+	if proxy != "" {
+		if u, err := url.Parse(proxy); err == nil {
+			transport.Proxy = http.ProxyURL(u)
+		}
+	}
+	return &http.Client{Transport: transport}
+}
+
+func enclosureErrorKind(err error) schema.TaskErrorKind {
+	var statusErr *enclosureStatusError
+	if errors.As(err, &statusErr) {
+		switch {
+		case statusErr.code == http.StatusTooManyRequests:
+			return schema.TaskErrorRateLimited
+		case statusErr.code == http.StatusUnauthorized || statusErr.code == http.StatusForbidden:
+			return schema.TaskErrorForbidden
+		case statusErr.code == http.StatusNotFound || statusErr.code == http.StatusGone:
+			return schema.TaskErrorUnavailable
+		case statusErr.code >= 500:
+			return schema.TaskErrorNetwork
+		}
+		return schema.TaskErrorUnknown
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return schema.TaskErrorNetwork
+	}
+	return schema.TaskErrorUnknown
+}
+
+// enclosurePath lays the episode out like yt-dlp would, following the
+// output template of the feed when there is one.
+func enclosurePath(task *schema.Task) string {
+	ext := ".mp3"
+	id := "episode"
+	if u, err := url.Parse(task.EnclosureURL); err == nil {
+		if e := strings.ToLower(path.Ext(u.Path)); e != "" && len(e) <= 6 {
+			ext = e
+		}
+		if stem := strings.TrimSuffix(path.Base(u.Path), path.Ext(u.Path)); stem != "" && stem != "/" && stem != "." {
+			id = stem
+		}
+	}
+
+	if task.OutputTemplate == "" {
+		name := utils.SanitizeFileName(task.Title)
+		return filepath.Join(task.Dir, name, name+ext)
+	}
+	var uploadDate string
+	if task.PublishedAt > 0 {
+		uploadDate = time.Unix(task.PublishedAt, 0).Format("20060102")
+	}
+	rel := expandOutputTemplate(task.OutputTemplate, map[string]string{
+		"title":       task.Title,
+		"id":          id,
+		"uploader":    task.Author,
+		"channel":     task.Author,
+		"upload_date": uploadDate,
+		"ext":         strings.TrimPrefix(ext, "."),
+	})
+	return filepath.Join(task.Dir, filepath.FromSlash(rel))
+}
+
+// outputFieldRegex matches %% and the yt-dlp fields RenderOutputTemplate
+// emits, with their flags, width and conversion.
+var outputFieldRegex = regexp.MustCompile(`%%|%\(([^)]+)\)[#0\-+ .0-9]*([sSdif])`)
+
+// expandOutputTemplate fills the yt-dlp fields of a rendered output template
+// the way yt-dlp does, including alternatives, defaults and date formats.
+// Values are sanitized as file names, the S conversion sanitizes the
+// default as well.
+func expandOutputTemplate(tpl string, fields map[string]string) string {
+	return outputFieldRegex.ReplaceAllStringFunc(tpl, func(match string) string {
+		if match == "%%" {
+			return "%"
+		}
+		sub := outputFieldRegex.FindStringSubmatch(match)
+		spec, fallback, _ := strings.Cut(sub[1], "|")
+		spec, layout, _ := strings.Cut(spec, ">")
+		for _, name := range strings.Split(spec, ",") {
+			value := fields[strings.TrimSpace(name)]
+			if value == "" {
+				continue
+			}
+			if layout != "" {
+				if date, err := time.Parse("20060102", value); err == nil {
+					value = date.Format(strftimeReplacer.Replace(layout))
+				}
+			}
+			return utils.SanitizeFileName(value)
+		}
+		if fallback == "" {
+			return "NA"
+		}
+		if sub[2] == "S" {
+			return utils.SanitizeFileName(fallback)
+		}
+		return fallback
+	})
+}
+
+var strftimeReplacer = strings.NewReplacer("%Y", "2006", "%m", "01", "%d", "02")
+
+// fetchArtwork saves the episode artwork next to the file as JPEG, returns
+// an empty path when there is none.
+func (m *Manager) fetchArtwork(ctx context.Context, task *schema.Task, filePath string) (string, error) {
+	if !strings.HasPrefix(task.Thumbnail, "http://") && !strings.HasPrefix(task.Thumbnail, "https://") {
+		return "", nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, task.Thumbnail, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", enclosureUserAgent)
+	client := enclosureClient(task, config.ResolveNetworkOptions(task.Thumbnail).Proxy)
+	client.Timeout = 30 * time.Second
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("http error: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 20<<20))
+	if err != nil {
+		return "", err
+	}
+
+	coverPath := strings.TrimSuffix(filePath, filepath.Ext(filePath)) + ".jpg"
+	if bytes.HasPrefix(data, []byte{0xff, 0xd8}) {
+		return coverPath, os.WriteFile(coverPath, data, 0644)
+	}
+	// Convert PNG and WebP artwork so players and the library can use it
+	rawPath := coverPath + ".src"
+	if err := os.WriteFile(rawPath, data, 0644); err != nil {
+		return "", err
+	}
+	defer os.Remove(rawPath)
+	ffmpegPath, err := m.deps.GetFFmpegPath()
+	if err != nil {
+		return "", err
+	}
+	cmd := utils.CreateCommandContext(ctx, ffmpegPath, "-i", rawPath, "-y", coverPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("%v: %s", err, lastLine(output))
+	}
+	return coverPath, nil
+}
+
+// tagEnclosure writes the episode metadata into the file, the artwork is
+// embedded for MP3 and M4A files.
+func (m *Manager) tagEnclosure(ctx context.Context, task *schema.Task, filePath, coverPath string) error {
+	ffmpegPath, err := m.deps.GetFFmpegPath()
+	if err != nil {
+		return err
+	}
+	var album string
+	if task.ParentID != "" {
+		if parent, err := m.getTask(task.ParentID); err == nil && parent != nil {
+			album = parent.Title
+		}
+	}
+	artist := task.Author
+	if artist == "" {
+		artist = album
+	}
+	var date string
+	if task.PublishedAt > 0 {
+		date = time.Unix(task.PublishedAt, 0).Format("2006-01-02")
+	}
+
+	ext := strings.ToLower(filepath.Ext(filePath))
+	args := []string{"-i", filePath}
+	if coverPath != "" && (ext == ".mp3" || ext == ".m4a") {
+		args = append(args, "-i", coverPath, "-map", "0:a", "-map", "1:v", "-disposition:v", "attached_pic")
+	} else {
+		args = append(args, "-map", "0")
+	}
+	args = append(args, "-c", "copy")
+	if ext == ".mp3" {
+		args = append(args, "-id3v2_version", "3")
+	}
+	for _, tag := range [][2]string{
+		{"title", task.Title},
+		{"artist", artist},
+		{"album_artist", artist},
+		{"album", album},
+		{"date", date},
+		{"genre", "Podcast"},
+		{"comment", task.Description},
+	} {
+		if tag[1] != "" {
+			args = append(args, "-metadata", tag[0]+"="+tag[1])
+		}
+	}
+	taggedPath := strings.TrimSuffix(filePath, filepath.Ext(filePath)) + ".tagged" + filepath.Ext(filePath)
+	args = append(args, "-y", taggedPath)
+
+	cmd := utils.CreateCommandContext(ctx, ffmpegPath, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(taggedPath)
+		return fmt.Errorf("%v: %s", err, lastLine(output))
+	}
+	return os.Rename(taggedPath, filePath)
+}
+
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return lines[len(lines)-1]
+}
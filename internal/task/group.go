@@ -0,0 +1,220 @@
+package task
+
+import (
+	"fmt"
+	"time"
+
+	"Kairo/internal/db/schema"
+)
+
+// isGroup reports whether task is the parent of a playlist or RSS feed.
+func isGroup(task *schema.Task) bool {
+	return task.ParentID == "" &&
+		(task.SourceType == schema.SourceTypePlaylist || task.SourceType == schema.SourceTypeRSS)
+}
+
+// aggregateGroup derives the status, progress and byte totals of a parent
+// from its children. Cancelled children do not count towards progress.
+func aggregateGroup(parent *schema.Task, children []*schema.Task) {
+	var active, pending, paused, completed, failed, cancelled int
+	var progress float64
+	var totalBytes, downloadedBytes int64
+	for _, c := range children {
+		switch c.Status {
+		case schema.TaskStatusStarting, schema.TaskStatusDownloading, schema.TaskStatusMerging, schema.TaskStatusTrimming:
+			active++
+		case schema.TaskStatusPending:
+			pending++
+		case schema.TaskStatusPaused:
+			paused++
+		case schema.TaskStatusCompleted:
+			completed++
+		case schema.TaskStatusError, schema.TaskStatusTrimFailed:
+			failed++
+		case schema.TaskStatusCancelled:
+			cancelled++
+			continue
+		}
+		progress += c.Progress
+		totalBytes += c.TotalBytes
+		if c.Status == schema.TaskStatusCompleted {
+			downloadedBytes += c.TotalBytes
+		} else {
+			downloadedBytes += c.DownloadedBytes
+		}
+	}
+
+	switch {
+	case active > 0:
+		parent.Status = schema.TaskStatusDownloading
+	case pending > 0:
+		parent.Status = schema.TaskStatusPending
+	case paused > 0:
+		parent.Status = schema.TaskStatusPaused
+	case failed > 0 && completed > 0:
+		parent.Status = schema.TaskStatusPartialFailed
+	case failed > 0:
+		parent.Status = schema.TaskStatusError
+	case completed == 0 && cancelled > 0:
+		parent.Status = schema.TaskStatusCancelled
+	default:
+		parent.Status = schema.TaskStatusCompleted
+	}
+
+	parent.TotalBytes = totalBytes
+	parent.DownloadedBytes = downloadedBytes
+	switch counted := len(children) - cancelled; {
+	case len(children) == 0:
+		parent.Progress = 100
+	case counted > 0:
+		parent.Progress = progress / float64(counted)
+	default:
+		parent.Progress = 0
+	}
+}
+
+// groupRefreshDelay batches the refreshes of a parent while its children save
+// progress every second.
+const groupRefreshDelay = time.Second
+
+// queueGroupRefresh refreshes a parent once after groupRefreshDelay, saves
+// of its children in the meantime are folded into that refresh.
+func (m *Manager) queueGroupRefresh(parentID string) {
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+	if _, ok := m.refreshPending[parentID]; ok {
+		return
+	}
+	m.refreshPending[parentID] = struct{}{}
+	time.AfterFunc(groupRefreshDelay, func() {
+		m.refreshMu.Lock()
+		delete(m.refreshPending, parentID)
+		m.refreshMu.Unlock()
+		m.refreshGroup(parentID)
+	})
+}
+
+// refreshGroup recomputes a parent task from its children and publishes it
+// when anything changed.
+func (m *Manager) refreshGroup(parentID string) {
+	m.groupMu.Lock()
+	defer m.groupMu.Unlock()
+
+	parent, err := m.getTask(parentID)
+	if err != nil || !isGroup(parent) {
+		return
+	}
+	children, err := m.getTasksByParentID(parentID)
+	if err != nil {
+		return
+	}
+	before := *parent
+	aggregateGroup(parent, children)
+	if parent.Status == before.Status && parent.Progress == before.Progress &&
+		parent.TotalBytes == before.TotalBytes && parent.DownloadedBytes == before.DownloadedBytes {
+		return
+	}
+
+	// Do not bring back a parent deleted in the meantime
+	m.mu.Lock()
+	_, deleted := m.deletedTasks[parentID]
+	m.mu.Unlock()
+	if deleted {
+		return
+	}
+	m.saveTask(parent)
+	m.emitTaskUpdate(parent)
+}
+
+func (m *Manager) getGroupChildren(id string) ([]*schema.Task, error) {
+	parent, err := m.getTask(id)
+	if err != nil {
+		return nil, err
+	}
+	if !isGroup(parent) {
+		return nil, fmt.Errorf("task is not a playlist or feed")
+	}
+	return m.getTasksByParentID(id)
+}
+
+// stopTask moves a child to status and stops its download if running. The
+// status is saved first so the runner does not report the stop as failure,
+// the runner's progress saves leave it untouched.
+func (m *Manager) stopTask(task *schema.Task, status schema.TaskStatus) {
+	task.Status = status
+	task.SpeedBps = 0
+	task.EtaSeconds = 0
+	m.saveTask(task)
+
+	m.mu.Lock()
+	if cancel, ok := m.cancelFuncs[task.ID]; ok {
+		cancel()
+		delete(m.cancelFuncs, task.ID)
+	}
+	m.mu.Unlock()
+	m.emitTaskUpdate(task)
+}
+
+// PauseGroup pauses every queued and running child of a playlist or feed.
+func (m *Manager) PauseGroup(id string) error {
+	children, err := m.getGroupChildren(id)
+	if err != nil {
+		return err
+	}
+	for _, c := range children {
+		switch c.Status {
+		case schema.TaskStatusPending, schema.TaskStatusStarting, schema.TaskStatusDownloading:
+			m.stopTask(c, schema.TaskStatusPaused)
+		}
+	}
+	return nil
+}
+
+// ResumeGroup queues the paused children of a playlist or feed again.
+func (m *Manager) ResumeGroup(id string) error {
+	return m.requeueGroup(id, schema.TaskStatusPaused)
+}
+
+// RetryFailedInGroup queues the failed children of a playlist or feed again.
+func (m *Manager) RetryFailedInGroup(id string) error {
+	return m.requeueGroup(id, schema.TaskStatusError, schema.TaskStatusTrimFailed)
+}
+
+func (m *Manager) requeueGroup(id string, statuses ...schema.TaskStatus) error {
+	children, err := m.getGroupChildren(id)
+	if err != nil {
+		return err
+	}
+	for _, c := range children {
+		for _, status := range statuses {
+			if c.Status != status {
+				continue
+			}
+			if status != schema.TaskStatusPaused {
+				c.Progress = 0
+			}
+			c.Status = schema.TaskStatusPending
+			resetRetryState(c)
+			m.saveTask(c)
+			m.emitTaskUpdate(c)
+		}
+	}
+	go m.scheduleTasks()
+	return nil
+}
+
+// CancelGroup cancels the children of a playlist or feed that have not
+// finished yet. Children that are merging or trimming are left to finish.
+func (m *Manager) CancelGroup(id string) error {
+	children, err := m.getGroupChildren(id)
+	if err != nil {
+		return err
+	}
+	for _, c := range children {
+		switch c.Status {
+		case schema.TaskStatusPending, schema.TaskStatusPaused, schema.TaskStatusStarting, schema.TaskStatusDownloading:
+			m.stopTask(c, schema.TaskStatusCancelled)
+		}
+	}
+	return nil
+}
@@ -7,9 +7,8 @@ import (
 	"time"
 
 	"Kairo/internal/config"
+	"Kairo/internal/events"
 	"Kairo/internal/utils"
-
-	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 func (m *Manager) emitTaskLog(id, message string, replace bool) {
@@ -22,7 +21,7 @@ func (m *Manager) emitTaskLog(id, message string, replace bool) {
 	if !replace {
 		m.appendTextLog(id, message)
 	}
-	runtime.EventsEmit(m.ctx, "task:log", map[string]interface{}{
+	m.bus.Publish(events.TaskLog, map[string]interface{}{
 		"id":      id,
 		"message": message,
 		"replace": replace,
@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,35 +16,53 @@ import (
 	"Kairo/internal/db/dal"
 	"Kairo/internal/db/schema"
 	"Kairo/internal/deps"
+	"Kairo/internal/events"
+	"Kairo/internal/utils"
 
-	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 	"gorm.io/gorm"
 )
 
 type Manager struct {
 	ctx            context.Context
 	deps           *deps.Manager
+	bus            *events.Bus
 	cancelFuncs    map[string]context.CancelFunc
 	deletedTasks   map[string]struct{}
 	mu             sync.Mutex
+	syncMu         sync.Mutex // Serializes playlist syncs
+	groupMu        sync.Mutex // Serializes parent task refreshes
+	refreshMu      sync.Mutex
+	refreshPending map[string]struct{} // Parents with a debounced refresh
 	db             *gorm.DB
 	taskDAL        *dal.TaskDAL
+	wakeTimer      *time.Timer
+	wakeTime       time.Time
+	siteStarts     map[string][]time.Time
+	queueStatus    schema.QueueStatus
 	OnTaskComplete func(task *schema.Task)
 	OnTaskFailed   func(task *schema.Task)
 }
 
-func NewManager(ctx context.Context, db *gorm.DB, d *deps.Manager) *Manager {
+func NewManager(ctx context.Context, db *gorm.DB, d *deps.Manager, bus *events.Bus) *Manager {
 	m := &Manager{
-		ctx:          ctx,
-		deps:         d,
-		cancelFuncs:  make(map[string]context.CancelFunc),
-		deletedTasks: make(map[string]struct{}),
+		ctx:            ctx,
+		deps:           d,
+		bus:            bus,
+		cancelFuncs:    make(map[string]context.CancelFunc),
+		deletedTasks:   make(map[string]struct{}),
+		siteStarts:     make(map[string][]time.Time),
+		refreshPending: make(map[string]struct{}),
 	}
 	m.db = db
 	if db != nil {
 		m.taskDAL = dal.NewTaskDAL(db)
 	}
 	m.resetInterruptedTasks()
+	m.restoreSiteStarts()
+	m.refreshGroups()
+	m.mu.Lock()
+	m.armRetryTimer()
+	m.mu.Unlock()
 	return m
 }
 
@@ -66,17 +85,28 @@ func (m *Manager) GetTasks() map[string]*schema.Task {
 	return tasks
 }
 
+func (m *Manager) GetTask(id string) (*schema.Task, error) {
+	return m.getTask(id)
+}
+
 func (m *Manager) AddPlaylistTask(input schema.AddPlaylistTaskInput) (string, error) {
 	dir, err := validateTaskInput(input.URL, input.Dir)
 	if err != nil {
 		return "", err
 	}
+	if err := utils.ValidateOutputTemplate(input.FilenameTemplate); err != nil {
+		return "", err
+	}
+	if err := validatePriority(input.Priority); err != nil {
+		return "", err
+	}
 
 	// 1. Create parent task
 	parentTask := newTask(input.URL, dir, input.Title, input.Thumbnail, schema.SourceTypePlaylist, input.CategoryID)
-	parentTask.Status = schema.TaskStatusCompleted
-	parentTask.Progress = 100
-	parentTask.TotalBytes = 0
+	parentTask.Priority = input.Priority
+	parentTask.FilenameTemplate = input.FilenameTemplate
+	parentTask.SyncInterval = max(input.SyncInterval, 0)
+	parentTask.LastSyncedAt = parentTask.CreatedAt
 
 	m.registerCancel(parentTask.ID)
 
@@ -84,17 +114,13 @@ func (m *Manager) AddPlaylistTask(input schema.AddPlaylistTaskInput) (string, er
 	tasks := []*schema.Task{
 		parentTask,
 	}
-	for _, item := range input.PlaylistItems {
-		childTask := newTask(item.URL, dir, item.Title, item.Thumbnail, schema.SourceTypePlaylist, input.CategoryID)
-		childTask.LogPath = config.GetLogPath(childTask.ID)
-		childTask.ParentID = parentTask.ID
-		childTask.Quality = "best"
-		childTask.Format = "original"
-
+	for i, item := range input.PlaylistItems {
+		// Keep the playlist order within the queue
+		childTask := newPlaylistChild(parentTask, item, parentTask.QueueOrder+int64(i)+1)
 		m.registerCancel(childTask.ID)
-
 		tasks = append(tasks, childTask)
 	}
+	aggregateGroup(parentTask, tasks[1:])
 
 	// 3. Save tasks and emit updates
 	for _, t := range tasks {
@@ -118,6 +144,15 @@ func (m *Manager) AddRSSTask(input schema.AddRSSTaskInput) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if err := validatePriority(input.Priority); err != nil {
+		return "", err
+	}
+	outputTemplate, err := renderOutputTemplate(input.FilenameTemplate, utils.OutputTemplateVars{
+		Feed: input.FeedTitle,
+	})
+	if err != nil {
+		return "", err
+	}
 
 	// 1. Check for existing parent task
 	parentTask, _ := m.findTaskBySourceAndURL(schema.SourceTypeRSS, input.FeedURL)
@@ -130,6 +165,7 @@ func (m *Manager) AddRSSTask(input schema.AddRSSTaskInput) (string, error) {
 		parentTask.Status = schema.TaskStatusCompleted
 		parentTask.Progress = 100
 		parentTask.TotalBytes = 0
+		parentTask.FeedID = input.FeedID
 
 		m.registerCancel(parentTask.ID)
 		tasks = append(tasks, parentTask)
@@ -141,6 +177,27 @@ func (m *Manager) AddRSSTask(input schema.AddRSSTaskInput) (string, error) {
 	childTask.ParentID = parentTask.ID
 	childTask.Quality = "best"
 	childTask.Format = "original"
+	childTask.OutputTemplate = outputTemplate
+	childTask.FeedID = input.FeedID
+	childTask.FeedItemID = input.FeedItemID
+	childTask.Description = input.ItemDescription
+	childTask.Tags = input.FeedTags
+	childTask.Priority = input.Priority
+	childTask.Author = input.ItemAuthor
+	childTask.PublishedAt = input.ItemPubDate
+	if input.EnclosureURL != "" {
+		// Podcast episode, fetched directly and tagged with the feed artwork
+		// when it has none of its own
+		childTask.EnclosureURL = input.EnclosureURL
+		childTask.TotalBytes = input.EnclosureLength
+		if childTask.Thumbnail == "" {
+			childTask.Thumbnail = input.FeedThumbnail
+		}
+		if strings.HasPrefix(input.EnclosureType, "audio/") {
+			childTask.Quality = "audio"
+			childTask.Format = "audio"
+		}
+	}
 
 	m.registerCancel(childTask.ID)
 	tasks = append(tasks, childTask)
@@ -161,6 +218,13 @@ func (m *Manager) AddTask(input schema.AddTaskInput) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	outputTemplate, err := renderOutputTemplate(input.FilenameTemplate, utils.OutputTemplateVars{})
+	if err != nil {
+		return "", err
+	}
+	if err := validatePriority(input.Priority); err != nil {
+		return "", err
+	}
 
 	task := newTask(input.URL, dir, input.Title, input.Thumbnail, input.SourceType, input.CategoryID)
 	task.Quality = input.Quality
@@ -171,6 +235,8 @@ func (m *Manager) AddTask(input schema.AddTaskInput) (string, error) {
 	task.TrimEnd = input.TrimEnd
 	task.TrimMode = input.TrimMode
 	task.CategoryID = input.CategoryID
+	task.OutputTemplate = outputTemplate
+	task.Priority = input.Priority
 
 	m.registerCancel(task.ID)
 	m.saveTask(task)
@@ -184,42 +250,74 @@ func (m *Manager) AddTask(input schema.AddTaskInput) (string, error) {
 	return task.ID, nil
 }
 
+// schedulePageSize is how many pending tasks one scheduler pass loads at a
+// time.
+const schedulePageSize = 50
+
 func (m *Manager) scheduleTasks() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	downloadingCount, err := m.getRunningTaskCount()
+	running, err := m.getRunningTasks()
 	if err != nil {
-		fmt.Printf("Failed to get running task count: %v\n", err)
-		return
-	}
-
-	maxConcurrent := config.GetMaxConcurrentDownloads()
-
-	if downloadingCount >= maxConcurrent {
+		fmt.Printf("Failed to get running tasks: %v\n", err)
 		return
 	}
 
-	pendingNeeded := maxConcurrent - downloadingCount
-	if pendingNeeded <= 0 {
-		return
-	}
+	pendingNeeded := config.GetMaxConcurrentDownloads() - len(running)
 
-	pendingTasks, err := m.getPendingTasks(pendingNeeded)
-	if err != nil {
-		fmt.Printf("Failed to get pending tasks: %v\n", err)
-		return
+	siteRunning := make(map[string]int)
+	for _, t := range running {
+		siteRunning[utils.GetSiteKey(t.URL)]++
 	}
 
-	for _, task := range pendingTasks {
-		// Update status immediately to prevent duplicate scheduling
-		task.Status = schema.TaskStatusStarting
-		m.saveTask(task)
-
-		ctx, cancel := context.WithCancel(m.ctx)
-		m.cancelFuncs[task.ID] = cancel
-		go m.processTask(ctx, task)
+	now := time.Now()
+	disk := newDiskCheck(running)
+	// Walk the queue page by page, tasks of a site at its limit are skipped
+	// and later tasks of other sites may start instead
+	offset := 0
+	for pendingNeeded > 0 {
+		pendingTasks, err := m.getPendingTasks(offset, schedulePageSize)
+		if err != nil {
+			fmt.Printf("Failed to get pending tasks: %v\n", err)
+			break
+		}
+		if offset == 0 && len(pendingTasks) == 0 {
+			// An empty queue has nothing left to hold back
+			disk.checked = true
+		}
+		for _, task := range pendingTasks {
+			if pendingNeeded <= 0 {
+				break
+			}
+			site := utils.GetSiteKey(task.URL)
+			if !m.siteAvailable(site, siteRunning[site], now) {
+				offset++
+				continue
+			}
+			if !disk.fits(task) {
+				offset++
+				continue
+			}
+			siteRunning[site]++
+			m.siteStarts[site] = append(m.siteStarts[site], now)
+			pendingNeeded--
+
+			// Update status immediately to prevent duplicate scheduling
+			task.Status = schema.TaskStatusStarting
+			task.StartedAt = now.Unix()
+			m.saveTask(task)
+
+			ctx, cancel := context.WithCancel(m.ctx)
+			m.cancelFuncs[task.ID] = cancel
+			go m.processTask(ctx, task)
+		}
+		if len(pendingTasks) < schedulePageSize {
+			break
+		}
 	}
+	m.finishDiskCheck(disk)
+	m.armRetryTimer()
 }
 
 func (m *Manager) DeleteTask(id string, deleteFile bool) ([]string, error) {
@@ -259,6 +357,11 @@ func (m *Manager) DeleteTask(id string, deleteFile bool) ([]string, error) {
 		}
 		if !hasSiblings {
 			idsToDelete[task.ParentID] = struct{}{}
+		} else {
+			if task.SourceType == schema.SourceTypePlaylist {
+				m.rememberRemovedEntry(task)
+			}
+			go m.refreshGroup(task.ParentID)
 		}
 	}
 
@@ -280,11 +383,16 @@ func (m *Manager) deleteTaskInternal(id string, deleteFile bool) {
 			return
 		}
 
-		if deleteFile {
-			targetDir := filepath.Dir(task.FilePath)
-			go func(dir string) {
-				_ = os.RemoveAll(dir)
-			}(targetDir)
+		if deleteFile && task.FilePath != "" {
+			if task.OutputTemplate != "" {
+				// Templated outputs share directories, only remove this task's files
+				go removeTemplatedOutput(task.Dir, task.FilePath)
+			} else {
+				targetDir := filepath.Dir(task.FilePath)
+				go func(dir string) {
+					_ = os.RemoveAll(dir)
+				}(targetDir)
+			}
 		}
 	}
 
@@ -304,6 +412,10 @@ func (m *Manager) deleteTaskInternal(id string, deleteFile bool) {
 }
 
 func (m *Manager) PauseTask(id string) error {
+	if task, err := m.getTask(id); err == nil && isGroup(task) {
+		return m.PauseGroup(id)
+	}
+
 	m.mu.Lock()
 	// Check if running via cancelFuncs
 	_, isRunning := m.cancelFuncs[id]
@@ -338,23 +450,24 @@ func (m *Manager) ResumeTask(id string) error {
 	if err != nil {
 		return err
 	}
+	if isGroup(task) {
+		return m.ResumeGroup(id)
+	}
 
-	// Allow resuming from paused or error
-	if task.Status != schema.TaskStatusPaused && task.Status != schema.TaskStatusError {
+	// Allow resuming from paused, error or cancelled
+	if task.Status != schema.TaskStatusPaused && task.Status != schema.TaskStatusError &&
+		task.Status != schema.TaskStatusCancelled {
 		return fmt.Errorf("task is not paused or in error state")
 	}
 
 	task.Status = schema.TaskStatusPending
+	resetRetryState(task)
 
 	m.emitTaskUpdate(task)
 	m.saveTask(task)
 
-	ctx, cancel := context.WithCancel(m.ctx)
-	m.mu.Lock()
-	m.cancelFuncs[id] = cancel
-	m.mu.Unlock()
-
-	go m.processTask(ctx, task)
+	// The scheduler starts it within the site limits and disk guard
+	go m.scheduleTasks()
 	return nil
 }
 
@@ -363,19 +476,19 @@ func (m *Manager) RetryTask(id string) error {
 	if err != nil {
 		return err
 	}
+	if isGroup(task) {
+		return m.RetryFailedInGroup(id)
+	}
 
 	task.Status = schema.TaskStatusPending
 	task.Progress = 0
+	resetRetryState(task)
 
 	m.emitTaskUpdate(task)
 	m.saveTask(task)
 
-	ctx, cancel := context.WithCancel(m.ctx)
-	m.mu.Lock()
-	m.cancelFuncs[id] = cancel
-	m.mu.Unlock()
-
-	go m.processTask(ctx, task)
+	// The scheduler starts it within the site limits and disk guard
+	go m.scheduleTasks()
 	return nil
 }
 
@@ -415,7 +528,7 @@ func (m *Manager) emitTaskUpdate(task *schema.Task) {
 	}
 	t := *task
 	m.mu.Unlock()
-	wailsRuntime.EventsEmit(m.ctx, "task:update", t)
+	m.bus.Publish(events.TaskUpdate, t)
 }
 
 func (m *Manager) saveTask(task *schema.Task) {
@@ -427,6 +540,23 @@ func (m *Manager) saveTask(task *schema.Task) {
 	if err != nil {
 		fmt.Printf("Failed to save task %s: %v\n", task.ID, err)
 	}
+	if task.ParentID != "" {
+		m.queueGroupRefresh(task.ParentID)
+	}
+}
+
+// saveProgress stores the progress of a running download without its status.
+func (m *Manager) saveProgress(task *schema.Task) {
+	if m.taskDAL == nil {
+		return
+	}
+	task.UpdatedAt = time.Now().Unix()
+	if err := m.taskDAL.SaveProgress(m.ctx, task); err != nil {
+		fmt.Printf("Failed to save task %s: %v\n", task.ID, err)
+	}
+	if task.ParentID != "" {
+		m.queueGroupRefresh(task.ParentID)
+	}
 }
 
 func (m *Manager) resetInterruptedTasks() {
@@ -445,6 +575,24 @@ func (m *Manager) resetInterruptedTasks() {
 	}
 }
 
+// refreshGroups derives every parent task from its children, parents saved
+// before their status was derived start out as completed.
+func (m *Manager) refreshGroups() {
+	if m.taskDAL == nil {
+		return
+	}
+	rows, err := m.taskDAL.List(m.ctx)
+	if err != nil {
+		fmt.Printf("Failed to refresh parent tasks: %v\n", err)
+		return
+	}
+	for i := range rows {
+		if isGroup(&rows[i]) {
+			m.refreshGroup(rows[i].ID)
+		}
+	}
+}
+
 func (m *Manager) getTask(id string) (*schema.Task, error) {
 	if m.taskDAL == nil {
 		return nil, fmt.Errorf("database not initialized")
@@ -488,9 +636,9 @@ func (m *Manager) registerCancel(id string) {
 	delete(m.deletedTasks, id)
 }
 
-func (m *Manager) getRunningTaskCount() (int, error) {
+func (m *Manager) getRunningTasks() ([]schema.Task, error) {
 	if m.taskDAL == nil {
-		return 0, fmt.Errorf("database not initialized")
+		return nil, fmt.Errorf("database not initialized")
 	}
 	statuses := []string{
 		string(schema.TaskStatusDownloading),
@@ -498,6 +646,5 @@ func (m *Manager) getRunningTaskCount() (int, error) {
 		string(schema.TaskStatusMerging),
 		string(schema.TaskStatusTrimming),
 	}
-	count, err := m.taskDAL.CountByStatus(m.ctx, statuses)
-	return int(count), err
+	return m.taskDAL.ListByStatus(m.ctx, statuses)
 }
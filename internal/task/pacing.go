@@ -0,0 +1,88 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"Kairo/internal/config"
+	"Kairo/internal/utils"
+)
+
+// siteAvailable reports whether another download of the site may start now.
+// When the hourly budget is used up the scheduler is woken once a slot frees.
+// Callers must hold m.mu.
+func (m *Manager) siteAvailable(site string, running int, now time.Time) bool {
+	limit := config.GetSiteLimit(site)
+	if limit.MaxConcurrent > 0 && running >= limit.MaxConcurrent {
+		return false
+	}
+	if limit.MaxPerHour <= 0 {
+		return true
+	}
+
+	cutoff := now.Add(-time.Hour)
+	starts := m.siteStarts[site]
+	for len(starts) > 0 && !starts[0].After(cutoff) {
+		starts = starts[1:]
+	}
+	m.siteStarts[site] = starts
+	if len(starts) < limit.MaxPerHour {
+		return true
+	}
+	m.wakeAt(starts[0].Add(time.Hour))
+	return false
+}
+
+// restoreSiteStarts reloads the downloads started within the last hour so
+// the hourly budgets survive a restart.
+func (m *Manager) restoreSiteStarts() {
+	if m.taskDAL == nil {
+		return
+	}
+	tasks, err := m.taskDAL.ListStartedSince(m.ctx, time.Now().Add(-time.Hour).Unix())
+	if err != nil {
+		fmt.Printf("Failed to restore site start times: %v\n", err)
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range tasks {
+		site := utils.GetSiteKey(t.URL)
+		m.siteStarts[site] = append(m.siteStarts[site], time.Unix(t.StartedAt, 0))
+	}
+}
+
+// wakeAt makes sure the scheduler runs again at t, keeping an earlier wake up
+// that is still due. Callers must hold m.mu.
+func (m *Manager) wakeAt(t time.Time) {
+	now := time.Now()
+	if m.wakeTimer != nil && m.wakeTime.After(now) && !m.wakeTime.After(t) {
+		return
+	}
+	if m.wakeTimer != nil {
+		m.wakeTimer.Stop()
+	}
+	delay := t.Sub(now)
+	if delay < time.Second {
+		delay = time.Second
+	}
+	m.wakeTime = now.Add(delay)
+	m.wakeTimer = time.AfterFunc(delay, m.scheduleTasks)
+}
+
+// pacingArgs returns the yt-dlp sleep options configured for the site.
+func pacingArgs(site string) []string {
+	limit := config.GetSiteLimit(site)
+	var args []string
+	if limit.SleepRequests > 0 {
+		args = append(args, "--sleep-requests", strconv.FormatFloat(limit.SleepRequests, 'f', -1, 64))
+	}
+	if limit.SleepInterval > 0 {
+		args = append(args, "--sleep-interval", strconv.Itoa(limit.SleepInterval))
+		if limit.MaxSleepInterval > limit.SleepInterval {
+			args = append(args, "--max-sleep-interval", strconv.Itoa(limit.MaxSleepInterval))
+		}
+	}
+	return args
+}
@@ -0,0 +1,170 @@
+package task
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"Kairo/internal/config"
+	"Kairo/internal/db/schema"
+	"Kairo/internal/utils"
+)
+
+func newPlaylistChild(parent *schema.Task, item schema.PlaylistItem, queueOrder int64) *schema.Task {
+	child := newTask(item.URL, parent.Dir, item.Title, item.Thumbnail, schema.SourceTypePlaylist, parent.CategoryID)
+	child.LogPath = config.GetLogPath(child.ID)
+	child.ParentID = parent.ID
+	child.ExternalID = item.ID
+	child.Quality = "best"
+	child.Format = "original"
+	child.Priority = parent.Priority
+	child.QueueOrder = queueOrder
+	child.OutputTemplate, _ = renderOutputTemplate(parent.FilenameTemplate, utils.OutputTemplateVars{
+		Playlist:      parent.Title,
+		PlaylistIndex: item.Index,
+	})
+	return child
+}
+
+// playlistEntryKeys are the keys a playlist entry is matched by, its URL and
+// its extractor ID.
+func playlistEntryKeys(url, externalID string) []string {
+	var keys []string
+	if url = strings.TrimSpace(url); url != "" {
+		keys = append(keys, url)
+	}
+	if externalID != "" {
+		keys = append(keys, "id:"+externalID)
+	}
+	return keys
+}
+
+// rememberRemovedEntry records a deleted playlist child on its parent so
+// later syncs do not enqueue it again.
+func (m *Manager) rememberRemovedEntry(child *schema.Task) {
+	if m.taskDAL == nil {
+		return
+	}
+	keys := playlistEntryKeys(child.URL, child.ExternalID)
+	if err := m.taskDAL.AddRemovedEntries(m.ctx, child.ParentID, keys); err != nil {
+		fmt.Printf("Failed to remember removed playlist entry %s: %v\n", child.ID, err)
+	}
+}
+
+// SyncPlaylist extracts the playlist of a parent task again and enqueues the
+// entries without a child task, matched by URL or extractor ID. Entries the
+// user deleted are skipped. It returns the number of added tasks.
+func (m *Manager) SyncPlaylist(id string) (int, error) {
+	m.syncMu.Lock()
+	defer m.syncMu.Unlock()
+
+	parent, err := m.getTask(id)
+	if err != nil {
+		return 0, err
+	}
+	if parent.SourceType != schema.SourceTypePlaylist || parent.ParentID != "" {
+		return 0, fmt.Errorf("task is not a playlist")
+	}
+
+	// A failed sync also waits for the next interval
+	parent.LastSyncedAt = time.Now().Unix()
+	m.saveTask(parent)
+	m.emitTaskUpdate(parent)
+
+	m.emitTaskLog(parent.ID, "正在同步播放列表...", false)
+	info, err := m.deps.GetPlaylistInfo(parent.URL)
+	if err != nil {
+		m.emitTaskLog(parent.ID, "同步播放列表失败: "+err.Error(), false)
+		return 0, err
+	}
+
+	children, err := m.getTasksByParentID(parent.ID)
+	if err != nil {
+		return 0, err
+	}
+	known := make(map[string]bool)
+	for _, child := range children {
+		for _, key := range playlistEntryKeys(child.URL, child.ExternalID) {
+			known[key] = true
+		}
+	}
+	for _, key := range strings.Fields(parent.RemovedEntries) {
+		known[key] = true
+	}
+
+	var added []*schema.Task
+	queueOrder := time.Now().UnixMilli()
+	for _, item := range info.PlaylistItems {
+		keys := playlistEntryKeys(item.URL, item.ID)
+		if item.URL == "" || slices.ContainsFunc(keys, func(k string) bool { return known[k] }) {
+			continue
+		}
+		child := newPlaylistChild(parent, item, queueOrder+int64(len(added)))
+		m.registerCancel(child.ID)
+		added = append(added, child)
+	}
+
+	for _, child := range added {
+		m.saveTask(child)
+		m.emitTaskUpdate(child)
+	}
+	m.emitTaskLog(parent.ID, fmt.Sprintf("播放列表同步完成，新增 %d 项", len(added)), false)
+
+	if len(added) > 0 {
+		go m.scheduleTasks()
+	}
+	return len(added), nil
+}
+
+// SetPlaylistSyncInterval sets the minutes between automatic syncs of a
+// playlist, 0 turns them off.
+func (m *Manager) SetPlaylistSyncInterval(id string, minutes int) error {
+	if minutes < 0 {
+		return fmt.Errorf("invalid sync interval: %d", minutes)
+	}
+	parent, err := m.getTask(id)
+	if err != nil {
+		return err
+	}
+	if parent.SourceType != schema.SourceTypePlaylist || parent.ParentID != "" {
+		return fmt.Errorf("task is not a playlist")
+	}
+	parent.SyncInterval = minutes
+	m.saveTask(parent)
+	m.emitTaskUpdate(parent)
+	return nil
+}
+
+// StartPlaylistSync syncs playlists with an interval once it has elapsed.
+func (m *Manager) StartPlaylistSync() {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.ctx.Done():
+				return
+			case <-ticker.C:
+				m.syncDuePlaylists()
+			}
+		}
+	}()
+}
+
+func (m *Manager) syncDuePlaylists() {
+	if m.taskDAL == nil {
+		return
+	}
+	due, err := m.taskDAL.ListDueSyncs(m.ctx, time.Now().Unix())
+	if err != nil {
+		fmt.Printf("Failed to list playlists to sync: %v\n", err)
+		return
+	}
+	for _, parent := range due {
+		// Sync sequentially to avoid network spikes
+		if _, err := m.SyncPlaylist(parent.ID); err != nil {
+			fmt.Printf("Failed to sync playlist %s: %v\n", parent.Title, err)
+		}
+	}
+}
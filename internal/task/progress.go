@@ -0,0 +1,156 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"Kairo/internal/db/schema"
+	"Kairo/internal/utils"
+)
+
+// Prefixes of the machine readable lines yt-dlp prints for us, see
+// progressArgs.
+const (
+	progressPrefix    = "[kairo:progress] "
+	postprocessPrefix = "[kairo:postprocess] "
+)
+
+// ytDlpProgress is the progress hook dictionary yt-dlp dumps with %(progress)j.
+// Sizes, speed and ETA are null while unknown.
+type ytDlpProgress struct {
+	Status             string   `json:"status"`
+	Filename           string   `json:"filename"`
+	DownloadedBytes    float64  `json:"downloaded_bytes"`
+	TotalBytes         float64  `json:"total_bytes"`
+	TotalBytesEstimate float64  `json:"total_bytes_estimate"`
+	Speed              *float64 `json:"speed"`
+	Eta                *float64 `json:"eta"`
+	FragmentIndex      int      `json:"fragment_index"`
+	FragmentCount      int      `json:"fragment_count"`
+	Postprocessor      string   `json:"postprocessor"`
+}
+
+// progressArgs asks yt-dlp for JSON progress lines and for the final path of
+// every downloaded file, written to pathFile.
+func progressArgs(pathFile string) []string {
+	return []string{
+		"--progress-template", "download:" + progressPrefix + "%(progress)j",
+		"--progress-template", "postprocess:" + postprocessPrefix + "%(progress)j",
+		"--print-to-file", "after_move:filepath", pathFile,
+	}
+}
+
+func parseProgressLine(line, prefix string) (*ytDlpProgress, bool) {
+	data, ok := strings.CutPrefix(line, prefix)
+	if !ok {
+		return nil, false
+	}
+	var p ytDlpProgress
+	if err := json.Unmarshal([]byte(data), &p); err != nil {
+		return nil, false
+	}
+	return &p, true
+}
+
+// progressTracker sums the progress of the files of one download, video and
+// audio are fetched as separate files before they are merged.
+type progressTracker struct {
+	files map[string]*fileProgress
+}
+
+type fileProgress struct {
+	downloaded int64
+	total      int64
+}
+
+func newProgressTracker() *progressTracker {
+	return &progressTracker{files: make(map[string]*fileProgress)}
+}
+
+// apply records p and updates the numeric progress fields of task.
+func (t *progressTracker) apply(task *schema.Task, p *ytDlpProgress) {
+	f := t.files[p.Filename]
+	if f == nil {
+		f = &fileProgress{}
+		t.files[p.Filename] = f
+	}
+	f.downloaded = int64(p.DownloadedBytes)
+	switch {
+	case p.TotalBytes > 0:
+		f.total = int64(p.TotalBytes)
+	case p.TotalBytesEstimate > 0:
+		f.total = int64(p.TotalBytesEstimate)
+	}
+	if p.Status == "finished" {
+		f.downloaded = max(f.downloaded, f.total)
+		f.total = f.downloaded
+	}
+
+	var downloaded, total int64
+	for _, f := range t.files {
+		downloaded += f.downloaded
+		total += f.total
+	}
+
+	task.DownloadedBytes = downloaded
+	task.FragmentIndex = p.FragmentIndex
+	task.FragmentCount = p.FragmentCount
+	task.SpeedBps = 0
+	task.EtaSeconds = 0
+	if p.Status == "downloading" {
+		if p.Speed != nil {
+			task.SpeedBps = *p.Speed
+		}
+		if p.Eta != nil {
+			task.EtaSeconds = int64(*p.Eta)
+		}
+	}
+	if p.Filename != "" {
+		task.FilePath = p.Filename
+	}
+
+	var progress float64
+	switch {
+	case task.TotalBytes > 0:
+		progress = float64(downloaded) / float64(task.TotalBytes) * 100
+	case total > 0:
+		progress = float64(downloaded) / float64(total) * 100
+	case p.FragmentCount > 0:
+		progress = float64(p.FragmentIndex) / float64(p.FragmentCount) * 100
+	}
+	task.Progress = min(progress, 100)
+}
+
+// progressLog renders p for the task log in the shape of yt-dlp's own
+// progress line, so the log view keeps replacing it in place.
+func progressLog(task *schema.Task, p *ytDlpProgress) string {
+	if p.Status == "finished" {
+		return fmt.Sprintf("[download] 100%% of %s", utils.FormatBytes(int64(max(p.DownloadedBytes, p.TotalBytes))))
+	}
+	line := fmt.Sprintf("[download] %5.1f%%", task.Progress)
+	if task.TotalBytes > 0 {
+		line += " of " + utils.FormatBytes(task.TotalBytes)
+	}
+	if task.SpeedBps > 0 {
+		line += " at " + utils.FormatBytes(int64(task.SpeedBps)) + "/s"
+	}
+	if p.Eta != nil {
+		line += " ETA " + utils.FormatDuration(float64(task.EtaSeconds))
+	}
+	if p.FragmentCount > 0 {
+		line += fmt.Sprintf(" (frag %d/%d)", p.FragmentIndex, p.FragmentCount)
+	}
+	return line
+}
+
+// readFinalPath returns the last path yt-dlp wrote to pathFile.
+func readFinalPath(pathFile string) string {
+	data, err := os.ReadFile(pathFile)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
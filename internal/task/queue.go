@@ -0,0 +1,104 @@
+package task
+
+import (
+	"fmt"
+
+	"Kairo/internal/db/schema"
+)
+
+func validatePriority(priority int) error {
+	if priority < schema.TaskPriorityLow || priority > schema.TaskPriorityUrgent {
+		return fmt.Errorf("priority must be between %d and %d", schema.TaskPriorityLow, schema.TaskPriorityUrgent)
+	}
+	return nil
+}
+
+// GetQueue returns the pending tasks in the order they will be started.
+func (m *Manager) GetQueue() ([]schema.Task, error) {
+	if m.taskDAL == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return m.taskDAL.ListQueue(m.ctx)
+}
+
+func (m *Manager) SetTaskPriority(id string, priority int) error {
+	if err := validatePriority(priority); err != nil {
+		return err
+	}
+	task, err := m.getTask(id)
+	if err != nil {
+		return err
+	}
+	task.Priority = priority
+	m.saveTask(task)
+	m.emitTaskUpdate(task)
+
+	go m.scheduleTasks()
+	return nil
+}
+
+// MoveTask reorders a pending task. A task moved past tasks of another
+// priority takes over their priority, so the queue stays sorted.
+func (m *Manager) MoveTask(id string, move schema.TaskMove) error {
+	if m.taskDAL == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	m.mu.Lock()
+	queue, err := m.taskDAL.ListQueue(m.ctx)
+	if err != nil {
+		m.mu.Unlock()
+		return err
+	}
+	index := -1
+	for i := range queue {
+		if queue[i].ID == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		m.mu.Unlock()
+		return fmt.Errorf("task is not pending")
+	}
+
+	task := queue[index]
+	target := index
+	switch move {
+	case schema.TaskMoveUp:
+		if index > 0 {
+			target = index - 1
+			task.Priority = queue[target].Priority
+		}
+	case schema.TaskMoveDown:
+		if index < len(queue)-1 {
+			target = index + 1
+			task.Priority = queue[target].Priority
+		}
+	case schema.TaskMoveTop:
+		target = 0
+		task.Priority = max(task.Priority, queue[0].Priority)
+	case schema.TaskMoveBottom:
+		target = len(queue) - 1
+		task.Priority = min(task.Priority, queue[target].Priority)
+	default:
+		m.mu.Unlock()
+		return fmt.Errorf("unknown move %q", move)
+	}
+
+	queue = append(queue[:index], queue[index+1:]...)
+	queue = append(queue[:target], append([]schema.Task{task}, queue[target:]...)...)
+	for i := range queue {
+		queue[i].QueueOrder = int64(i)
+	}
+	err = m.taskDAL.SaveQueue(m.ctx, queue)
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for i := range queue {
+		m.emitTaskUpdate(&queue[i])
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"Kairo/internal/config"
+	"Kairo/internal/db/schema"
+)
+
+// errorPatterns maps lowercase fragments of yt-dlp error output to an error
+// kind. Order matters, the first match wins.
+var errorPatterns = []struct {
+	kind     schema.TaskErrorKind
+	patterns []string
+}{
+	{schema.TaskErrorRateLimited, []string{"http error 429", "too many requests", "rate-limit", "rate limit"}},
+	{schema.TaskErrorGeoBlocked, []string{"available in your country", "geo restrict", "geo-restrict", "available from your location"}},
+	{schema.TaskErrorLoginRequired, []string{"sign in to confirm", "login required", "requires authentication", "members-only", "use --cookies", "--cookies-from-browser", "account cookies"}},
+	{schema.TaskErrorForbidden, []string{"http error 403", "403 forbidden", "forbidden"}},
+	{schema.TaskErrorFormatUnavailable, []string{"requested format is not available", "no video formats found"}},
+	{schema.TaskErrorUnavailable, []string{"video unavailable", "has been removed", "private video", "http error 404", "does not exist", "account has been terminated", "this video is no longer available", "copyright"}},
+	{schema.TaskErrorUnsupported, []string{"unsupported url", "is not a valid url"}},
+	{schema.TaskErrorNetwork, []string{"connection reset", "timed out", "temporary failure in name resolution", "name or service not known", "network is unreachable", "connection refused", "remote end closed connection", "incompleteread", "eof occurred", "urlopen error", "http error 5", "unable to download webpage", "unable to download video data"}},
+}
+
+// defaultRetryPolicies are used for kinds without an override in settings.
+// Kinds without a policy are never retried.
+var defaultRetryPolicies = map[schema.TaskErrorKind]config.RetryPolicy{
+	schema.TaskErrorRateLimited:       {MaxAttempts: 5, BaseDelay: 60, MaxDelay: 1800, CookieFallback: true},
+	schema.TaskErrorForbidden:         {MaxAttempts: 3, BaseDelay: 30, MaxDelay: 600, CookieFallback: true},
+	schema.TaskErrorNetwork:           {MaxAttempts: 5, BaseDelay: 10, MaxDelay: 600},
+	schema.TaskErrorGeoBlocked:        {MaxAttempts: 2, BaseDelay: 10, MaxDelay: 60, ProxyFallback: true},
+	schema.TaskErrorLoginRequired:     {MaxAttempts: 2, BaseDelay: 10, MaxDelay: 60, CookieFallback: true},
+	schema.TaskErrorFormatUnavailable: {MaxAttempts: 2, BaseDelay: 5, MaxDelay: 5},
+	schema.TaskErrorUnknown:           {MaxAttempts: 2, BaseDelay: 30, MaxDelay: 300},
+}
+
+// classifyError returns the error kind and the line that explains it best.
+func classifyError(lines []string) (schema.TaskErrorKind, string) {
+	var errorLines []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "ERROR:") {
+			errorLines = append(errorLines, line)
+		}
+	}
+	if len(errorLines) == 0 {
+		errorLines = lines
+	}
+
+	for _, group := range errorPatterns {
+		for _, line := range errorLines {
+			lower := strings.ToLower(line)
+			for _, pattern := range group.patterns {
+				if strings.Contains(lower, pattern) {
+					return group.kind, strings.TrimSpace(line)
+				}
+			}
+		}
+	}
+
+	message := ""
+	for i := len(errorLines) - 1; i >= 0 && message == ""; i-- {
+		message = strings.TrimSpace(errorLines[i])
+	}
+	return schema.TaskErrorUnknown, message
+}
+
+func retryPolicy(kind schema.TaskErrorKind) (config.RetryPolicy, bool) {
+	if policy, ok := config.GetRetryPolicy(string(kind)); ok {
+		return policy, policy.MaxAttempts > 1
+	}
+	policy, ok := defaultRetryPolicies[kind]
+	return policy, ok
+}
+
+func retryDelay(policy config.RetryPolicy, attempt int) time.Duration {
+	delay := time.Duration(policy.BaseDelay) * time.Second
+	if delay <= 0 {
+		delay = 10 * time.Second
+	}
+	maxDelay := time.Duration(policy.MaxDelay) * time.Second
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if maxDelay > 0 && delay >= maxDelay {
+			return maxDelay
+		}
+	}
+	return delay
+}
+
+// failTask records why the attempt failed and either schedules another
+// attempt or marks the task as failed for good.
+func (m *Manager) failTask(task *schema.Task, kind schema.TaskErrorKind, message string) {
+	task.ErrorKind = kind
+	task.ErrorMessage = message
+	task.Attempts++
+	task.SpeedBps = 0
+	task.EtaSeconds = 0
+
+	if policy, ok := retryPolicy(kind); ok && task.Attempts < policy.MaxAttempts {
+		delay := retryDelay(policy, task.Attempts)
+		if policy.CookieFallback && len(config.ResolveNetworkOptions(task.URL).CookieArgs(true)) > 0 {
+			task.FallbackCookies = true
+		}
+		if policy.ProxyFallback && config.GetFallbackProxy() != "" {
+			task.FallbackProxy = true
+		}
+		if kind == schema.TaskErrorFormatUnavailable {
+			// Let yt-dlp pick whatever is available instead of the resolved format
+			task.FormatID = ""
+			task.Quality = "best"
+		}
+		task.Status = schema.TaskStatusPending
+		task.NextRetryAt = time.Now().Add(delay).Unix()
+		m.emitTaskLog(task.ID, fmt.Sprintf("下载失败 (%s)，将在 %s 后重试 (%d/%d)", kind, delay, task.Attempts+1, policy.MaxAttempts), false)
+		m.emitTaskUpdate(task)
+		m.saveTask(task)
+		m.mu.Lock()
+		m.armRetryTimer()
+		m.mu.Unlock()
+		return
+	}
+
+	task.Status = schema.TaskStatusError
+	task.NextRetryAt = 0
+	m.emitTaskLog(task.ID, fmt.Sprintf("下载失败 (%s): %s", kind, message), false)
+	m.emitTaskUpdate(task)
+	m.saveTask(task)
+	if m.OnTaskFailed != nil {
+		go m.OnTaskFailed(task)
+	}
+}
+
+// resetRetryState clears the retry bookkeeping before a manual retry.
+func resetRetryState(task *schema.Task) {
+	task.ErrorKind = ""
+	task.ErrorMessage = ""
+	task.Attempts = 0
+	task.NextRetryAt = 0
+	task.FallbackCookies = false
+	task.FallbackProxy = false
+}
+
+// armRetryTimer wakes the scheduler when the earliest waiting retry is due.
+// Callers must hold m.mu.
+func (m *Manager) armRetryTimer() {
+	if m.taskDAL == nil {
+		return
+	}
+	next, err := m.taskDAL.NextRetryAt(m.ctx, time.Now().Unix())
+	if err != nil || next == 0 {
+		return
+	}
+	m.wakeAt(time.Unix(next, 0))
+}
@@ -9,7 +9,6 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -76,8 +75,12 @@ func (m *Manager) getFormatString(task *schema.Task) string {
 	return format
 }
 
-func (m *Manager) buildYtDlpArgs(task *schema.Task, outputDir, ffmpegPath string) []string {
+func (m *Manager) buildYtDlpArgs(task *schema.Task, outputDir, ffmpegPath, pathFile string) []string {
 	format := m.getFormatString(task)
+	outputTemplate := "%(title)s.%(ext)s"
+	if task.OutputTemplate != "" {
+		outputTemplate = task.OutputTemplate
+	}
 
 	args := []string{
 		"--newline",
@@ -85,34 +88,27 @@ func (m *Manager) buildYtDlpArgs(task *schema.Task, outputDir, ffmpegPath string
 		"--convert-thumbnails", "jpg",
 		"--encoding", "utf-8",
 		"--ffmpeg-location", filepath.Dir(ffmpegPath),
-		"-o", "%(title)s.%(ext)s",
+		"-o", outputTemplate,
 		"-P", outputDir,
 		"-f", format,
 	}
+	args = append(args, progressArgs(pathFile)...)
 
 	if limit := config.GetDownloadRateLimit(); limit != "" {
 		args = append(args, "-r", limit)
 	}
+	args = append(args, pacingArgs(utils.GetSiteKey(task.URL))...)
 
-	if proxy := config.GetProxyUrl(); proxy != "" {
-		args = append(args, "--proxy", proxy)
-	}
-
-	if ua := config.GetUserAgent(); ua != "" {
-		args = append(args, "--user-agent", ua)
-	}
-	if ref := config.GetReferer(); ref != "" {
-		args = append(args, "--referer", ref)
-	}
-	if config.GetGeoBypass() {
-		args = append(args, "--geo-bypass")
-	} else {
-		args = append(args, "--no-geo-bypass")
+	netOpts := config.ResolveNetworkOptions(task.URL)
+	if task.FallbackProxy {
+		if fallback := config.GetFallbackProxy(); fallback != "" {
+			netOpts.Proxy = fallback
+		}
 	}
-
-	if cookieArgs := config.GetCookieArgs(); len(cookieArgs) > 0 {
-		args = append(args, cookieArgs...)
+	if task.FallbackCookies {
+		netOpts.Cookie.Enabled = true
 	}
+	args = append(args, netOpts.Args()...)
 
 	if task.Format != "original" {
 		args = append(args, "--merge-output-format", task.Format)
@@ -218,56 +214,70 @@ func (m *Manager) processTask(ctx context.Context, task *schema.Task) {
 		if err != nil || t == nil {
 			return false
 		}
-		return t.Status == schema.TaskStatusPaused
+		return t.Status == schema.TaskStatusPaused || t.Status == schema.TaskStatusCancelled
 	}
 
 	lastSave := time.Now()
 	throttledSave := func() {
 		if time.Since(lastSave) > 1*time.Second {
-			m.saveTask(task)
+			m.saveProgress(task)
 			lastSave = time.Now()
 		}
 	}
 
+	if task.EnclosureURL != "" {
+		m.processEnclosure(ctx, task, checkPaused)
+		return
+	}
+
 	ytDlpPath, err := m.deps.GetYtDlpPath()
 	if err != nil {
-		task.Status = schema.TaskStatusError
 		m.emitTaskLog(task.ID, "Error: yt-dlp not found", false)
-		m.emitTaskUpdate(task)
-		m.saveTask(task)
+		m.failTask(task, schema.TaskErrorInternal, "yt-dlp not found")
 		return
 	}
 
+	if task.Attempts > 0 {
+		m.emitTaskLog(task.ID, fmt.Sprintf("第 %d 次重试 (上次失败原因: %s)", task.Attempts, task.ErrorKind), false)
+	}
+
 	// Update status (local copy) and save to DB
 	// Note: We don't need to lock for local task updates as we own this struct instance
 	task.Status = schema.TaskStatusStarting
-	task.Speed = "启动中..."
-	task.Eta = ""
+	task.SpeedBps = 0
+	task.EtaSeconds = 0
 
 	m.emitTaskLog(task.ID, "正在启动下载引擎...", false)
 	m.emitTaskUpdate(task)
 	m.saveTask(task)
 
 	if err := m.resolveVideoInfo(task); err != nil {
-		task.Status = schema.TaskStatusError
 		m.emitTaskLog(task.ID, err.Error(), false)
-		m.emitTaskUpdate(task)
-		m.saveTask(task)
+		kind, message := classifyError(strings.Split(err.Error(), "\n"))
+		m.failTask(task, kind, message)
 		return
 	}
 	// Save updated task (with potential new title/format info)
 	m.saveTask(task)
 	m.emitTaskUpdate(task)
 
-	// Create output directory based on title
-	sanitizedTitle := utils.SanitizeFileName(task.Title)
-	outputDir := filepath.Join(task.Dir, sanitizedTitle)
+	// Create output directory based on title, templated tasks lay out
+	// their own subdirectories below task.Dir
+	outputDir := task.Dir
+	if task.OutputTemplate == "" {
+		outputDir = filepath.Join(task.Dir, utils.SanitizeFileName(task.Title))
+	}
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		m.emitTaskLog(task.ID, "Failed to create directory: "+err.Error(), false)
 	}
 
+	// yt-dlp reports the final path of each file here once it is moved in place
+	pathFile := filepath.Join(os.TempDir(), "kairo-"+task.ID+".paths")
+	os.Remove(pathFile)
+	defer os.Remove(pathFile)
+
 	ffmpegPath, _ := m.deps.GetFFmpegPath()
-	args := m.buildYtDlpArgs(task, outputDir, ffmpegPath)
+	args := m.buildYtDlpArgs(task, outputDir, ffmpegPath, pathFile)
 
 	cmd := utils.CreateCommandContext(ctx, ytDlpPath, args...)
 
@@ -281,10 +291,8 @@ func (m *Manager) processTask(ctx context.Context, task *schema.Task) {
 			return
 		}
 
-		task.Status = schema.TaskStatusError
 		m.emitTaskLog(task.ID, "Start Error: "+err.Error(), false)
-		m.emitTaskUpdate(task)
-		m.saveTask(task)
+		m.failTask(task, schema.TaskErrorInternal, err.Error())
 		return
 	}
 
@@ -293,128 +301,61 @@ func (m *Manager) processTask(ctx context.Context, task *schema.Task) {
 	m.emitTaskUpdate(task)
 	m.saveTask(task)
 
-	// Regex for progress
-	progressRegex := regexp.MustCompile(`\[download\]\s+(\d+\.?\d*)%\s+of\s+([~\d\.\w]+)(?:\s+at\s+([~\d\.\w/]+)\s+ETA\s+([\d:]+))?`)
-	destinationRegex := regexp.MustCompile(`\[download\] Destination: (.+)`)
-	alreadyDownloadedRegex := regexp.MustCompile(`\[download\] (.+) has already been downloaded`)
-	mergerRegex := regexp.MustCompile(`\[Merger\] Merging formats into "(.+)"`)
 	deletingRegex := regexp.MustCompile(`Deleting original file (.+?)(?: \(pass -k to keep\))?$`)
 
 	var wg sync.WaitGroup
 	wg.Add(2)
 
-	// Progress tracking
-	var completedBytes int64
-	var currentPartBytes int64
-	var currentPartDownloaded int64
-	var currentPartLogged bool
+	tracker := newProgressTracker()
+	task.DownloadedBytes = 0
+	task.FragmentIndex = 0
+	task.FragmentCount = 0
 
-	// Stdout reader
+	// Stdout reader, the only goroutine that touches task until both exit
 	go func() {
 		defer wg.Done()
 		sc := bufio.NewScanner(stdout)
 		for sc.Scan() {
 			line := sc.Text()
-			isProgress := strings.HasPrefix(line, "[download]") && strings.Contains(line, "%")
-			m.emitTaskLog(task.ID, line, isProgress)
-
-			// Parse already downloaded
-			if matches := alreadyDownloadedRegex.FindStringSubmatch(line); len(matches) > 1 {
-				// File exists, try to get size
-				filename := matches[1]
-				path := utils.NormalizePath(outputDir, filename)
-				if info, err := os.Stat(path); err == nil {
-					completedBytes += info.Size()
-				}
 
-				// Re-calculate progress?
-				if task.TotalBytes > 0 {
-					p := float64(completedBytes+currentPartDownloaded) / float64(task.TotalBytes) * 100
-					if p > 100 {
-						p = 100
-					}
-					task.Progress = p
-				}
+			if p, ok := parseProgressLine(line, progressPrefix); ok {
+				tracker.apply(task, p)
+				m.emitTaskLog(task.ID, progressLog(task, p), p.Status == "downloading")
 				m.emitTaskUpdate(task)
 				throttledSave()
+				continue
 			}
 
-			// Parse Destination (new part starting)
-			if matches := destinationRegex.FindStringSubmatch(line); len(matches) > 1 {
-				if currentPartBytes > 0 {
-					completedBytes += currentPartBytes
+			if p, ok := parseProgressLine(line, postprocessPrefix); ok {
+				if p.Status == "started" && p.Postprocessor == "Merger" && task.Status != schema.TaskStatusMerging {
+					task.Status = schema.TaskStatusMerging
+					task.SpeedBps = 0
+					task.EtaSeconds = 0
+					m.emitTaskUpdate(task)
+					m.saveTask(task)
 				}
-				currentPartBytes = 0
-				currentPartDownloaded = 0
-				currentPartLogged = false
-
-				task.FilePath = matches[1]
-			}
-
-			if matches := mergerRegex.FindStringSubmatch(line); len(matches) > 1 {
-				mergedPath := matches[1]
-				task.FilePath = mergedPath
+				continue
 			}
 
+			m.emitTaskLog(task.ID, line, false)
 			if matches := deletingRegex.FindStringSubmatch(line); len(matches) > 1 {
 				m.emitTaskUpdate(task)
 			}
-
-			if strings.HasPrefix(line, "[Merger]") && task.Status != schema.TaskStatusMerging {
-				task.Status = schema.TaskStatusMerging
-				m.emitTaskUpdate(task)
-				m.saveTask(task)
-			}
-
-			// Parse progress
-			if matches := progressRegex.FindStringSubmatch(line); len(matches) > 2 {
-				percent, _ := strconv.ParseFloat(matches[1], 64)
-				sizeStr := matches[2]
-				speed := matches[3]
-				eta := matches[4]
-
-				task.Speed = speed
-				task.Eta = eta
-
-				// Parse size
-				size, _ := utils.ParseSize(sizeStr)
-				if size > 0 {
-					currentPartBytes = int64(size)
-					currentPartDownloaded = int64(percent / 100 * size)
-				}
-
-				// Calculate total progress
-				if task.TotalBytes > 0 {
-					totalDownloaded := completedBytes + currentPartDownloaded
-					p := float64(totalDownloaded) / float64(task.TotalBytes) * 100
-					if p > 100 {
-						p = 100
-					}
-					task.Progress = p
-				} else {
-					task.Progress = percent
-				}
-
-				if percent >= 100 && !currentPartLogged {
-					m.emitTaskLog(task.ID, line, false)
-					currentPartLogged = true
-				}
-
-				m.emitTaskUpdate(task)
-				throttledSave()
-			}
 		}
 	}()
 
-	// Stderr reader
+	// Stderr reader, the tail is kept to classify failures. It only reads
+	// task.ID so it never races with the progress updates above.
+	var stderrTail []string
 	go func() {
 		defer wg.Done()
 		sc := bufio.NewScanner(stderr)
 		for sc.Scan() {
 			line := sc.Text()
 			m.emitTaskLog(task.ID, line, false)
-			if matches := deletingRegex.FindStringSubmatch(line); len(matches) > 1 {
-				m.emitTaskUpdate(task)
+			stderrTail = append(stderrTail, line)
+			if len(stderrTail) > 50 {
+				stderrTail = stderrTail[1:]
 			}
 		}
 	}()
@@ -444,17 +385,24 @@ func (m *Manager) processTask(ctx context.Context, task *schema.Task) {
 				return
 			}
 
-			task.Status = schema.TaskStatusError
 			m.emitTaskLog(task.ID, "Exit Error: "+waitErr.Error(), false)
-			m.saveTask(task)
-			if m.OnTaskFailed != nil {
-				m.emitTaskUpdate(task)
-				go m.OnTaskFailed(task)
+			kind, message := classifyError(stderrTail)
+			if message == "" {
+				message = waitErr.Error()
 			}
+			m.failTask(task, kind, message)
 		}
 	} else {
 		task.Progress = 100
+		task.SpeedBps = 0
+		task.EtaSeconds = 0
 		task.FileExists = false
+		if path := readFinalPath(pathFile); path != "" {
+			task.FilePath = path
+		}
+		task.ErrorKind = ""
+		task.ErrorMessage = ""
+		task.NextRetryAt = 0
 
 		if !task.FileExists && task.FilePath != "" {
 			if _, statErr := os.Stat(task.FilePath); statErr == nil {
@@ -2,19 +2,23 @@ package task
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"Kairo/internal/config"
 	"Kairo/internal/db/schema"
+	"Kairo/internal/utils"
 
 	"github.com/google/uuid"
 )
 
-func (m *Manager) getPendingTasks(limit int) ([]*schema.Task, error) {
+func (m *Manager) getPendingTasks(offset, limit int) ([]*schema.Task, error) {
 	if m.taskDAL == nil {
 		return nil, fmt.Errorf("database not initialized")
 	}
-	rows, err := m.taskDAL.ListPending(m.ctx, limit)
+	rows, err := m.taskDAL.ListPending(m.ctx, time.Now().Unix(), offset, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -52,6 +56,7 @@ func newTask(url, dir, title, thumbnail string, sourceType schema.SourceType, ca
 		Thumbnail:  thumbnail,
 		SourceType: sourceType,
 		Status:     schema.TaskStatusPending,
+		QueueOrder: time.Now().UnixMilli(),
 		CreatedAt:  now,
 		UpdatedAt:  now,
 		LogPath:    config.GetLogPath(id),
@@ -59,3 +64,40 @@ func newTask(url, dir, title, thumbnail string, sourceType schema.SourceType, ca
 		CategoryID: categoryID,
 	}
 }
+
+// renderOutputTemplate returns an empty template when none is configured so
+// the task keeps the default per-title folder layout.
+func renderOutputTemplate(tpl string, vars utils.OutputTemplateVars) (string, error) {
+	if strings.TrimSpace(tpl) == "" {
+		return "", nil
+	}
+	return utils.RenderOutputTemplate(tpl, vars)
+}
+
+// removeTemplatedOutput deletes the downloaded file and its sidecars (same
+// base name, e.g. thumbnails) and then prunes directories left empty, never
+// going above the task root directory.
+func removeTemplatedOutput(rootDir, filePath string) {
+	dir := filepath.Dir(filePath)
+	stem := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	if entries, err := os.ReadDir(dir); err == nil {
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			name := e.Name()
+			if name == stem || strings.HasPrefix(name, stem+".") || strings.HasPrefix(name, stem+"_trimmed.") {
+				_ = os.Remove(filepath.Join(dir, name))
+			}
+		}
+	}
+
+	root := filepath.Clean(rootDir)
+	for dir != root && strings.HasPrefix(dir, root+string(filepath.Separator)) {
+		if err := os.Remove(dir); err != nil {
+			// Not empty or not removable
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var ErrInsufficientSpace = errors.New("insufficient disk space")
+
+// DiskFree returns the bytes available to the user on the volume of path.
+// Missing directories are resolved to their closest existing parent.
+func DiskFree(path string) (int64, error) {
+	path, err := existingParent(path)
+	if err != nil {
+		return 0, err
+	}
+	return diskFree(path)
+}
+
+// DiskVolume identifies the volume holding path, so directories on the same
+// disk share one free space budget. Missing directories are resolved to
+// their closest existing parent.
+func DiskVolume(path string) (string, error) {
+	path, err := existingParent(path)
+	if err != nil {
+		return "", err
+	}
+	return diskVolume(path)
+}
+
+func existingParent(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return "", fmt.Errorf("no existing parent for %s", path)
+		}
+		path = parent
+	}
+}
+
+// EnsureFreeSpace fails with ErrInsufficientSpace when writing need bytes to
+// dir would leave less than reserve bytes free. An unknown free space does
+// not block the caller.
+func EnsureFreeSpace(dir string, need, reserve int64) error {
+	free, err := DiskFree(dir)
+	if err != nil {
+		return nil
+	}
+	if free-need < reserve {
+		return fmt.Errorf("%w: %s free, %s required in %s", ErrInsufficientSpace, FormatBytes(free), FormatBytes(need+reserve), dir)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// OutputTemplateVars carries the values that are known when a task is
+// created and therefore rendered statically into the yt-dlp template.
+type OutputTemplateVars struct {
+	Feed          string
+	Playlist      string
+	PlaylistIndex int
+}
+
+// Placeholders resolved by yt-dlp at download time. The S conversion has
+// yt-dlp sanitize the value as a file name, so a "/" or ".." in the metadata
+// cannot add directories or leave the task directory.
+var dynamicPlaceholders = map[string]string{
+	"title":        "%(title)S",
+	"id":           "%(id)s",
+	"uploader":     "%(uploader,channel|Unknown)S",
+	"channel":      "%(channel,uploader|Unknown)S",
+	"upload_date":  "%(upload_date|Unknown)S",
+	"upload_year":  "%(upload_date>%Y|Unknown)S",
+	"upload_month": "%(upload_date>%m|Unknown)S",
+	"ext":          "%(ext)s",
+}
+
+// rawFieldConversions are the conversions allowed on raw yt-dlp fields,
+// the numeric ones cannot produce a path separator.
+const rawFieldConversions = "sSdif"
+
+// RenderOutputTemplate converts a Kairo filename template such as
+// "{uploader}/{upload_date} - {title}" into a yt-dlp output template relative
+// to the task directory. "/" separates directories, {feed}, {playlist} and
+// {playlist_index} are filled from vars, and raw yt-dlp fields like
+// %(duration)s are passed through with string values sanitized. The extension is appended when missing
+// and " [%(id)s]" is added when the template has no {id}, so two items with
+// the same title never overwrite each other.
+func RenderOutputTemplate(tpl string, vars OutputTemplateVars) (string, error) {
+	tpl = strings.TrimSpace(strings.ReplaceAll(tpl, "\\", "/"))
+	if tpl == "" {
+		return "", fmt.Errorf("filename template is empty")
+	}
+	if strings.HasPrefix(tpl, "/") || filepath.VolumeName(tpl) != "" {
+		return "", fmt.Errorf("filename template must be a relative path")
+	}
+
+	static := map[string]string{
+		"feed":     vars.Feed,
+		"playlist": vars.Playlist,
+	}
+	if static["feed"] == "" {
+		static["feed"] = vars.Playlist
+	}
+	if static["playlist"] == "" {
+		static["playlist"] = vars.Feed
+	}
+	if vars.PlaylistIndex > 0 {
+		static["playlist_index"] = fmt.Sprintf("%03d", vars.PlaylistIndex)
+	} else {
+		static["playlist_index"] = "000"
+	}
+
+	parts := strings.Split(tpl, "/")
+	segments := make([]string, 0, len(parts))
+	for _, part := range parts {
+		seg, err := renderTemplateSegment(part, static)
+		if err != nil {
+			return "", err
+		}
+		seg = strings.Trim(strings.TrimSpace(seg), ".")
+		if seg == "" {
+			return "", fmt.Errorf("filename template contains an empty path segment")
+		}
+		segments = append(segments, seg)
+	}
+
+	last := segments[len(segments)-1]
+	if !strings.Contains(last, "%(ext)s") {
+		last += ".%(ext)s"
+	}
+	if !strings.Contains(strings.Join(segments, "/"), "%(id)s") {
+		if idx := strings.LastIndex(last, ".%(ext)s"); idx >= 0 {
+			last = last[:idx] + " [%(id)s]" + last[idx:]
+		} else {
+			last += " [%(id)s]"
+		}
+	}
+	segments[len(segments)-1] = last
+
+	return strings.Join(segments, "/"), nil
+}
+
+// ValidateOutputTemplate reports whether tpl can be rendered. An empty
+// template is valid and means the default layout.
+func ValidateOutputTemplate(tpl string) error {
+	if strings.TrimSpace(tpl) == "" {
+		return nil
+	}
+	_, err := RenderOutputTemplate(tpl, OutputTemplateVars{Feed: "feed", Playlist: "playlist", PlaylistIndex: 1})
+	return err
+}
+
+func renderTemplateSegment(part string, static map[string]string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(part); i++ {
+		c := part[i]
+		switch {
+		case c == '{':
+			end := strings.IndexByte(part[i:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unclosed placeholder in filename template")
+			}
+			name := strings.ToLower(strings.TrimSpace(part[i+1 : i+end]))
+			if v, ok := static[name]; ok {
+				if v == "" {
+					v = "Unknown"
+				}
+				b.WriteString(strings.ReplaceAll(SanitizeFileName(v), "%", "%%"))
+			} else if v, ok := dynamicPlaceholders[name]; ok {
+				b.WriteString(v)
+			} else {
+				return "", fmt.Errorf("unknown placeholder {%s} in filename template", name)
+			}
+			i += end
+		case c == '%' && i+1 < len(part) && part[i+1] == '(':
+			end := strings.IndexByte(part[i:], ')')
+			if end < 0 {
+				return "", fmt.Errorf("invalid yt-dlp field in filename template")
+			}
+			// Keep the field together with its flags, width and conversion
+			j := i + end + 1
+			for j < len(part) && strings.IndexByte("#0-+ .123456789", part[j]) >= 0 {
+				j++
+			}
+			if j >= len(part) {
+				return "", fmt.Errorf("invalid yt-dlp field in filename template")
+			}
+			field := part[i : j+1]
+			conv := part[j]
+			if strings.IndexByte(rawFieldConversions, conv) < 0 {
+				return "", fmt.Errorf("unsupported conversion in yt-dlp field %s", field)
+			}
+			if conv == 's' && field != "%(id)s" && field != "%(ext)s" {
+				field = field[:len(field)-1] + "S"
+			}
+			b.WriteString(field)
+			i = j
+		case c == '%':
+			b.WriteString("%%")
+		case strings.ContainsRune(`<>:"|?*`, rune(c)):
+			b.WriteByte('_')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	seg := b.String()
+	if seg == ".." {
+		return "", fmt.Errorf("filename template must not contain '..'")
+	}
+	return seg, nil
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"io"
 	"log"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -113,6 +114,32 @@ func GetSiteName(url string) string {
 	return "other"
 }
 
+// GetSiteKey returns the site name of known sites and the host otherwise,
+// used to group downloads by origin.
+func GetSiteKey(rawURL string) string {
+	if name := GetSiteName(rawURL); name != "other" {
+		return name
+	}
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Hostname() == "" {
+		return "other"
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// NormalizeSiteKey maps a configured site name, host or URL to the key
+// GetSiteKey produces, so "www.youtube.com" and "youtube" agree.
+func NormalizeSiteKey(key string) string {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if key == "" {
+		return ""
+	}
+	if !strings.Contains(key, "://") {
+		key = "https://" + key
+	}
+	return GetSiteKey(key)
+}
+
 func CreateCommand(name string, arg ...string) *exec.Cmd {
 	cmd := exec.Command(name, arg...)
 	HideWindow(cmd)
@@ -10,10 +10,11 @@ import (
 )
 
 func (m *Manager) InitAnalyzeQueue() {
-	m.analysisQueue = make(chan string, 100)
-	go m.resetProcessingVideoStatus()
-	go m.processAnalyzeQueue()
-	go m.enqueueUnanalyzedVideos()
+	go func() {
+		// Interrupted analyses are picked up again by their persisted jobs
+		m.resetProcessingVideoStatus()
+		m.enqueueUnanalyzedVideos()
+	}()
 }
 
 func (m *Manager) resetProcessingVideoStatus() {
@@ -23,27 +24,6 @@ func (m *Manager) resetProcessingVideoStatus() {
 	_ = m.videoDAL.UpdateStatusByStatus(m.ctx, "processing", "none")
 }
 
-func (m *Manager) processAnalyzeQueue() {
-	for videoID := range m.analysisQueue {
-		m.handleAnalyzeTask(videoID)
-	}
-}
-
-func (m *Manager) handleAnalyzeTask(videoID string) {
-	video, err := m.GetVideoById(videoID)
-	if err != nil {
-		return
-	}
-	status := strings.TrimSpace(video.Status)
-	if status != "" && status != "none" {
-		return
-	}
-	if _, err := m.getReadySubtitlePath(videoID); err != nil {
-		return
-	}
-	_ = m.AnalyzeVideo(videoID)
-}
-
 func (m *Manager) enqueueUnanalyzedVideos() {
 	if !config.GetSettings().AI.Enabled {
 		return
@@ -56,18 +36,29 @@ func (m *Manager) enqueueUnanalyzedVideos() {
 		return
 	}
 	for _, video := range videos {
-		if _, err := m.getReadySubtitlePath(video.ID); err != nil {
-			continue
-		}
 		m.enqueueAnalyze(video.ID)
 	}
 }
 
+// enqueueAnalyze schedules automatic analysis for a video that has not been
+// analyzed yet and has a usable subtitle. Videos processed by a recipe are
+// left to it.
 func (m *Manager) enqueueAnalyze(videoID string) {
-	if m.analysisQueue == nil {
+	if m.hasPipeline(videoID) {
+		return
+	}
+	video, err := m.GetVideoById(videoID)
+	if err != nil {
 		return
 	}
-	m.analysisQueue <- videoID
+	status := strings.TrimSpace(video.Status)
+	if status != "" && status != "none" {
+		return
+	}
+	if _, err := m.getReadySubtitlePath(videoID); err != nil {
+		return
+	}
+	_ = m.AnalyzeVideo(videoID)
 }
 
 func (m *Manager) getReadySubtitlePath(videoID string) (string, error) {
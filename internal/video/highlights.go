@@ -1,16 +1,18 @@
 package video
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"Kairo/internal/ai"
 	"Kairo/internal/db/schema"
+	"Kairo/internal/events"
 	"Kairo/internal/utils"
-
-	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 func (m *Manager) GetHighlights(videoID string) ([]schema.VideoHighlight, error) {
@@ -51,25 +53,10 @@ func (m *Manager) clearHighlightFiles(videoID string) error {
 	return m.highlightDAL.DeleteByVideoID(m.ctx, videoID)
 }
 
-func buildFallbackHighlights(candidates []energyCandidate) []struct {
-	Title       string `json:"title"`
-	Start       string `json:"start"`
-	End         string `json:"end"`
-	Description string `json:"description"`
-} {
-	highlights := make([]struct {
-		Title       string `json:"title"`
-		Start       string `json:"start"`
-		End         string `json:"end"`
-		Description string `json:"description"`
-	}, 0, len(candidates))
+func buildFallbackHighlights(candidates []energyCandidate) []ai.Highlight {
+	highlights := make([]ai.Highlight, 0, len(candidates))
 	for _, candidate := range candidates {
-		highlights = append(highlights, struct {
-			Title       string `json:"title"`
-			Start       string `json:"start"`
-			End         string `json:"end"`
-			Description string `json:"description"`
-		}{
+		highlights = append(highlights, ai.Highlight{
 			Title:       "Highlight",
 			Start:       formatTimestamp(candidate.Start, false),
 			End:         formatTimestamp(candidate.End, false),
@@ -84,19 +71,10 @@ type highlightRange struct {
 	End         float64
 	Title       string
 	Description string
+	Score       float64
 }
 
-func normalizeHighlights(highlights []struct {
-	Title       string `json:"title"`
-	Start       string `json:"start"`
-	End         string `json:"end"`
-	Description string `json:"description"`
-}, videoDuration float64, segments []subtitleSegment, candidates []energyCandidate) []struct {
-	Title       string `json:"title"`
-	Start       string `json:"start"`
-	End         string `json:"end"`
-	Description string `json:"description"`
-} {
+func normalizeHighlights(highlights []ai.Highlight, videoDuration float64, segments []subtitleSegment, candidates []energyCandidate) []ai.Highlight {
 	if len(highlights) == 0 {
 		return highlights
 	}
@@ -137,6 +115,7 @@ func normalizeHighlights(highlights []struct {
 			End:         end,
 			Title:       h.Title,
 			Description: h.Description,
+			Score:       h.Score,
 		})
 	}
 	if len(normalized) == 0 {
@@ -146,23 +125,14 @@ func normalizeHighlights(highlights []struct {
 		return normalized[i].Start < normalized[j].Start
 	})
 	merged := mergeHighlightRanges(normalized, 5.0)
-	final := make([]struct {
-		Title       string `json:"title"`
-		Start       string `json:"start"`
-		End         string `json:"end"`
-		Description string `json:"description"`
-	}, 0, len(merged))
+	final := make([]ai.Highlight, 0, len(merged))
 	for _, h := range merged {
-		final = append(final, struct {
-			Title       string `json:"title"`
-			Start       string `json:"start"`
-			End         string `json:"end"`
-			Description string `json:"description"`
-		}{
+		final = append(final, ai.Highlight{
 			Title:       h.Title,
 			Start:       formatTimestamp(h.Start, false),
 			End:         formatTimestamp(h.End, false),
 			Description: h.Description,
+			Score:       h.Score,
 		})
 	}
 	return final
@@ -211,6 +181,9 @@ func mergeHighlightRanges(items []highlightRange, gap float64) []highlightRange
 			if items[i].Title != "" && items[i].Title != last.Title {
 				last.Title = last.Title + " / " + items[i].Title
 			}
+			if items[i].Score > last.Score {
+				last.Score = items[i].Score
+			}
 			continue
 		}
 		merged = append(merged, items[i])
@@ -218,45 +191,82 @@ func mergeHighlightRanges(items []highlightRange, gap float64) []highlightRange
 	return merged
 }
 
-func (m *Manager) ClipHighlights(videoID string, highlights []schema.VideoHighlight) {
+// ClipHighlights cuts every stored highlight of the video into its own file.
+// Highlights that already have a clip on disk are skipped so retries only
+// redo the failed ones.
+func (m *Manager) ClipHighlights(ctx context.Context, videoID string, progress func(float64)) error {
+	return m.clipHighlights(ctx, videoID, 0, progress)
+}
+
+// clipHighlights clips the highlights scored at least minScore; 0 clips all.
+func (m *Manager) clipHighlights(ctx context.Context, videoID string, minScore float64, progress func(float64)) error {
 	v, err := m.GetVideoById(videoID)
 	if err != nil {
-		fmt.Printf("Failed to get video for clipping: %v\n", err)
-		return
+		return fmt.Errorf("failed to get video for clipping: %v", err)
+	}
+
+	highlights, err := m.GetHighlights(videoID)
+	if err != nil {
+		return err
 	}
 
 	ffmpegPath, err := m.deps.GetFFmpegPath()
 	if err != nil {
-		fmt.Printf("Failed to get ffmpeg: %v\n", err)
-		return
+		return fmt.Errorf("failed to get ffmpeg: %v", err)
 	}
 
 	pathInfo := buildVideoPathInfo(v.FilePath)
 
-	for _, h := range highlights {
+	var failed int
+	for i, h := range highlights {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if minScore > 0 && h.Score < minScore {
+			continue
+		}
+		if h.FilePath != "" {
+			if _, err := os.Stat(h.FilePath); err == nil {
+				progress(float64(i+1) / float64(len(highlights)) * 100)
+				continue
+			}
+		}
+
 		safeStart := strings.ReplaceAll(h.StartTime, ":", "-")
 		safeEnd := strings.ReplaceAll(h.EndTime, ":", "-")
 		outputName := fmt.Sprintf("%s_clip_%s_%s%s", pathInfo.BaseName, safeStart, safeEnd, pathInfo.Ext)
 		outputPath := filepath.Join(pathInfo.Dir, outputName)
 
+		var segment float64
+		start, startErr := parseTimestampToSeconds(h.StartTime)
+		end, endErr := parseTimestampToSeconds(h.EndTime)
+		if startErr == nil && endErr == nil {
+			segment = end - start
+		}
+		if err := checkDiskSpace(pathInfo.Dir, estimateSegmentBytes(v.FilePath, v.Duration, segment)); err != nil {
+			return err
+		}
+
 		args := []string{"-i", v.FilePath, "-ss", h.StartTime, "-to", h.EndTime, "-c", "copy", "-y", outputPath}
 
-		cmd := utils.CreateCommand(ffmpegPath, args...)
+		cmd := utils.CreateCommandContext(ctx, ffmpegPath, args...)
 
 		if output, err := cmd.CombinedOutput(); err != nil {
 			fmt.Printf("Failed to clip highlight %s: %v, output: %s\n", h.ID, err, string(output))
+			failed++
 			continue
 		}
 
 		// Update DB with file path
 		_ = m.UpdateHighlightFilePath(h.ID, outputPath)
+		progress(float64(i+1) / float64(len(highlights)) * 100)
 	}
 
 	// Notify frontend again with updated file paths
 	// Re-fetch to get file paths
 	updatedHighlights, _ := m.GetHighlights(videoID)
 
-	wailsRuntime.EventsEmit(m.ctx, "video:ai_status", map[string]interface{}{
+	m.bus.Publish(events.VideoAIStatus, map[string]interface{}{
 		"id":         v.ID,
 		"status":     v.Status,
 		"summary":    v.Summary,
@@ -264,4 +274,9 @@ func (m *Manager) ClipHighlights(videoID string, highlights []schema.VideoHighli
 		"tags":       v.TagsList, // Use TagsList for frontend
 		"highlights": updatedHighlights,
 	})
+
+	if failed > 0 {
+		return fmt.Errorf("failed to clip %d of %d highlights", failed, len(highlights))
+	}
+	return nil
 }
@@ -0,0 +1,99 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"Kairo/internal/ai"
+	"Kairo/internal/db/schema"
+	"Kairo/internal/jobs"
+)
+
+const (
+	JobTypeSubtitleGenerate = "subtitle_generate"
+	JobTypeVideoAnalyze     = "video_analyze"
+	JobTypeHighlightClip    = "highlight_clip"
+	JobTypePipelineStep     = "pipeline_step"
+
+	// JobTypeSubtitleFetch jobs were queued before recipes existed, they
+	// now start the pipeline of their video
+	JobTypeSubtitleFetch = "subtitle_fetch"
+)
+
+func (m *Manager) registerJobs() {
+	if m.jobs == nil {
+		return
+	}
+	m.jobs.Register(JobTypeSubtitleGenerate, 1, m.runSubtitleGenerateJob)
+	m.jobs.Register(JobTypeVideoAnalyze, 1, m.runVideoAnalyzeJob)
+	m.jobs.Register(JobTypeHighlightClip, 1, m.runHighlightClipJob)
+	m.jobs.Register(JobTypePipelineStep, 2, m.runPipelineStepJob)
+	m.jobs.Register(JobTypeSubtitleFetch, 1, m.runSubtitleFetchJob)
+}
+
+func (m *Manager) enqueueJob(jobType, refID string, payload interface{}) error {
+	if m.jobs == nil {
+		return nil
+	}
+	if _, err := m.jobs.Enqueue(jobType, refID, payload); err != nil {
+		log.Printf("[VideoJobs] failed to enqueue %s for %s: %v", jobType, refID, err)
+		return err
+	}
+	return nil
+}
+
+func (m *Manager) enqueueSubtitleTask(task SubtitleTask) error {
+	return m.enqueueJob(JobTypeSubtitleGenerate, task.SubtitleID, task)
+}
+
+func (m *Manager) runSubtitleGenerateJob(ctx context.Context, job *schema.Job, progress func(float64)) error {
+	var task SubtitleTask
+	if err := jobs.DecodePayload(job, &task); err != nil {
+		return jobs.Permanent(err)
+	}
+	err := m.handleSubtitleTask(ctx, task, progress)
+	// The step waits for the last attempt, a retry may still succeed
+	if task.PipelineStepID != "" && (err == nil || ctx.Err() != nil || job.Attempts+1 >= job.MaxAttempts) {
+		m.resumePipeline(task.PipelineStepID, err)
+	}
+	return err
+}
+
+func (m *Manager) runVideoAnalyzeJob(ctx context.Context, job *schema.Job, progress func(float64)) error {
+	highlights, err := m.runAnalysis(ctx, job.RefID, "", progress)
+	if errors.Is(err, ai.ErrAIDisabled) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+	// Clip highlights in the background
+	if len(highlights) > 0 {
+		_ = m.enqueueJob(JobTypeHighlightClip, job.RefID, nil)
+	}
+	return nil
+}
+
+func (m *Manager) runHighlightClipJob(ctx context.Context, job *schema.Job, progress func(float64)) error {
+	return m.ClipHighlights(ctx, job.RefID, progress)
+}
+
+func (m *Manager) runPipelineStepJob(ctx context.Context, job *schema.Job, progress func(float64)) error {
+	err := m.runPipelineStep(ctx, job.RefID, progress)
+	if errors.Is(err, ai.ErrAIDisabled) || errors.Is(err, errPipelineStepInvalid) {
+		return jobs.Permanent(err)
+	}
+	return err
+}
+
+func (m *Manager) runSubtitleFetchJob(ctx context.Context, job *schema.Job, progress func(float64)) error {
+	if m.hasPipeline(job.RefID) {
+		return nil
+	}
+	v, err := m.GetVideoById(job.RefID)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+	return m.startPipeline(v)
+}
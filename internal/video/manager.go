@@ -10,37 +10,50 @@ import (
 	"Kairo/internal/db/dal"
 	"Kairo/internal/db/schema"
 	"Kairo/internal/deps"
+	"Kairo/internal/events"
+	"Kairo/internal/jobs"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type Manager struct {
-	ctx           context.Context
-	db            *gorm.DB
-	aiService     *ai.Manager
-	deps          *deps.Manager
-	subtitleQueue chan SubtitleTask
-	analysisQueue chan string
-	videoDAL      *dal.VideoDAL
-	subtitleDAL   *dal.VideoSubtitleDAL
-	categoryDAL   *dal.CategoryDAL
-	highlightDAL  *dal.VideoHighlightDAL
+	ctx          context.Context
+	db           *gorm.DB
+	aiService    *ai.Manager
+	deps         *deps.Manager
+	bus          *events.Bus
+	jobs         *jobs.Queue
+	videoDAL     *dal.VideoDAL
+	subtitleDAL  *dal.VideoSubtitleDAL
+	categoryDAL  *dal.CategoryDAL
+	highlightDAL *dal.VideoHighlightDAL
+	recipeDAL    *dal.RecipeDAL
+	pipelineDAL  *dal.VideoPipelineDAL
+
+	// PublishHighlights queues clipped highlights on a publish account for
+	// the publish step of a recipe and returns how many tasks were created.
+	PublishHighlights func(accountID string, highlights []schema.VideoHighlight, tags string) (int, error)
 }
 
-func NewManager(ctx context.Context, db *gorm.DB, d *deps.Manager) *Manager {
+func NewManager(ctx context.Context, db *gorm.DB, d *deps.Manager, bus *events.Bus, queue *jobs.Queue) *Manager {
 	m := &Manager{
 		ctx:       ctx,
 		db:        db,
-		aiService: ai.NewManager(ctx),
+		aiService: ai.NewManager(ctx, db, d),
 		deps:      d,
+		bus:       bus,
+		jobs:      queue,
 	}
 	if db != nil {
 		m.videoDAL = dal.NewVideoDAL(db)
 		m.subtitleDAL = dal.NewVideoSubtitleDAL(db)
 		m.categoryDAL = dal.NewCategoryDAL(db)
 		m.highlightDAL = dal.NewVideoHighlightDAL(db)
+		m.recipeDAL = dal.NewRecipeDAL(db)
+		m.pipelineDAL = dal.NewVideoPipelineDAL(db)
 	}
+	m.registerJobs()
 	m.InitSubtitleQueue()
 	m.InitAnalyzeQueue()
 	return m
@@ -59,20 +72,25 @@ func (m *Manager) CreateFromTask(t *schema.Task) error {
 	}
 
 	v := &schema.Video{
-		ID:         uuid.New().String(),
-		TaskID:     t.ID,
-		Title:      t.Title,
-		URL:        t.URL,
-		FilePath:   t.FilePath,
-		Thumbnail:  t.Thumbnail,
-		Size:       t.TotalBytes,
-		Format:     t.Format,
-		Resolution: t.Quality,
-		CreatedAt:  time.Now().Unix(),
-		UpdatedAt:  time.Now().Unix(),
-		CategoryID: t.CategoryID,
-		Status:     "none",
+		ID:          uuid.New().String(),
+		TaskID:      t.ID,
+		Title:       t.Title,
+		URL:         t.URL,
+		FilePath:    t.FilePath,
+		Thumbnail:   t.Thumbnail,
+		Size:        t.TotalBytes,
+		Format:      t.Format,
+		Resolution:  t.Quality,
+		CreatedAt:   time.Now().Unix(),
+		UpdatedAt:   time.Now().Unix(),
+		CategoryID:  t.CategoryID,
+		Status:      "none",
+		Description: t.Description,
+		FeedID:      t.FeedID,
+		FeedItemID:  t.FeedItemID,
+		SourceTags:  mergeTags(t.Tags),
 	}
+	v.Tags = v.SourceTags
 
 	if v.Duration <= 0 && v.FilePath != "" {
 		log.Printf("[CreateFromTask] get duration from file: %s", v.FilePath)
@@ -82,9 +100,11 @@ func (m *Manager) CreateFromTask(t *schema.Task) error {
 		log.Printf("[CreateFromTask] duration: %f", v.Duration)
 	}
 
-	err = m.videoDAL.Save(m.ctx, v)
-	if err == nil {
-		go m.FetchSubtitles(v.ID)
+	if err := m.videoDAL.Save(m.ctx, v); err != nil {
+		return err
+	}
+	if err := m.startPipeline(v); err != nil {
+		log.Printf("[CreateFromTask] failed to start pipeline for video %s: %v", v.ID, err)
 	}
-	return err
+	return nil
 }
@@ -0,0 +1,324 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"Kairo/internal/config"
+	"Kairo/internal/db/schema"
+	"Kairo/internal/events"
+
+	"github.com/google/uuid"
+)
+
+var (
+	errPipelineStepInvalid = errors.New("invalid pipeline step")
+	errPipelineStepSkipped = errors.New("step skipped")
+	// errPipelineStepWaiting means the step handed its work to another job,
+	// which resumes the pipeline when it finishes
+	errPipelineStepWaiting = errors.New("step waiting")
+)
+
+// defaultRecipe is used for categories without an enabled recipe and matches
+// the processing that ran before recipes existed.
+var defaultRecipe = []schema.RecipeStep{
+	{Type: schema.RecipeStepSubtitles},
+	{Type: schema.RecipeStepAnalyze},
+	{Type: schema.RecipeStepClip},
+}
+
+func (m *Manager) recipeFor(categoryID string) []schema.RecipeStep {
+	if m.recipeDAL == nil || strings.TrimSpace(categoryID) == "" {
+		return defaultRecipe
+	}
+	recipe, err := m.recipeDAL.GetByCategory(m.ctx, categoryID)
+	if err != nil || !recipe.IsEnabled || len(recipe.StepList) == 0 {
+		return defaultRecipe
+	}
+	return recipe.StepList
+}
+
+// startPipeline records the recipe steps of a new video and schedules the
+// first one. Each step schedules the next when it succeeds.
+func (m *Manager) startPipeline(v *schema.Video) error {
+	if m.pipelineDAL == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	recipe := m.recipeFor(v.CategoryID)
+	steps := make([]schema.VideoPipelineStep, 0, len(recipe))
+	for i, step := range recipe {
+		options, err := json.Marshal(step)
+		if err != nil {
+			return err
+		}
+		steps = append(steps, schema.VideoPipelineStep{
+			ID:       uuid.New().String(),
+			VideoID:  v.ID,
+			Position: i,
+			Type:     step.Type,
+			Options:  string(options),
+			Status:   schema.PipelineStepPending,
+		})
+	}
+	if err := m.pipelineDAL.CreateBatch(m.ctx, steps); err != nil {
+		return err
+	}
+	m.publishPipeline(v.ID)
+	return m.enqueueJob(JobTypePipelineStep, steps[0].ID, nil)
+}
+
+func (m *Manager) GetVideoPipeline(videoID string) ([]schema.VideoPipelineStep, error) {
+	if m.pipelineDAL == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return m.pipelineDAL.ListByVideo(m.ctx, videoID)
+}
+
+func (m *Manager) hasPipeline(videoID string) bool {
+	if m.pipelineDAL == nil {
+		return false
+	}
+	exists, err := m.pipelineDAL.ExistsForVideo(m.ctx, videoID)
+	return err == nil && exists
+}
+
+func (m *Manager) runPipelineStep(ctx context.Context, stepID string, progress func(float64)) error {
+	if m.pipelineDAL == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	step, err := m.pipelineDAL.GetByID(m.ctx, stepID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPipelineStepInvalid, err)
+	}
+	var options schema.RecipeStep
+	if err := json.Unmarshal([]byte(step.Options), &options); err != nil {
+		return fmt.Errorf("%w: %v", errPipelineStepInvalid, err)
+	}
+	v, err := m.GetVideoById(step.VideoID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPipelineStepInvalid, err)
+	}
+
+	step.Status = schema.PipelineStepRunning
+	step.Error = ""
+	step.StartedAt = time.Now().UnixMilli()
+	step.FinishedAt = 0
+	m.savePipelineStep(step)
+
+	err = m.execPipelineStep(ctx, v, step.ID, options, progress)
+	if errors.Is(err, errPipelineStepWaiting) {
+		return nil
+	}
+	return m.finishPipelineStep(step, err)
+}
+
+// finishPipelineStep records the outcome of a step and schedules the next
+// pending one when the step did not fail.
+func (m *Manager) finishPipelineStep(step *schema.VideoPipelineStep, err error) error {
+	step.FinishedAt = time.Now().UnixMilli()
+	switch {
+	case errors.Is(err, errPipelineStepSkipped):
+		step.Status = schema.PipelineStepSkipped
+		step.Error = err.Error()
+	case err != nil:
+		step.Status = schema.PipelineStepFailed
+		step.Error = err.Error()
+		m.savePipelineStep(step)
+		return err
+	default:
+		step.Status = schema.PipelineStepCompleted
+	}
+	m.savePipelineStep(step)
+
+	steps, err := m.pipelineDAL.ListByVideo(m.ctx, step.VideoID)
+	if err != nil {
+		return err
+	}
+	for _, next := range steps {
+		if next.Position > step.Position && next.Status == schema.PipelineStepPending {
+			return m.enqueueJob(JobTypePipelineStep, next.ID, nil)
+		}
+	}
+	return nil
+}
+
+// resumePipeline finishes a step that waited on a subtitle_generate job.
+func (m *Manager) resumePipeline(stepID string, err error) {
+	if m.pipelineDAL == nil {
+		return
+	}
+	step, getErr := m.pipelineDAL.GetByID(m.ctx, stepID)
+	if getErr != nil || step.Status != schema.PipelineStepRunning {
+		return
+	}
+	if err := m.finishPipelineStep(step, err); err != nil {
+		log.Printf("[Pipeline] failed to resume after step %s: %v", stepID, err)
+	}
+}
+
+func (m *Manager) execPipelineStep(ctx context.Context, v *schema.Video, stepID string, step schema.RecipeStep, progress func(float64)) error {
+	switch step.Type {
+	case schema.RecipeStepSubtitles:
+		return m.subtitlesForPipeline(ctx, v, stepID, step.SkipASR)
+	case schema.RecipeStepTranslate:
+		return m.translateForPipeline(ctx, v, step.Language)
+	case schema.RecipeStepAnalyze:
+		if !config.GetSettings().AI.Enabled {
+			return fmt.Errorf("%w: AI is disabled in settings", errPipelineStepSkipped)
+		}
+		if _, err := m.getReadySubtitlePath(v.ID); err != nil {
+			return fmt.Errorf("%w: %v", errPipelineStepSkipped, err)
+		}
+		_, err := m.runAnalysis(ctx, v.ID, step.Prompt, progress)
+		return err
+	case schema.RecipeStepClip:
+		if isAudioFile(v.FilePath) {
+			return fmt.Errorf("%w: audio has no video to clip", errPipelineStepSkipped)
+		}
+		return m.clipHighlights(ctx, v.ID, step.MinScore, progress)
+	case schema.RecipeStepPublish:
+		return m.publishForPipeline(v, step)
+	}
+	return fmt.Errorf("%w: unknown step type %q", errPipelineStepInvalid, step.Type)
+}
+
+// subtitlesForPipeline downloads the subtitles published with the video.
+// Without any, speech recognition runs as a subtitle_generate job so it does
+// not hold a pipeline worker, the step waits for that job.
+func (m *Manager) subtitlesForPipeline(ctx context.Context, v *schema.Video, stepID string, skipASR bool) error {
+	if v.URL != "" {
+		if err := m.fetchSubtitles(ctx, v, false); err != nil {
+			return err
+		}
+	}
+	if _, err := m.getReadySubtitlePath(v.ID); err == nil {
+		return nil
+	}
+	if skipASR {
+		return fmt.Errorf("%w: no subtitles published", errPipelineStepSkipped)
+	}
+	if !config.GetSettings().WhisperAI.Enabled {
+		return fmt.Errorf("%w: no subtitles published and Whisper is disabled in settings", errPipelineStepSkipped)
+	}
+
+	now := time.Now().UnixMilli()
+	target := &schema.VideoSubtitle{
+		ID:        uuid.New().String(),
+		VideoID:   v.ID,
+		Status:    schema.SubtitleStatusPending,
+		Source:    schema.SubtitleSourceASR,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := m.subtitleDAL.Create(m.ctx, target); err != nil {
+		return err
+	}
+	if err := m.enqueueSubtitleTask(SubtitleTask{
+		Type:           SubtitleTaskTypeASR,
+		SubtitleID:     target.ID,
+		VideoID:        v.ID,
+		PipelineStepID: stepID,
+	}); err != nil {
+		return err
+	}
+	return errPipelineStepWaiting
+}
+
+// translateForPipeline translates the best available subtitle of the video,
+// reusing an earlier translation record for the same language.
+func (m *Manager) translateForPipeline(ctx context.Context, v *schema.Video, language string) error {
+	language = strings.TrimSpace(language)
+	subs, err := m.GetVideoSubtitles(v.ID)
+	if err != nil {
+		return err
+	}
+	var target *schema.VideoSubtitle
+	for i := range subs {
+		if schema.SubtitleSource(subs[i].Source) != schema.SubtitleSourceTranslation || subs[i].Language != language {
+			continue
+		}
+		if schema.SubtitleStatus(subs[i].Status) == schema.SubtitleStatusSuccess {
+			return nil
+		}
+		target = &subs[i]
+	}
+
+	source, err := m.findBestSourceSubtitle(v.ID)
+	if err != nil || source == nil {
+		return fmt.Errorf("%w: no subtitle to translate", errPipelineStepSkipped)
+	}
+
+	if target == nil {
+		now := time.Now().UnixMilli()
+		target = &schema.VideoSubtitle{
+			ID:        uuid.New().String(),
+			VideoID:   v.ID,
+			Language:  language,
+			Status:    schema.SubtitleStatusPending,
+			Source:    schema.SubtitleSourceTranslation,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := m.subtitleDAL.Create(m.ctx, target); err != nil {
+			return err
+		}
+	}
+
+	return m.handleSubtitleTask(ctx, SubtitleTask{
+		Type:             SubtitleTaskTypeTranslate,
+		SubtitleID:       target.ID,
+		VideoID:          v.ID,
+		SourceSubtitleID: source.ID,
+		TargetLanguage:   language,
+	}, nil)
+}
+
+func (m *Manager) publishForPipeline(v *schema.Video, step schema.RecipeStep) error {
+	if m.PublishHighlights == nil {
+		return fmt.Errorf("%w: publishing is not available", errPipelineStepInvalid)
+	}
+	highlights, err := m.GetHighlights(v.ID)
+	if err != nil {
+		return err
+	}
+	selected := make([]schema.VideoHighlight, 0, len(highlights))
+	for _, h := range highlights {
+		if strings.TrimSpace(h.FilePath) == "" || h.Score < step.MinScore {
+			continue
+		}
+		selected = append(selected, h)
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("%w: no clipped highlights to publish", errPipelineStepSkipped)
+	}
+
+	created, err := m.PublishHighlights(step.AccountID, selected, strings.Join(v.TagsList, ","))
+	if err != nil {
+		return err
+	}
+	log.Printf("[Pipeline] queued %d highlights of video %s for account %s", created, v.ID, step.AccountID)
+	return nil
+}
+
+func (m *Manager) savePipelineStep(step *schema.VideoPipelineStep) {
+	if err := m.pipelineDAL.Save(m.ctx, step); err != nil {
+		log.Printf("[Pipeline] failed to save step %s: %v", step.ID, err)
+	}
+	m.publishPipeline(step.VideoID)
+}
+
+func (m *Manager) publishPipeline(videoID string) {
+	steps, err := m.pipelineDAL.ListByVideo(m.ctx, videoID)
+	if err != nil {
+		return
+	}
+	m.bus.Publish(events.VideoPipeline, map[string]interface{}{
+		"id":    videoID,
+		"steps": steps,
+	})
+}
@@ -1,6 +1,7 @@
 package video
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -12,6 +13,7 @@ import (
 	"Kairo/internal/ai"
 	"Kairo/internal/config"
 	"Kairo/internal/db/schema"
+	"Kairo/internal/deps"
 	"Kairo/internal/utils"
 
 	"github.com/google/uuid"
@@ -29,8 +31,18 @@ func (m *Manager) FetchSubtitles(id string) error {
 	if err != nil {
 		return err
 	}
+	if err := m.fetchSubtitles(m.ctx, v, true); err != nil {
+		return err
+	}
+	m.enqueueAnalyze(v.ID)
+	return nil
+}
+
+// fetchSubtitles downloads the subtitles published with the video and, when
+// there are none and allowASR is set, generates them by speech recognition.
+func (m *Manager) fetchSubtitles(ctx context.Context, v *schema.Video, allowASR bool) error {
 	if v.URL == "" {
-		return fmt.Errorf("no URL found for video %s", id)
+		return fmt.Errorf("no URL found for video %s", v.ID)
 	}
 
 	ytDlpPath, err := m.deps.GetYtDlpPath()
@@ -55,18 +67,10 @@ func (m *Manager) FetchSubtitles(id string) error {
 		args = append(args, "--sub-langs", "zh-Hans,-danmaku")
 	}
 
-	if proxy := config.GetProxyUrl(); proxy != "" {
-		args = append(args, "--proxy", proxy)
-	}
-	if ua := config.GetUserAgent(); ua != "" {
-		args = append(args, "--user-agent", ua)
-	}
-	if cookieArgs := config.GetCookieArgs(); len(cookieArgs) > 0 {
-		args = append(args, cookieArgs...)
-	}
+	args = append(args, config.ResolveNetworkOptions(v.URL).Args()...)
 
 	args = append(args, v.URL)
-	cmd := utils.CreateCommandContext(m.ctx, ytDlpPath, args...)
+	cmd := utils.CreateCommandContext(ctx, ytDlpPath, args...)
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -79,7 +83,10 @@ func (m *Manager) FetchSubtitles(id string) error {
 	}
 
 	if len(entries) == 0 {
-		outputPath, language, asrErr := m.GenerateSubtitlesByASR(v)
+		if !allowASR {
+			return nil
+		}
+		outputPath, language, asrErr := m.GenerateSubtitlesByASR(ctx, v, nil)
 		if asrErr != nil {
 			return asrErr
 		}
@@ -91,19 +98,21 @@ func (m *Manager) FetchSubtitles(id string) error {
 		if _, err := m.addSubtitleRecord(v.ID, outputPath, language, schema.SubtitleStatusSuccess, schema.SubtitleSourceASR); err != nil {
 			return err
 		}
-
-		m.enqueueAnalyze(v.ID)
-		return nil
-	}
-
-	if len(entries) > 0 {
-		m.enqueueAnalyze(v.ID)
 	}
 
-	return err
+	// Some subtitles were written even if yt-dlp reported an error
+	return nil
 }
 
-func (m *Manager) GenerateSubtitlesByASR(v *schema.Video) (string, string, error) {
+// asrBytesPerSecond is the size of the mono mp3 handed to ASR at the default
+// 128 kbps of libmp3lame.
+const asrBytesPerSecond = 16 * 1024
+
+// asrWavBytesPerSecond is the size of the 16 kHz 16-bit mono WAV whisper.cpp
+// reads, it does not decode mp3 in every build.
+const asrWavBytesPerSecond = 32 * 1000
+
+func (m *Manager) GenerateSubtitlesByASR(ctx context.Context, v *schema.Video, progress func(float64)) (string, string, error) {
 	if !config.GetSettings().WhisperAI.Enabled {
 		log.Printf("[GenerateSubtitlesByASR] Whisper disabled, skip generating subtitles")
 		return "", "", nil
@@ -112,14 +121,26 @@ func (m *Manager) GenerateSubtitlesByASR(v *schema.Video) (string, string, error
 	log.Printf("[GenerateSubtitlesByASR] start generate subtitles by ASR for video %s,", v.ID)
 	inputPath := v.FilePath
 	tempPath := ""
-	if strings.ToLower(filepath.Ext(v.FilePath)) != ".mp3" {
+	ext, codec, rate := ".mp3", "libmp3lame", int64(asrBytesPerSecond)
+	if config.GetSettings().WhisperAI.Provider == deps.WhisperEngineCpp {
+		ext, codec, rate = ".wav", "pcm_s16le", asrWavBytesPerSecond
+	}
+	if strings.ToLower(filepath.Ext(v.FilePath)) != ext {
 		ffmpegPath, err := m.deps.GetFFmpegPath()
 		if err != nil {
 			log.Printf("[GenerateSubtitlesByASR] error get ffmpeg path: %v", err)
 			return "", "", fmt.Errorf("asr failed: %v", err)
 		}
 		tempDir := filepath.Dir(v.FilePath)
-		tempFile, err := os.CreateTemp(tempDir, "whisper-*.mp3")
+		need := int64(v.Duration * float64(rate))
+		if v.Duration <= 0 {
+			need = estimateSegmentBytes(v.FilePath, 0, 0)
+		}
+		if err := checkDiskSpace(tempDir, need); err != nil {
+			log.Printf("[GenerateSubtitlesByASR] %v", err)
+			return "", "", err
+		}
+		tempFile, err := os.CreateTemp(tempDir, "whisper-*"+ext)
 		if err != nil {
 			log.Printf("[GenerateSubtitlesByASR] error create temp file: %v", err)
 			return "", "", err
@@ -129,10 +150,10 @@ func (m *Manager) GenerateSubtitlesByASR(v *schema.Video) (string, string, error
 			log.Printf("[GenerateSubtitlesByASR] error close temp file: %v", err)
 			return "", "", err
 		}
-		args := []string{"-i", v.FilePath, "-vn", "-ac", "1", "-ar", "16000", "-c:a", "libmp3lame", "-y", tempPath}
-		cmd := utils.CreateCommand(ffmpegPath, args...)
+		args := []string{"-i", v.FilePath, "-vn", "-ac", "1", "-ar", "16000", "-c:a", codec, "-y", tempPath}
+		cmd := utils.CreateCommandContext(ctx, ffmpegPath, args...)
 		if output, err := cmd.CombinedOutput(); err != nil {
-			log.Printf("[GenerateSubtitlesByASR] error convert to mp3: %v, output: %s", err, string(output))
+			log.Printf("[GenerateSubtitlesByASR] error convert audio: %v, output: %s", err, string(output))
 			_ = os.Remove(tempPath)
 			return "", "", fmt.Errorf("ffmpeg error: %v, output: %s", err, string(output))
 		}
@@ -141,7 +162,7 @@ func (m *Manager) GenerateSubtitlesByASR(v *schema.Video) (string, string, error
 	if tempPath != "" {
 		defer os.Remove(tempPath)
 	}
-	content, err := m.aiService.TranscribeWhisper(inputPath)
+	content, err := m.aiService.TranscribeWhisper(ctx, v.ID, inputPath, progress)
 	if err != nil {
 		log.Printf("[GenerateSubtitlesByASR] error transcribe whisper: %v", err)
 		if errors.Is(err, ai.ErrWhisperDisabled) {
@@ -250,12 +271,14 @@ func (m *Manager) TranslateSubtitle(input schema.TranslateSubtitleInput) (*schem
 		return nil, err
 	}
 
-	m.subtitleQueue <- SubtitleTask{
+	if err := m.enqueueSubtitleTask(SubtitleTask{
 		Type:             SubtitleTaskTypeTranslate,
 		SubtitleID:       newSub.ID,
 		VideoID:          newSub.VideoID,
 		SourceSubtitleID: input.SubtitleID,
 		TargetLanguage:   input.TargetLanguage,
+	}); err != nil {
+		return nil, err
 	}
 
 	return newSub, nil
@@ -346,7 +369,9 @@ func (m *Manager) RegenerateSubtitle(subtitleID string) (*schema.VideoSubtitle,
 		subtitleTask.SourceSubtitleID = bestSource.ID
 	}
 
-	m.subtitleQueue <- subtitleTask
+	if err := m.enqueueSubtitleTask(subtitleTask); err != nil {
+		return nil, err
+	}
 
 	return sub, nil
 }
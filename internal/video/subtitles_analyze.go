@@ -9,8 +9,15 @@ import (
 	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
+
+	"Kairo/internal/ai"
 )
 
+// transcriptWindowOverlap repeats the last seconds of a window at the
+// start of the next one, so highlights crossing the boundary are seen whole.
+const transcriptWindowOverlap = 30.0
+
 type subtitleSegment struct {
 	Start float64
 	End   float64
@@ -117,6 +124,40 @@ func buildSubtitleText(segments []subtitleSegment) string {
 	return b.String()
 }
 
+// buildTranscriptWindows splits the subtitles into consecutive time
+// windows of at most maxChars characters of subtitle text each.
+func buildTranscriptWindows(segments []subtitleSegment, maxChars int) []ai.TranscriptWindow {
+	var windows []ai.TranscriptWindow
+	start := 0
+	for start < len(segments) {
+		end := start
+		size := 0
+		for end < len(segments) {
+			// Two timestamps and the arrow precede the text of every cue
+			n := utf8.RuneCountInString(segments[end].Text) + 31
+			if size > 0 && size+n > maxChars {
+				break
+			}
+			size += n
+			end++
+		}
+		windows = append(windows, ai.TranscriptWindow{
+			Start: segments[start].Start,
+			End:   segments[end-1].End,
+			Text:  buildSubtitleText(segments[start:end]),
+		})
+		if end == len(segments) {
+			break
+		}
+		next := end
+		for next-1 > start && segments[end-1].End-segments[next-1].Start <= transcriptWindowOverlap {
+			next--
+		}
+		start = next
+	}
+	return windows
+}
+
 func isSubtitleIndex(line string) bool {
 	if line == "" {
 		return false
@@ -1,6 +1,7 @@
 package video
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -23,12 +24,11 @@ type SubtitleTask struct {
 	VideoID          string
 	SourceSubtitleID string // For translation: The ID of the source subtitle
 	TargetLanguage   string // For translation
+	PipelineStepID   string // Recipe step resumed once the task finishes
 }
 
 func (m *Manager) InitSubtitleQueue() {
-	m.subtitleQueue = make(chan SubtitleTask, 100)
 	go m.restorePendingSubtitles()
-	go m.processSubtitleQueue()
 }
 
 func (m *Manager) restorePendingSubtitles() {
@@ -68,11 +68,13 @@ func (m *Manager) restorePendingSubtitles() {
 				continue
 			}
 			task.SourceSubtitleID = bestSource.ID
-			m.subtitleQueue <- task
-			count++
 		} else if schema.SubtitleSource(subtitle.Source) == schema.SubtitleSourceASR {
 			task.Type = SubtitleTaskTypeASR
-			m.subtitleQueue <- task
+		} else {
+			continue
+		}
+		// Enqueue is a no-op for subtitles whose job survived the restart
+		if err := m.enqueueSubtitleTask(task); err == nil {
 			count++
 		}
 	}
@@ -115,30 +117,24 @@ func (m *Manager) findBestSourceSubtitle(videoID string) (*schema.VideoSubtitle,
 	return nil, fmt.Errorf("no suitable source found")
 }
 
-func (m *Manager) processSubtitleQueue() {
-	for task := range m.subtitleQueue {
-		m.handleSubtitleTask(task)
-	}
-}
-
-func (m *Manager) handleSubtitleTask(task SubtitleTask) {
+func (m *Manager) handleSubtitleTask(ctx context.Context, task SubtitleTask, progress func(float64)) error {
 	log.Printf("[SubtitleQueue] processing task: %+v", task)
 
 	// Update status to Generating
 	if m.subtitleDAL == nil {
-		return
+		return fmt.Errorf("database not initialized")
 	}
 	if err := m.subtitleDAL.UpdateStatus(m.ctx, task.SubtitleID, int(schema.SubtitleStatusGenerating)); err != nil {
 		log.Printf("[SubtitleQueue] failed to update status to generating: %v", err)
-		return
+		return err
 	}
 
 	var resultErr error
 
 	if task.Type == SubtitleTaskTypeASR {
-		resultErr = m.processASRTask(task)
+		resultErr = m.processASRTask(ctx, task, progress)
 	} else if task.Type == SubtitleTaskTypeTranslate {
-		resultErr = m.processTranslateTask(task)
+		resultErr = m.processTranslateTask(ctx, task)
 	}
 
 	status := schema.SubtitleStatusSuccess
@@ -149,14 +145,15 @@ func (m *Manager) handleSubtitleTask(task SubtitleTask) {
 	if err := m.subtitleDAL.UpdateStatus(m.ctx, task.SubtitleID, int(status)); err != nil {
 		log.Printf("[SubtitleQueue] failed to update status to %v: %v", status, err)
 	}
+	return resultErr
 }
 
-func (m *Manager) processASRTask(task SubtitleTask) error {
+func (m *Manager) processASRTask(ctx context.Context, task SubtitleTask, progress func(float64)) error {
 	video, err := m.GetVideoById(task.VideoID)
 	if err != nil {
 		return err
 	}
-	outputPath, language, err := m.GenerateSubtitlesByASR(video)
+	outputPath, language, err := m.GenerateSubtitlesByASR(ctx, video, progress)
 	if err != nil {
 		return err
 	}
@@ -173,7 +170,7 @@ func (m *Manager) processASRTask(task SubtitleTask) error {
 	return m.subtitleDAL.Update(m.ctx, sub)
 }
 
-func (m *Manager) processTranslateTask(task SubtitleTask) error {
+func (m *Manager) processTranslateTask(ctx context.Context, task SubtitleTask) error {
 	// 1. Get source subtitle
 	sourceSub, err := m.getSubtitleByID(task.SourceSubtitleID)
 	if err != nil {
@@ -194,7 +191,7 @@ func (m *Manager) processTranslateTask(task SubtitleTask) error {
 	for _, seg := range segments {
 		texts = append(texts, seg.Text)
 	}
-	translations, err := m.aiService.TranslateSegments(task.TargetLanguage, texts)
+	translations, err := m.aiService.TranslateSegments(ctx, task.VideoID, task.TargetLanguage, texts)
 	if err != nil {
 		return fmt.Errorf("translation failed: %v", err)
 	}
@@ -1,8 +1,12 @@
 package video
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
+
+	"Kairo/internal/config"
+	"Kairo/internal/utils"
 )
 
 type videoPathInfo struct {
@@ -20,7 +24,64 @@ func buildVideoPathInfo(filePath string) videoPathInfo {
 	}
 }
 
+// checkDiskSpace fails when writing need bytes to dir would undercut the
+// configured free space reserve.
+func checkDiskSpace(dir string, need int64) error {
+	guard := config.GetDiskGuard()
+	if !guard.Enabled {
+		return nil
+	}
+	return utils.EnsureFreeSpace(dir, need, guard.MinFreeMB<<20)
+}
+
+// estimateSegmentBytes estimates the size of a stream copied segment of the
+// video file, the whole file when the durations are unknown.
+func estimateSegmentBytes(filePath string, duration, segment float64) int64 {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return 0
+	}
+	if duration <= 0 || segment <= 0 || segment >= duration {
+		return info.Size()
+	}
+	return int64(float64(info.Size()) * segment / duration)
+}
+
+var audioExtensions = map[string]struct{}{
+	".mp3": {}, ".m4a": {}, ".aac": {}, ".ogg": {}, ".oga": {}, ".opus": {},
+	".flac": {}, ".wav": {},
+}
+
+// isAudioFile reports whether the library entry is an audio-only file such
+// as a podcast episode.
+func isAudioFile(filePath string) bool {
+	_, ok := audioExtensions[strings.ToLower(filepath.Ext(filePath))]
+	return ok
+}
+
 func buildOutputTemplate(filePath string) string {
 	info := buildVideoPathInfo(filePath)
 	return filepath.Join(info.Dir, info.BaseName+".%(ext)s")
 }
+
+// mergeTags joins comma separated tag lists, dropping blanks and
+// case-insensitive duplicates while keeping first-seen order.
+func mergeTags(lists ...string) string {
+	seen := make(map[string]struct{})
+	var merged []string
+	for _, list := range lists {
+		for _, tag := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == '，' }) {
+			tag = strings.TrimSpace(tag)
+			if tag == "" {
+				continue
+			}
+			key := strings.ToLower(tag)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			merged = append(merged, tag)
+		}
+	}
+	return strings.Join(merged, ",")
+}
@@ -1,6 +1,7 @@
 package video
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -8,19 +9,20 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"Kairo/internal/ai"
 	"Kairo/internal/config"
 	"Kairo/internal/db/schema"
+	"Kairo/internal/events"
 	"Kairo/internal/utils"
 
 	"github.com/google/uuid"
-	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 	"gorm.io/gorm"
 )
 
 func (m *Manager) ListVideos(filter schema.VideoFilter) ([]schema.Video, error) {
-	return m.videoDAL.List(m.ctx, filter.Status, filter.Query)
+	return m.videoDAL.List(m.ctx, filter.Status, filter.Query, filter.FeedID)
 }
 
 func (m *Manager) GetVideoById(id string) (*schema.Video, error) {
@@ -59,6 +61,10 @@ func (m *Manager) DeleteVideo(id string, deleteFile bool) error {
 }
 
 func (m *Manager) UpdateVideoStatus(id, status, summary, evaluation string, tags string, highlights []schema.VideoHighlight) error {
+	// Tags inherited from the source feed survive AI re-analysis
+	if v, err := m.videoDAL.GetByID(m.ctx, id); err == nil && v.SourceTags != "" {
+		tags = mergeTags(v.SourceTags, tags)
+	}
 	if err := m.videoDAL.UpdateStatus(m.ctx, id, status, summary, evaluation, tags); err != nil {
 		return err
 	}
@@ -79,6 +85,7 @@ func (m *Manager) UpdateVideoStatus(id, status, summary, evaluation string, tags
 				EndTime:     h.EndTime,
 				Title:       h.Title,
 				Description: h.Description,
+				Score:       h.Score,
 				FilePath:    h.FilePath,
 				CreatedAt:   now,
 				UpdatedAt:   now,
@@ -86,7 +93,7 @@ func (m *Manager) UpdateVideoStatus(id, status, summary, evaluation string, tags
 			_ = m.highlightDAL.Replace(m.ctx, id, records)
 		}
 
-		wailsRuntime.EventsEmit(m.ctx, "video:ai_status", map[string]interface{}{
+		m.bus.Publish(events.VideoAIStatus, map[string]interface{}{
 			"id":         id,
 			"status":     status,
 			"summary":    summary,
@@ -99,6 +106,11 @@ func (m *Manager) UpdateVideoStatus(id, status, summary, evaluation string, tags
 	return nil
 }
 
+// GetAIUsage sums the AI calls of the last days by day and by feature.
+func (m *Manager) GetAIUsage(days int) (*schema.AIUsageReport, error) {
+	return m.aiService.GetUsage(days)
+}
+
 func (m *Manager) AnalyzeVideo(id string) error {
 	if !config.GetSettings().AI.Enabled {
 		return nil
@@ -109,8 +121,7 @@ func (m *Manager) AnalyzeVideo(id string) error {
 		return err
 	}
 
-	subtitlePath, err := m.getReadySubtitlePath(v.ID)
-	if err != nil {
+	if _, err := m.getReadySubtitlePath(v.ID); err != nil {
 		return err
 	}
 
@@ -118,80 +129,113 @@ func (m *Manager) AnalyzeVideo(id string) error {
 	// If re-analyzing, we clear previous results but keep metadata
 	m.UpdateVideoStatus(id, "processing", "", "", "", nil)
 
-	go func(subtitlePath string) {
-		var subtitlesContent string
-		var subtitleStats string
-		var energyCandidatesText string
-		var energyCandidates []energyCandidate
-		var subtitleSegments []subtitleSegment
-		if segments, err := parseSubtitleFile(subtitlePath); err == nil && len(segments) > 0 {
-			subtitleSegments = segments
-			subtitlesContent = buildSubtitleText(segments)
-			subtitleStats, energyCandidates = buildSubtitleAnalysis(segments, v.Duration)
-			energyCandidatesText = formatEnergyCandidates(energyCandidates)
-		} else if content, readErr := os.ReadFile(subtitlePath); readErr == nil {
-			subtitlesContent = string(content)
+	return m.enqueueJob(JobTypeVideoAnalyze, id, nil)
+}
+
+// runAnalysis performs the AI analysis of a video and stores the summary and
+// highlights. A non-empty prompt replaces the category prompt.
+func (m *Manager) runAnalysis(ctx context.Context, id string, prompt string, progress func(float64)) ([]schema.VideoHighlight, error) {
+	v, err := m.GetVideoById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	subtitlePath, err := m.getReadySubtitlePath(v.ID)
+	if err != nil {
+		m.UpdateVideoStatus(id, "failed", fmt.Sprintf("Error: %v", err), "", "", nil)
+		return nil, err
+	}
+
+	if v.Status != "processing" {
+		m.UpdateVideoStatus(id, "processing", "", "", "", nil)
+	}
+
+	var subtitlesContent string
+	var subtitleStats string
+	var energyCandidatesText string
+	var energyCandidates []energyCandidate
+	var subtitleSegments []subtitleSegment
+	var windows []ai.TranscriptWindow
+	chunkSize := ai.AnalysisChunkSize(config.GetSettings().AI)
+	if segments, err := parseSubtitleFile(subtitlePath); err == nil && len(segments) > 0 {
+		subtitleSegments = segments
+		subtitlesContent = buildSubtitleText(segments)
+		subtitleStats, energyCandidates = buildSubtitleAnalysis(segments, v.Duration)
+		energyCandidatesText = formatEnergyCandidates(energyCandidates)
+		// Long transcripts are analysed window by window
+		if utf8.RuneCountInString(subtitlesContent) > chunkSize {
+			windows = buildTranscriptWindows(segments, chunkSize)
 		}
-		if len(subtitlesContent) > 12000 {
-			head := subtitlesContent[:8000]
-			tail := subtitlesContent[len(subtitlesContent)-4000:]
+	} else if content, readErr := os.ReadFile(subtitlePath); readErr == nil {
+		subtitlesContent = string(content)
+		// Without timestamps there is nothing to window by, keep the
+		// beginning and the end
+		if runes := []rune(subtitlesContent); len(runes) > chunkSize {
+			head := string(runes[:chunkSize*2/3])
+			tail := string(runes[len(runes)-chunkSize/3:])
 			subtitlesContent = head + "\n...\n" + tail
 		}
+	}
 
-		meta := ai.VideoMetadata{
-			Title:            v.Title,
-			Description:      v.Description,
-			Subtitles:        subtitlesContent,
-			SubtitleStats:    subtitleStats,
-			EnergyCandidates: energyCandidatesText,
-			Uploader:         v.Uploader,
-			Duration:         utils.FormatDuration(v.Duration),
-			Resolution:       v.Resolution,
-			Format:           v.Format,
-			Size:             utils.FormatBytes(v.Size),
-			Date:             time.Unix(v.CreatedAt, 0).Format("2006-01-02"),
+	meta := ai.VideoMetadata{
+		VideoID:          v.ID,
+		Title:            v.Title,
+		Description:      v.Description,
+		Subtitles:        subtitlesContent,
+		SubtitleStats:    subtitleStats,
+		EnergyCandidates: energyCandidatesText,
+		Uploader:         v.Uploader,
+		Duration:         utils.FormatDuration(v.Duration),
+		Resolution:       v.Resolution,
+		Format:           v.Format,
+		Size:             utils.FormatBytes(v.Size),
+		Date:             time.Unix(v.CreatedAt, 0).Format("2006-01-02"),
+		Windows:          windows,
+	}
+	if progress != nil {
+		// The windows take most of the time, the final pass the rest
+		meta.Progress = func(done, total int) {
+			progress(float64(done) / float64(total+1) * 100)
 		}
+	}
 
-		categoryPrompt, _ := m.getCategoryPrompt(v.CategoryID)
-		result, err := m.aiService.Analyze(meta, categoryPrompt)
-
-		if err != nil {
-			if errors.Is(err, ai.ErrAIDisabled) {
-				m.UpdateVideoStatus(id, "failed", "AI is disabled in settings", "", "", nil)
-				return
-			}
-			fmt.Printf("AI Analysis failed: %v\n", err)
-			m.UpdateVideoStatus(id, "failed", fmt.Sprintf("Error: %v", err), "", "", nil)
-			return
-		}
+	if strings.TrimSpace(prompt) == "" {
+		prompt, _ = m.getCategoryPrompt(v.CategoryID)
+	}
+	result, err := m.aiService.Analyze(ctx, meta, prompt)
 
-		if len(result.Highlights) == 0 && len(energyCandidates) > 0 {
-			result.Highlights = buildFallbackHighlights(energyCandidates)
+	if err != nil {
+		if errors.Is(err, ai.ErrAIDisabled) {
+			m.UpdateVideoStatus(id, "failed", "AI is disabled in settings", "", "", nil)
+			return nil, err
 		}
-		result.Highlights = normalizeHighlights(result.Highlights, v.Duration, subtitleSegments, energyCandidates)
+		fmt.Printf("AI Analysis failed: %v\n", err)
+		m.UpdateVideoStatus(id, "failed", fmt.Sprintf("Error: %v", err), "", "", nil)
+		return nil, err
+	}
 
-		// Convert result highlights to model highlights
-		var highlights []schema.VideoHighlight
-		for _, h := range result.Highlights {
-			highlights = append(highlights, schema.VideoHighlight{
-				ID:          uuid.New().String(),
-				VideoID:     id,
-				StartTime:   h.Start,
-				EndTime:     h.End,
-				Title:       h.Title,
-				Description: h.Description,
-			})
-		}
+	if len(result.Highlights) == 0 && len(energyCandidates) > 0 {
+		result.Highlights = buildFallbackHighlights(energyCandidates)
+	}
+	result.Highlights = normalizeHighlights(result.Highlights, v.Duration, subtitleSegments, energyCandidates)
 
-		m.UpdateVideoStatus(id, "completed", result.Summary, result.Evaluation, result.Tags, highlights)
+	// Convert result highlights to model highlights
+	var highlights []schema.VideoHighlight
+	for _, h := range result.Highlights {
+		highlights = append(highlights, schema.VideoHighlight{
+			ID:          uuid.New().String(),
+			VideoID:     id,
+			StartTime:   h.Start,
+			EndTime:     h.End,
+			Title:       h.Title,
+			Description: h.Description,
+			Score:       h.Score,
+		})
+	}
 
-		// Async Clip Highlights
-		if len(highlights) > 0 {
-			go m.ClipHighlights(id, highlights)
-		}
-	}(subtitlePath)
+	m.UpdateVideoStatus(id, "completed", result.Summary, result.Evaluation, result.Tags, highlights)
 
-	return nil
+	return highlights, nil
 }
 
 func (m *Manager) HasVideoForTask(taskID, filePath string) (bool, error) {